@@ -21,9 +21,7 @@ function App() {
 
   const handleLogout = async () => {
     try {
-      const response = await axiosClient.post("/logout", {
-        user_id: auth.user_id,
-      });
+      const response = await axiosClient.post("/logout");
       console.log(response.data);
       setAuth(null);
       // localStorage.removeItem('user');
@@ -1,20 +1,34 @@
 import { useEffect, useState } from "react";
+import { Button } from "react-bootstrap";
 import axiosClient from "../../api/axiosConfig";
 import Movies from "../movies/Movies";
 
+const PAGE_LIMIT = 20;
+
 function Home({ updateMovieReview }) {
   const [movies, setMovies] = useState([]);
+  const [nextPageToken, setNextPageToken] = useState("");
   const [loading, setLoading] = useState(false);
+  const [loadingMore, setLoadingMore] = useState(false);
   const [msg, setMsg] = useState(null);
 
+  // GET /movies is paginated, `after` is the next_page_token of the previous page
+  const fetchPage = async (after = "") => {
+    const resp = await axiosClient.get("/movies", {
+      params: { limit: PAGE_LIMIT, ...(after && { after }) },
+    });
+    setNextPageToken(resp.data.next_page_token);
+    return resp.data.movies;
+  };
+
   useEffect(() => {
     const fetchMovies = async () => {
       setLoading(true);
       setMsg("");
       try {
-        const resp = await axiosClient.get("/movies");
-        setMovies(resp.data);
-        if (resp.data.length === 0) {
+        const firstPage = await fetchPage();
+        setMovies(firstPage);
+        if (firstPage.length === 0) {
           setMsg("There are currently no movies available!");
         }
       } catch (error) {
@@ -26,16 +40,41 @@ function Home({ updateMovieReview }) {
     };
     fetchMovies(); // ✅ THIS WAS MISSING
   }, []);
+
+  const loadMore = async () => {
+    setLoadingMore(true);
+    try {
+      const nextPage = await fetchPage(nextPageToken);
+      setMovies((prev) => [...prev, ...nextPage]);
+    } catch (error) {
+      console.log(`⚠️ Error fetching more movies: ${error}`);
+    } finally {
+      setLoadingMore(false);
+    }
+  };
+
   return (
     <>
       {loading ? (
         <h2>Loading... ⌛</h2>
       ) : (
-        <Movies
-          movies={movies}
-          message={msg}
-          updateMovieReview={updateMovieReview}
-        />
+        <>
+          <Movies
+            movies={movies}
+            message={msg}
+            updateMovieReview={updateMovieReview}
+          />
+          {nextPageToken && (
+            <div className="text-center my-4">
+              <Button
+                variant="outline-info"
+                disabled={loadingMore}
+                onClick={loadMore}>
+                {loadingMore ? "Loading... ⌛" : "Load more"}
+              </Button>
+            </div>
+          )}
+        </>
       )}
     </>
   );
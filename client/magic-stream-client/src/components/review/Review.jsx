@@ -80,7 +80,7 @@ const Review = () => {
             </div>
             <div className="col-12 col-md-6 d-flex align-items-stretch">
               <div className="w-100 shadow rounded p-4 bg-light">
-                {auth && auth.role === "ADMIN" ? (
+                {auth && ["ADMIN", "EDITOR"].includes(auth.role) ? (
                   <Form onSubmit={handleSubmit}>
                     <Form.Group
                       className="mb-3"
@@ -0,0 +1,69 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+const defaultMaxRetries = 2
+
+// Classification is the validated ranking, plus the raw answer it came from
+type Classification struct {
+	Ranking  models.Ranking
+	Result   Result
+	Attempts int
+}
+
+// SENTIMENT_MAX_RETRIES: how often the model is re-asked (with a corrective prompt) after an unknown answer
+func maxRetries() int {
+	if val, err := strconv.Atoi(os.Getenv("SENTIMENT_MAX_RETRIES")); err == nil && val >= 0 {
+		return val
+	}
+	return defaultMaxRetries
+}
+
+// Corrective prompt for the next attempt
+func correction(previous string, rankings []models.Ranking) string {
+	names := make([]string, 0, len(rankings))
+	for _, ranking := range Rankable(rankings) {
+		names = append(names, ranking.RankingName)
+	}
+	return fmt.Sprintf("Your previous answer %q is not one of the allowed rankings. Answer with exactly one of: %s. No other words.",
+		previous, strings.Join(names, ", "))
+}
+
+// Classify ranks the review and validates the answer against the rankings.
+// An unknown answer is retried with a corrective prompt, and never silently stored as "not ranked".
+func Classify(ctx context.Context, ranker SentimentRanker, review string, rankings []models.Ranking) (Classification, error) {
+	if len(Rankable(rankings)) == 0 {
+		return Classification{}, errors.New("no rankings to choose from")
+	}
+
+	request := RankRequest{Review: review, Rankings: rankings}
+	attempts := maxRetries() + 1
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		result, err := ranker.Rank(ctx, request)
+		if err != nil {
+			return Classification{}, err
+		}
+
+		ranking, err := MatchRanking(result.Response, rankings)
+		if err == nil {
+			return Classification{Ranking: ranking, Result: result, Attempts: attempt}, nil
+		}
+
+		log.Printf("⚠️ WARNING: %s answered with an unknown ranking (attempt %d/%d) --- %v", result.Provider, attempt, attempts, err)
+		lastErr = err
+		request.Correction = correction(result.Response, rankings)
+	}
+	return Classification{}, lastErr
+}
@@ -0,0 +1,59 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+func TestClassifyRetriesWithACorrectivePrompt(t *testing.T) {
+	ranker := &stubRanker{name: "openai", responses: []string{"Positive", "Good"}}
+
+	classification, err := Classify(context.Background(), ranker, "Nice", testRankings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if classification.Ranking.RankingName != "Good" || classification.Attempts != 2 || classification.Result.Provider != "openai" {
+		t.Fatalf("got %+v, want Good by openai on the 2nd attempt", classification)
+	}
+
+	if ranker.requests[0].Correction != "" {
+		t.Fatalf("1st prompt corrected: %q", ranker.requests[0].Correction)
+	}
+	correction := ranker.requests[1].Correction
+	if !strings.Contains(correction, `"Positive"`) || !strings.Contains(correction, "Excellent, Good, Okay, Bad, Terrible") || strings.Contains(correction, "Not Ranked") {
+		t.Fatalf("corrective prompt %q, want the bad answer & the rankable names", correction)
+	}
+}
+
+func TestClassifyGivesUpAfterTheRetries(t *testing.T) {
+	t.Setenv("SENTIMENT_MAX_RETRIES", "1")
+	ranker := &stubRanker{name: "openai", responses: []string{"Positive"}}
+
+	if _, err := Classify(context.Background(), ranker, "Nice", testRankings); !errors.Is(err, ErrUnknownRanking) {
+		t.Fatalf("got %v, want ErrUnknownRanking", err)
+	}
+	if len(ranker.requests) != 2 {
+		t.Fatalf("asked %d times, want 2", len(ranker.requests))
+	}
+}
+
+func TestClassifyDoesNotRetryAProviderError(t *testing.T) {
+	down := errors.New("connection refused")
+	ranker := &stubRanker{name: "openai", err: down}
+
+	if _, err := Classify(context.Background(), ranker, "Nice", testRankings); !errors.Is(err, down) {
+		t.Fatalf("got %v, want the provider's error", err)
+	}
+	if len(ranker.requests) != 1 {
+		t.Fatalf("asked %d times, want 1", len(ranker.requests))
+	}
+
+	notRanked := []models.Ranking{{RankingValue: NotRankedValue, RankingName: "Not Ranked"}}
+	if _, err := Classify(context.Background(), ranker, "Nice", notRanked); err == nil || len(ranker.requests) != 1 {
+		t.Fatalf("got %v after %d requests, want an error without asking", err, len(ranker.requests))
+	}
+}
@@ -0,0 +1,110 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+// LexiconRanker is a deterministic, rule-based classifier (no network at all).
+// Words are scored from a small lexicon (with negation & intensifiers), the normalised
+// score is then spread over the rankings, best (lowest ranking_value) to worst.
+type LexiconRanker struct{}
+
+func NewLexiconRanker() *LexiconRanker {
+	return &LexiconRanker{}
+}
+
+func (r *LexiconRanker) Name() string {
+	return "lexicon"
+}
+
+var lexicon = map[string]float64{
+	// 👍
+	"masterpiece": 3, "masterful": 3, "brilliant": 3, "outstanding": 3, "superb": 3, "amazing": 3,
+	"excellent": 3, "perfect": 3, "flawless": 3, "stunning": 2.5, "phenomenal": 3, "exceptional": 3,
+	"iconic": 2.5, "classic": 2, "gripping": 2, "captivating": 2, "powerful": 2, "beautiful": 2,
+	"great": 2, "wonderful": 2.5, "fantastic": 2.5, "terrific": 2.5, "moving": 1.5, "touching": 1.5,
+	"memorable": 1.5, "good": 1.5, "enjoyable": 1.5, "entertaining": 1.5, "fun": 1, "funny": 1,
+	"clever": 1.5, "solid": 1, "charming": 1.5, "warm": 1, "engaging": 1.5, "likeable": 1,
+	"nice": 1, "decent": 0.5, "fine": 0.5, "love": 2, "loved": 2, "liked": 1, "like": 0.5,
+	"recommend": 1.5, "recommended": 1.5, "impressive": 2, "best": 2.5,
+	// 👎
+	"terrible": -3, "awful": -3, "horrible": -3, "worst": -3, "atrocious": -3, "unwatchable": -3,
+	"disaster": -3, "garbage": -3, "trash": -3, "dreadful": -3, "abysmal": -3, "painful": -2,
+	"bad": -2, "poor": -2, "boring": -2, "dull": -1.5, "tedious": -2, "weak": -1.5, "mediocre": -1,
+	"bland": -1.5, "forgettable": -1.5, "predictable": -1, "silly": -0.5, "dumb": -1.5, "stupid": -2,
+	"messy": -1.5, "confusing": -1.5, "overlong": -1, "slow": -1, "clumsy": -1.5, "cheap": -1,
+	"disappointing": -2, "disappointment": -2, "waste": -2.5, "hate": -2.5, "hated": -2.5,
+	"annoying": -1.5, "flat": -1, "lifeless": -2, "pointless": -2, "unfunny": -2, "avoid": -2,
+}
+
+var negators = []string{"not", "no", "never", "nothing", "hardly", "barely", "neither", "nor", "without"}
+
+var intensifiers = map[string]float64{
+	"very": 1.5, "really": 1.5, "extremely": 1.8, "incredibly": 1.8, "truly": 1.5, "so": 1.3,
+	"absolutely": 1.8, "utterly": 1.8, "totally": 1.5, "quite": 1.2, "slightly": 0.6, "somewhat": 0.7,
+}
+
+// How far back a negator/intensifier reaches
+const modifierWindow = 3
+
+// Normalisation constant: score / sqrt(score² + alpha) lands in (-1, 1)
+const normalizeAlpha = 15.0
+
+func tokenizeReview(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+}
+
+func isNegator(token string) bool {
+	return slices.Contains(negators, token) || strings.HasSuffix(token, "n't")
+}
+
+// Score returns the normalised sentiment of the text, between -1 (negative) and 1 (positive)
+func Score(text string) float64 {
+	tokens := tokenizeReview(text)
+
+	total := 0.0
+	for i, token := range tokens {
+		value, ok := lexicon[strings.Trim(token, "'")]
+		if !ok {
+			continue
+		}
+		for j := max(0, i-modifierWindow); j < i; j++ {
+			if isNegator(tokens[j]) {
+				value = -value * 0.75 // "not good" isn't quite as strong as "bad"
+			}
+			if boost, ok := intensifiers[tokens[j]]; ok {
+				value *= boost
+			}
+		}
+		total += value
+	}
+
+	return total / math.Sqrt(total*total+normalizeAlpha)
+}
+
+// Always answers with a known ranking, so Correction is ignored
+func (r *LexiconRanker) Rank(ctx context.Context, request RankRequest) (Result, error) {
+	rankable := Rankable(request.Rankings)
+	if len(rankable) == 0 {
+		return Result{}, errors.New("no rankings to choose from")
+	}
+	// Best first
+	slices.SortFunc(rankable, func(a, b models.Ranking) int {
+		return a.RankingValue - b.RankingValue
+	})
+
+	// Spread [-1, 1] over the rankings, 1 = the best one
+	position := (1 - Score(request.Review)) / 2 * float64(len(rankable))
+	index := min(int(position), len(rankable)-1)
+
+	return Result{Response: rankable[index].RankingName, Provider: r.Name()}, nil
+}
@@ -0,0 +1,58 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+// The rankings collection as seeded, "Not Ranked" included
+var testRankings = []models.Ranking{
+	{RankingValue: 1, RankingName: "Excellent"},
+	{RankingValue: 2, RankingName: "Good"},
+	{RankingValue: 3, RankingName: "Okay"},
+	{RankingValue: 4, RankingName: "Bad"},
+	{RankingValue: 5, RankingName: "Terrible"},
+	{RankingValue: NotRankedValue, RankingName: "Not Ranked"},
+}
+
+func TestScore(t *testing.T) {
+	for _, tc := range []struct {
+		text     string
+		min, max float64
+	}{
+		{"An absolutely brilliant masterpiece", 0.8, 1},
+		{"Good fun", 0.3, 0.8},
+		{"", 0, 0},
+		{"It was a movie", 0, 0},
+		{"Not good", -0.5, -0.1},
+		{"Awful, boring and a waste of time", -1, -0.8},
+	} {
+		if got := Score(tc.text); got < tc.min || got > tc.max {
+			t.Errorf("Score(%q) = %.3f, want %.1f..%.1f", tc.text, got, tc.min, tc.max)
+		}
+	}
+}
+
+func TestLexiconRanksOntoTheRankings(t *testing.T) {
+	ranker := NewLexiconRanker()
+	for text, want := range map[string]string{
+		"An absolutely brilliant masterpiece": "Excellent",
+		"It was a movie":                      "Okay",
+		"Awful, boring and a waste of time":   "Terrible",
+	} {
+		result, err := ranker.Rank(context.Background(), RankRequest{Review: text, Rankings: testRankings})
+		if err != nil {
+			t.Fatalf("Rank(%q): %v", text, err)
+		}
+		if result.Response != want || result.Provider != "lexicon" {
+			t.Errorf("Rank(%q) = %q by %s, want %q by lexicon", text, result.Response, result.Provider, want)
+		}
+	}
+
+	notRanked := []models.Ranking{{RankingValue: NotRankedValue, RankingName: "Not Ranked"}}
+	if _, err := ranker.Rank(context.Background(), RankRequest{Review: "Great", Rankings: notRanked}); err == nil {
+		t.Fatal("ranked onto the Not Ranked placeholder, want an error")
+	}
+}
@@ -0,0 +1,116 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/ollama"
+	"github.com/tmc/langchaingo/llms/openai"
+)
+
+const (
+	defaultOllamaURL   = "http://localhost:11434"
+	defaultOllamaModel = "llama3"
+)
+
+// Appended to the prompt in JSON-mode
+const jsonModeInstruction = `Respond only with a JSON object of the form {"ranking": "<one of the rankings>"}.`
+
+// LLMRanker asks a (langchaingo) model, prompted with BASE_PROMPT_TEMPLATE
+type LLMRanker struct {
+	name     string
+	llm      llms.Model
+	jsonMode bool
+}
+
+// OpenAI, or any OpenAI-compatible HTTP endpoint (baseURL & model are optional)
+func NewOpenAIRanker(apiKey, baseURL, model string, jsonMode bool) (*LLMRanker, error) {
+	if apiKey == "" {
+		return nil, errors.New("could not read OPENAI_API_KEY")
+	}
+
+	opts := []openai.Option{openai.WithToken(apiKey)}
+	if baseURL != "" {
+		opts = append(opts, openai.WithBaseURL(baseURL))
+	}
+	if model != "" {
+		opts = append(opts, openai.WithModel(model))
+	}
+
+	llm, err := openai.New(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &LLMRanker{name: "openai", llm: llm, jsonMode: jsonMode}, nil
+}
+
+// Local Ollama(-style) endpoint
+func NewOllamaRanker(serverURL, model string, jsonMode bool) (*LLMRanker, error) {
+	if serverURL == "" {
+		serverURL = defaultOllamaURL
+	}
+	if model == "" {
+		model = defaultOllamaModel
+	}
+
+	opts := []ollama.Option{ollama.WithServerURL(serverURL), ollama.WithModel(model)}
+	if jsonMode {
+		opts = append(opts, ollama.WithFormat("json"))
+	}
+
+	llm, err := ollama.New(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &LLMRanker{name: "ollama", llm: llm, jsonMode: jsonMode}, nil
+}
+
+func (r *LLMRanker) Name() string {
+	return r.name
+}
+
+// BuildPrompt fills {rankings} of BASE_PROMPT_TEMPLATE, the review goes after it
+func BuildPrompt(rankings []models.Ranking) string {
+	names := make([]string, 0, len(rankings))
+	for _, ranking := range Rankable(rankings) {
+		names = append(names, ranking.RankingName)
+	}
+
+	basePrompt := os.Getenv("BASE_PROMPT_TEMPLATE")
+	return strings.Replace(basePrompt, "{rankings}", strings.Join(names, ","), 1)
+}
+
+// PromptVersion identifies the BASE_PROMPT_TEMPLATE a ranking was made with:
+// BASE_PROMPT_VERSION when set, else a short hash of the template
+func PromptVersion() string {
+	if version := os.Getenv("BASE_PROMPT_VERSION"); version != "" {
+		return version
+	}
+	sum := sha256.Sum256([]byte(os.Getenv("BASE_PROMPT_TEMPLATE")))
+	return "sha256:" + hex.EncodeToString(sum[:])[:12]
+}
+
+func (r *LLMRanker) Rank(ctx context.Context, request RankRequest) (Result, error) {
+	prompt := BuildPrompt(request.Rankings)
+	var opts []llms.CallOption
+	if r.jsonMode {
+		prompt += "\n" + jsonModeInstruction + "\n"
+		opts = append(opts, llms.WithJSONMode())
+	}
+	prompt += request.Review
+	if request.Correction != "" {
+		prompt += "\n\n" + request.Correction
+	}
+
+	response, err := llms.GenerateFromSinglePrompt(ctx, r.llm, prompt, opts...)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Response: strings.TrimSpace(response), Provider: r.name}, nil
+}
@@ -0,0 +1,97 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/textdist"
+)
+
+// ErrUnknownRanking: the answer isn't (unambiguously) one of the rankings
+var ErrUnknownRanking = errors.New("response is not a known ranking")
+
+// Structured (JSON-mode) answer
+type jsonAnswer struct {
+	Ranking string `json:"ranking"`
+}
+
+// Lower-case, punctuation/quotes dropped, "_" & "-" as spaces, single spaces
+func normalize(text string) string {
+	text = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return unicode.ToLower(r)
+		case r == '_' || r == '-' || unicode.IsSpace(r):
+			return ' '
+		default:
+			return -1
+		}
+	}, text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// MatchRanking maps a (raw) model answer onto one of the rankings:
+// JSON {"ranking": ...} → normalised exact match → the only ranking mentioned in a sentence → fuzzy (typos)
+func MatchRanking(response string, rankings []models.Ranking) (models.Ranking, error) {
+	rankable := Rankable(rankings)
+	answer := strings.TrimSpace(response)
+
+	if strings.HasPrefix(answer, "{") {
+		var structured jsonAnswer
+		if err := json.Unmarshal([]byte(answer), &structured); err == nil && structured.Ranking != "" {
+			answer = structured.Ranking
+		}
+	}
+
+	normalized := normalize(answer)
+	if normalized == "" {
+		return models.Ranking{}, fmt.Errorf("%w: empty response", ErrUnknownRanking)
+	}
+
+	for _, ranking := range rankable {
+		if normalize(ranking.RankingName) == normalized {
+			return ranking, nil
+		}
+	}
+
+	// "The sentiment is Good." → only if exactly one ranking is mentioned
+	padded := " " + normalized + " "
+	var mentioned []models.Ranking
+	for _, ranking := range rankable {
+		if strings.Contains(padded, " "+normalize(ranking.RankingName)+" ") {
+			mentioned = append(mentioned, ranking)
+		}
+	}
+	if len(mentioned) == 1 {
+		return mentioned[0], nil
+	}
+	if len(mentioned) > 1 {
+		return models.Ranking{}, fmt.Errorf("%w: %q mentions several rankings", ErrUnknownRanking, response)
+	}
+
+	// Typos ("Excelent"), the closest ranking wins, ties are rejected
+	best, bestDistance, tie := models.Ranking{}, -1, false
+	for _, ranking := range rankable {
+		name := normalize(ranking.RankingName)
+		limit := max(1, len([]rune(name))/4)
+		distance := textdist.Levenshtein(normalized, name, limit)
+		if distance > limit {
+			continue
+		}
+		switch {
+		case bestDistance == -1 || distance < bestDistance:
+			best, bestDistance, tie = ranking, distance, false
+		case distance == bestDistance:
+			tie = true
+		}
+	}
+	if bestDistance != -1 && !tie {
+		return best, nil
+	}
+
+	return models.Ranking{}, fmt.Errorf("%w: %q", ErrUnknownRanking, response)
+}
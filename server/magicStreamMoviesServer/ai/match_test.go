@@ -0,0 +1,51 @@
+package ai
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+func TestMatchRanking(t *testing.T) {
+	for _, tc := range []struct {
+		name, response string
+		want           string // "" = ErrUnknownRanking
+	}{
+		{"exact", "Good", "Good"},
+		{"case & whitespace", "  EXCELLENT ", "Excellent"},
+		{"punctuation & quotes", `"Okay."`, "Okay"},
+		{"json mode", `{"ranking": "bad"}`, "Bad"},
+		{"in a sentence", "The sentiment of this review is Good.", "Good"},
+		{"hyphenated", "Okay-ish", "Okay"},
+		{"typo", "Excelent", "Excellent"},
+		{"typo, short name", "God", "Good"},
+		{"two typos, long name", "Teribble!!", "Terrible"},
+		{"typo, too far off", "Goooood", ""},
+		{"several mentioned", "Good or Bad", ""},
+		{"placeholder", "Not Ranked", ""},
+		{"junk", "I cannot answer that", ""},
+		{"empty", "  ", ""},
+		{"empty json", "{}", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := MatchRanking(tc.response, testRankings)
+			if tc.want == "" {
+				if !errors.Is(err, ErrUnknownRanking) {
+					t.Fatalf("got %q (%v), want ErrUnknownRanking", got.RankingName, err)
+				}
+				return
+			}
+			if err != nil || got.RankingName != tc.want {
+				t.Fatalf("got %q (%v), want %q", got.RankingName, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestMatchRankingRejectsATypoCloseToTwoRankings(t *testing.T) {
+	rankings := []models.Ranking{{RankingValue: 1, RankingName: "Bad"}, {RankingValue: 2, RankingName: "Sad"}}
+	if got, err := MatchRanking("Tad", rankings); !errors.Is(err, ErrUnknownRanking) {
+		t.Fatalf("got %q (%v), want ErrUnknownRanking", got.RankingName, err)
+	}
+}
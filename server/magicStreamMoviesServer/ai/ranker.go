@@ -0,0 +1,133 @@
+// Package ai ranks the sentiment of a review onto the rankings collection 🤖🧠
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+// Ranking-value of the "not ranked" placeholder, never offered as an answer
+const NotRankedValue = 999
+
+// SentimentRanker classifies a review as one of the (ranking) names
+type SentimentRanker interface {
+	Name() string
+	Rank(ctx context.Context, request RankRequest) (Result, error)
+}
+
+// RankRequest is what a ranker gets asked.
+// Correction is set when the previous answer wasn't a known ranking
+type RankRequest struct {
+	Review     string
+	Rankings   []models.Ranking
+	Correction string
+}
+
+// Result is the (raw) answer of a ranker, and which provider gave it
+type Result struct {
+	Response string
+	Provider string
+}
+
+// Rankable drops the "not ranked" placeholder from the rankings
+func Rankable(rankings []models.Ranking) []models.Ranking {
+	var rankable []models.Ranking
+	for _, ranking := range rankings {
+		if ranking.RankingValue != NotRankedValue {
+			rankable = append(rankable, ranking)
+		}
+	}
+	return rankable
+}
+
+//! 🔗 Fallback chain: the first provider that answers wins
+
+type ChainRanker struct {
+	rankers []SentimentRanker
+}
+
+func NewChainRanker(rankers ...SentimentRanker) *ChainRanker {
+	return &ChainRanker{rankers: rankers}
+}
+
+func (c *ChainRanker) Name() string {
+	names := make([]string, 0, len(c.rankers))
+	for _, ranker := range c.rankers {
+		names = append(names, ranker.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+func (c *ChainRanker) Rank(ctx context.Context, request RankRequest) (Result, error) {
+	var errs []error
+	for _, ranker := range c.rankers {
+		result, err := ranker.Rank(ctx, request)
+		if err == nil {
+			return result, nil
+		}
+		log.Printf("⚠️ WARNING: sentiment-provider %s failed, trying the next one --- %v", ranker.Name(), err)
+		errs = append(errs, fmt.Errorf("%s: %w", ranker.Name(), err))
+	}
+	if len(errs) == 0 {
+		return Result{}, errors.New("no sentiment-provider configured")
+	}
+	return Result{}, errors.Join(errs...)
+}
+
+//! ⚙️ Config (env)
+// SENTIMENT_PROVIDERS  comma-separated chain of openai, ollama & lexicon (first = primary)
+//                      default: "openai,lexicon" when OPENAI_API_KEY is set, else "lexicon"
+// OPENAI_API_KEY, OPENAI_BASE_URL, OPENAI_MODEL   any OpenAI-compatible endpoint
+// OLLAMA_URL, OLLAMA_MODEL                         local Ollama(-style) endpoint
+// SENTIMENT_JSON_MODE=true                         structured {"ranking": "..."} answers from the LLMs
+// SENTIMENT_MAX_RETRIES                            corrective re-prompts after an unknown answer (default 2)
+// BASE_PROMPT_VERSION                              recorded in the review-history (default: hash of BASE_PROMPT_TEMPLATE)
+
+func NewRankerFromEnv() (SentimentRanker, error) {
+	providers := os.Getenv("SENTIMENT_PROVIDERS")
+	if providers == "" {
+		providers = "lexicon"
+		if os.Getenv("OPENAI_API_KEY") != "" {
+			providers = "openai,lexicon"
+		}
+	}
+
+	jsonMode := os.Getenv("SENTIMENT_JSON_MODE") == "true"
+
+	var rankers []SentimentRanker
+	for _, provider := range strings.Split(providers, ",") {
+		var ranker SentimentRanker
+		var err error
+
+		switch strings.ToLower(strings.TrimSpace(provider)) {
+		case "openai":
+			ranker, err = NewOpenAIRanker(os.Getenv("OPENAI_API_KEY"), os.Getenv("OPENAI_BASE_URL"), os.Getenv("OPENAI_MODEL"), jsonMode)
+		case "ollama":
+			ranker, err = NewOllamaRanker(os.Getenv("OLLAMA_URL"), os.Getenv("OLLAMA_MODEL"), jsonMode)
+		case "lexicon":
+			ranker = NewLexiconRanker()
+		case "":
+			continue
+		default:
+			err = fmt.Errorf("unknown sentiment-provider %q", provider)
+		}
+		if err != nil {
+			return nil, err
+		}
+		rankers = append(rankers, ranker)
+	}
+
+	if len(rankers) == 0 {
+		return nil, errors.New("no sentiment-provider configured")
+	}
+	if len(rankers) == 1 {
+		return rankers[0], nil
+	}
+	return NewChainRanker(rankers...), nil
+}
@@ -0,0 +1,84 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// stubRanker answers with its responses in turn (the last one over & over), or fails with err
+type stubRanker struct {
+	name      string
+	responses []string
+	err       error
+	requests  []RankRequest
+}
+
+func (s *stubRanker) Name() string {
+	return s.name
+}
+
+func (s *stubRanker) Rank(ctx context.Context, request RankRequest) (Result, error) {
+	s.requests = append(s.requests, request)
+	if s.err != nil {
+		return Result{}, s.err
+	}
+	response := s.responses[min(len(s.requests), len(s.responses))-1]
+	return Result{Response: response, Provider: s.name}, nil
+}
+
+func TestChainFallsThroughToTheNextProvider(t *testing.T) {
+	down := &stubRanker{name: "openai", err: errors.New("connection refused")}
+	local := &stubRanker{name: "ollama", responses: []string{"Good"}}
+	chain := NewChainRanker(down, local)
+
+	result, err := chain.Rank(context.Background(), RankRequest{Review: "Nice", Rankings: testRankings})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Response != "Good" || result.Provider != "ollama" {
+		t.Fatalf("got %q by %s, want Good by ollama", result.Response, result.Provider)
+	}
+	if chain.Name() != "openai,ollama" {
+		t.Fatalf("Name() = %q, want openai,ollama", chain.Name())
+	}
+
+	local.err = errors.New("model not found")
+	_, err = chain.Rank(context.Background(), RankRequest{Review: "Nice", Rankings: testRankings})
+	if err == nil || !strings.Contains(err.Error(), "openai: connection refused") || !strings.Contains(err.Error(), "ollama: model not found") {
+		t.Fatalf("got %v, want both providers' errors", err)
+	}
+
+	if _, err := NewChainRanker().Rank(context.Background(), RankRequest{}); err == nil {
+		t.Fatal("empty chain ranked, want an error")
+	}
+}
+
+func TestNewRankerFromEnv(t *testing.T) {
+	for _, tc := range []struct {
+		providers, apiKey string
+		want              string // Name(), "" = an error
+	}{
+		{"", "", "lexicon"},
+		{"", "sk-test", "openai,lexicon"},
+		{"lexicon", "sk-test", "lexicon"},
+		{" Ollama , lexicon ,", "", "ollama,lexicon"},
+		{"openai", "", ""}, // no OPENAI_API_KEY
+		{"lexicon,gpt-9", "", ""},
+		{",", "", ""},
+	} {
+		t.Setenv("SENTIMENT_PROVIDERS", tc.providers)
+		t.Setenv("OPENAI_API_KEY", tc.apiKey)
+
+		ranker, err := NewRankerFromEnv()
+		switch {
+		case tc.want == "" && err == nil:
+			t.Errorf("SENTIMENT_PROVIDERS=%q: got %s, want an error", tc.providers, ranker.Name())
+		case tc.want != "" && err != nil:
+			t.Errorf("SENTIMENT_PROVIDERS=%q: %v", tc.providers, err)
+		case tc.want != "" && ranker.Name() != tc.want:
+			t.Errorf("SENTIMENT_PROVIDERS=%q: got %s, want %s", tc.providers, ranker.Name(), tc.want)
+		}
+	}
+}
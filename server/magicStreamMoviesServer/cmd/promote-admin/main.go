@@ -0,0 +1,63 @@
+// promote-admin makes an (already registered) account ADMIN, e.g. the very first one 👑
+// Run it where the API's MongoDB is reachable (MONGODB_URI, .env):
+//
+//	go run ./cmd/promote-admin -email admin@example.com
+//
+// The user has to log in again for the new role to be in their tokens.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/database"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
+)
+
+func main() {
+	email := flag.String("email", "", "e-mail of the account to promote")
+	allowUnverified := flag.Bool("allow-unverified", false, "promote even if the e-mail isn't verified")
+	flag.Parse()
+	if *email == "" {
+		log.Fatal("⚠️ -email is required")
+	}
+
+	client := database.DBConnect()
+	if client == nil {
+		log.Fatal("⚠️ Failed to connect to MongoDB")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	defer client.Disconnect(context.Background())
+
+	users := repository.NewMongoUserRepository(client)
+	user, err := users.FindByEmail(ctx, *email)
+	if errors.Is(err, repository.ErrNotFound) {
+		log.Fatalf("⚠️ No account with e-mail %s, register it first", *email)
+	}
+	if err != nil {
+		log.Fatalf("⚠️ ERROR finding %s --- %v", *email, err)
+	}
+	if user.Role == models.RoleAdmin {
+		log.Printf("%s is ADMIN already", user.UserID)
+		return
+	}
+	if !user.EmailVerified && !*allowUnverified {
+		log.Fatalf("⚠️ The e-mail of %s isn't verified (-allow-unverified to promote anyway)", user.UserID)
+	}
+
+	if err := users.SetRole(ctx, user.UserID, models.RoleAdmin, time.Now()); err != nil {
+		log.Fatalf("⚠️ ERROR promoting %s --- %v", user.UserID, err)
+	}
+	utils.RecordAudit(ctx, repository.NewMongoAuditRepository(client), &models.AuditEvent{
+		Type:         models.AuditRoleChanged,
+		TargetUserID: user.UserID,
+		Details:      map[string]string{"from": user.Role, "role": models.RoleAdmin, "reason": "promote-admin"},
+	})
+	log.Printf("👑 %s is ADMIN now, they have to log in again", user.UserID)
+}
@@ -0,0 +1,245 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/mailer"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
+)
+
+// Request-bodies of the verify-email/password-reset endpoints
+type emailRequest struct{
+	Email string `json:"email" validate:"required,email"`
+}
+
+type tokenRequest struct{
+	Token string `json:"token" validate:"required"`
+}
+
+type passwordResetRequest struct{
+	Token string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=6"`
+}
+
+// The client's pages the e-mailed links open (APP_BASE_URL, default the dev-client)
+func appLink(path,token string)string{
+	base:=os.Getenv("APP_BASE_URL")
+	if base==""{
+		base="http://localhost:5173"
+	}
+	return strings.TrimRight(base,"/")+path+"?token="+url.QueryEscape(token)
+}
+
+// REQUIRE_EMAIL_VERIFICATION=true refuses the log-in of unverified accounts
+func emailVerificationRequired()bool{
+	return os.Getenv("REQUIRE_EMAIL_VERIFICATION")=="true"
+}
+
+// Issues a verification-token & mails the link
+func sendVerificationEmail(c context.Context,tokens repository.ActionTokenRepository,mail mailer.Mailer,user *models.User)error{
+	token,err:=utils.GenerateActionToken(c,tokens,user.UserID,models.ActionVerifyEmail)
+	if err!=nil{
+		return err
+	}
+	return mail.Send(c,mailer.Message{
+		To: user.Email,
+		Subject: "Verify your MagikStream e-mail address",
+		Body: "Hi "+user.FirstName+",\n\n"+
+			"please confirm your e-mail address by opening this link:\n\n"+
+			appLink("/verify-email",token)+"\n\n"+
+			"The link is valid for "+utils.EmailVerificationLifetime.String()+". If you didn't sign up, just ignore this e-mail.\n",
+	})
+}
+
+func sendPasswordResetEmail(c context.Context,tokens repository.ActionTokenRepository,mail mailer.Mailer,user *models.User)error{
+	token,err:=utils.GenerateActionToken(c,tokens,user.UserID,models.ActionResetPassword)
+	if err!=nil{
+		return err
+	}
+	return mail.Send(c,mailer.Message{
+		To: user.Email,
+		Subject: "Reset your MagikStream password",
+		Body: "Hi "+user.FirstName+",\n\n"+
+			"someone (hopefully you) asked to reset your password. Choose a new one here:\n\n"+
+			appLink("/reset-password",token)+"\n\n"+
+			"The link is valid for "+utils.PasswordResetLifetime.String()+" and works once. If you didn't ask for it, just ignore this e-mail.\n",
+	})
+}
+
+// Binds & validates a request-body, writes the 400 itself
+func bindAccountRequest(ctx *gin.Context,req any)bool{
+	if err:=ctx.ShouldBindJSON(req);err!=nil{
+		ctx.JSON(http.StatusBadRequest,gin.H{
+			"error":"⚠️ Invalid Input!",
+			"status_code":http.StatusBadRequest,
+		})
+		return false
+	}
+	if err:=validator.New().Struct(req);err!=nil{
+		ctx.JSON(http.StatusBadRequest,gin.H{
+			"error":"⚠️ Validation failed!",
+			"status_code":http.StatusBadRequest,
+		})
+		return false
+	}
+	return true
+}
+
+func invalidLink(ctx *gin.Context){
+	ctx.JSON(http.StatusBadRequest,gin.H{
+		"error":"⚠️ Invalid or expired link!",
+		"status_code":http.StatusBadRequest,
+	})
+}
+
+//! 1️⃣ POST Request a (new) verification-mail. Always 202, so it can't be used to find out who has an account
+func RequestEmailVerificationHandler(users repository.UserRepository,tokens repository.ActionTokenRepository,mail mailer.Mailer)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		var req emailRequest
+		if !bindAccountRequest(ctx,&req){
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,err:=users.FindByEmail(c,req.Email)
+		if err==nil && !user.EmailVerified{
+			if err:=sendVerificationEmail(c,tokens,mail,user);err!=nil{
+				log.Printf("⚠️ ERROR sending the verification-mail to %s --- %v",user.UserID,err)
+			}
+		}else if err!=nil && !errors.Is(err,repository.ErrNotFound){
+			log.Println("⚠️ ERROR looking up user for verification ---",err)
+		}
+
+		ctx.JSON(http.StatusAccepted,gin.H{"message":"If the account exists and isn't verified yet, a verification link is on its way ✉️"})
+	}
+}
+
+//! 2️⃣ POST Verify the e-mail address with the mailed token
+func VerifyEmailHandler(users repository.UserRepository,tokens repository.ActionTokenRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		var req tokenRequest
+		if !bindAccountRequest(ctx,&req){
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		claims,err:=utils.ConsumeActionToken(c,tokens,req.Token,models.ActionVerifyEmail)
+		if errors.Is(err,utils.ErrInvalidActionToken){
+			invalidLink(ctx)
+			return
+		}
+		if err==nil{
+			err=users.SetEmailVerified(c,claims.UserId,time.Now())
+		}
+		if errors.Is(err,repository.ErrNotFound){
+			invalidLink(ctx)
+			return
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to verify e-mail!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK,gin.H{"message":"E-mail verified ✅"})
+	}
+}
+
+//! 3️⃣ POST Request a password-reset mail. Always 202 (no account enumeration)
+func RequestPasswordResetHandler(users repository.UserRepository,tokens repository.ActionTokenRepository,mail mailer.Mailer)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		var req emailRequest
+		if !bindAccountRequest(ctx,&req){
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,err:=users.FindByEmail(c,req.Email)
+		if err==nil{
+			if err:=sendPasswordResetEmail(c,tokens,mail,user);err!=nil{
+				log.Printf("⚠️ ERROR sending the password-reset mail to %s --- %v",user.UserID,err)
+			}
+		}else if !errors.Is(err,repository.ErrNotFound){
+			log.Println("⚠️ ERROR looking up user for password-reset ---",err)
+		}
+
+		ctx.JSON(http.StatusAccepted,gin.H{"message":"If an account with this e-mail exists, a reset link is on its way ✉️"})
+	}
+}
+
+//! 4️⃣ POST Set a new password with the mailed token: every session & access-token of the account is signed out
+func ResetPasswordHandler(users repository.UserRepository,tokens repository.ActionTokenRepository,sessions repository.SessionRepository,revoker *utils.Revoker)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		var req passwordResetRequest
+		if !bindAccountRequest(ctx,&req){
+			return
+		}
+
+		hashedPassword,err:=HashPassword(req.Password)
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Hashing Error!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		claims,err:=utils.ConsumeActionToken(c,tokens,req.Token,models.ActionResetPassword)
+		if errors.Is(err,utils.ErrInvalidActionToken){
+			invalidLink(ctx)
+			return
+		}
+		now:=time.Now()
+		if err==nil{
+			err=users.UpdatePassword(c,claims.UserId,hashedPassword,now)
+		}
+		if errors.Is(err,repository.ErrNotFound){
+			invalidLink(ctx)
+			return
+		}
+		if err==nil{
+			// Whoever had the old password may still be logged in somewhere
+			_,err=sessions.RevokeAllForUser(c,claims.UserId,models.SessionRevokedPasswordReset,now)
+		}
+		if err==nil{
+			err=revoker.RevokeUser(c,claims.UserId,models.SessionRevokedPasswordReset)
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to reset password!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		// The link went to the mailbox, so the address is proven as well
+		if err:=users.SetEmailVerified(c,claims.UserId,now);err!=nil{
+			log.Printf("⚠️ ERROR marking %s as verified --- %v",claims.UserId,err)
+		}
+
+		clearAuthCookies(ctx)
+		ctx.JSON(http.StatusOK,gin.H{"message":"Password has been reset, please log in again ✅"})
+	}
+}
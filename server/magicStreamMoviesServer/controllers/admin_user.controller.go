@@ -0,0 +1,571 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/mailer"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Page-size of GET /admin/users
+const (
+	defaultUserPageLimit int64 = 20
+	maxUserPageLimit int64 = 100
+)
+
+// POST /admin/users/:user_id/disable body, the reason is optional
+type userDisableRequest struct{
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+func toAdminUserResponse(user *models.User)models.AdminUserResponse{
+	return models.AdminUserResponse{
+		UserID: user.UserID,
+		FirstName: user.FirstName,
+		LastName: user.LastName,
+		Email: user.Email,
+		Role: user.Role,
+		EmailVerified: user.EmailVerified,
+		MFAEnabled: user.MFA.Enabled,
+		Disabled: user.Disabled,
+		DisabledAt: user.DisabledAt,
+		DisabledReason: user.DisabledReason,
+		PasswordResetRequired: user.PasswordResetRequired,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+// The :user_id of the route, writes the 404/500 itself
+func findTargetUser(ctx *gin.Context,c context.Context,users repository.UserRepository)(*models.User,bool){
+	user,err:=users.FindByUserID(c,ctx.Param("user_id"))
+	if errors.Is(err,repository.ErrNotFound){
+		ctx.JSON(http.StatusNotFound,gin.H{
+			"error":"⚠️ User NOT FOUND!",
+			"status_code":http.StatusNotFound,
+		})
+		return nil,false
+	}
+	if err!=nil{
+		ctx.JSON(http.StatusInternalServerError,gin.H{
+			"error":"⚠️ Failed to fetch user!",
+			"status_code":http.StatusInternalServerError,
+		})
+		return nil,false
+	}
+	return user,true
+}
+
+// Admins can't demote or disable themselves, so there's always someone left to undo it (writes the 409 itself)
+func rejectSelfChange(ctx *gin.Context,adminId string,user *models.User)bool{
+	if adminId!=user.UserID{
+		return false
+	}
+	ctx.JSON(http.StatusConflict,gin.H{
+		"error":"⚠️ You can't change your own role or disable your own account!",
+		"status_code":http.StatusConflict,
+	})
+	return true
+}
+
+// Demoting/disabling the last enabled ADMIN would leave nobody to manage the users (writes the 409/500 itself)
+func rejectLastAdmin(ctx *gin.Context,c context.Context,users repository.UserRepository,user *models.User)bool{
+	if user.Role!=models.RoleAdmin || user.Disabled{
+		return false
+	}
+	enabled:=false
+	admins,err:=users.Count(c,repository.UserQuery{Role: models.RoleAdmin,Disabled: &enabled})
+	if err!=nil{
+		ctx.JSON(http.StatusInternalServerError,gin.H{
+			"error":"⚠️ Failed to count admins!",
+			"status_code":http.StatusInternalServerError,
+		})
+		return true
+	}
+	if admins<=1{
+		ctx.JSON(http.StatusConflict,gin.H{
+			"error":"⚠️ This is the last ADMIN!",
+			"status_code":http.StatusConflict,
+		})
+		return true
+	}
+	return false
+}
+
+//! 1️⃣ POST Force sign-out of a user (user:manage): every session & every outstanding access-token stops working
+func ForceSignOutHandler(users repository.UserRepository,sessions repository.SessionRepository,revoker *utils.Revoker,audit repository.AuditRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,ok:=findTargetUser(ctx,c,users)
+		if !ok{
+			return
+		}
+
+		revoked,err:=sessions.RevokeAllForUser(c,user.UserID,models.SessionRevokedByAdmin,time.Now())
+		if err==nil{
+			err=revoker.RevokeUser(c,user.UserID,models.SessionRevokedByAdmin)
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to sign out user!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		adminId,_:=utils.GetUserIdFromCtx(ctx)
+		utils.RecordAudit(c,audit,&models.AuditEvent{
+			Type: models.AuditForcedSignOut,
+			ActorID: adminId,
+			TargetUserID: user.UserID,
+			IPAddress: ctx.ClientIP(),
+		})
+
+		ctx.JSON(http.StatusOK,gin.H{
+			"message":"User signed out everywhere ✅",
+			"sessions_revoked":revoked,
+		})
+	}
+}
+
+//! 2️⃣ POST Unlock a user's log-in (user:manage): the failed attempts are forgotten
+func UnlockUserHandler(users repository.UserRepository,guard *utils.LoginGuard,audit repository.AuditRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		userId:=ctx.Param("user_id")
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,err:=users.FindByUserID(c,userId)
+		if err==nil{
+			err=guard.Unlock(c,user.Email)
+		}
+		if errors.Is(err,repository.ErrNotFound){
+			ctx.JSON(http.StatusNotFound,gin.H{
+				"error":"⚠️ User NOT FOUND!",
+				"status_code":http.StatusNotFound,
+			})
+			return
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to unlock user!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		adminId,_:=utils.GetUserIdFromCtx(ctx)
+		utils.RecordAudit(c,audit,&models.AuditEvent{
+			Type: models.AuditLoginUnlock,
+			ActorID: adminId,
+			TargetUserID: user.UserID,
+			IPAddress: ctx.ClientIP(),
+			Details: map[string]string{"kind":models.LoginAttemptAccount,"subject":user.Email},
+		})
+
+		ctx.JSON(http.StatusOK,gin.H{"message":"User unlocked ✅"})
+	}
+}
+
+//! 3️⃣ DELETE the lock of a client-IP (user:manage)
+func UnlockIPHandler(guard *utils.LoginGuard,audit repository.AuditRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		ip:=ctx.Param("ip")
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		if err:=guard.UnlockIP(c,ip);err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to unlock IP!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		adminId,_:=utils.GetUserIdFromCtx(ctx)
+		utils.RecordAudit(c,audit,&models.AuditEvent{
+			Type: models.AuditLoginUnlock,
+			ActorID: adminId,
+			IPAddress: ctx.ClientIP(),
+			Details: map[string]string{"kind":models.LoginAttemptIP,"subject":ip},
+		})
+
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+//! 4️⃣ GET the currently locked accounts & IPs (user:manage)
+func GetLoginLocksHandler(guard *utils.LoginGuard)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		locks,err:=guard.ListLocked(c)
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to fetch log-in locks!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+		ctx.JSON(http.StatusOK,locks)
+	}
+}
+
+//! 5️⃣ GET Audit-events (audit:read, newest first), ?type= & ?user_id= (actor or target) narrow them down
+func GetAuditEventsHandler(audit repository.AuditRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		limit:=defaultHistoryLimit
+		if limitStr:=ctx.Query("limit"); limitStr!=""{
+			val,err:=strconv.ParseInt(limitStr,10,64)
+			if err!=nil || val<1{
+				ctx.JSON(http.StatusBadRequest,gin.H{
+					"error":"⚠️ limit must be a positive number!",
+					"status_code":http.StatusBadRequest,
+				})
+				return
+			}
+			limit = min(val,maxHistoryLimit)
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		events,err:=audit.List(c,repository.AuditFilter{Type: ctx.Query("type"),UserID: ctx.Query("user_id")},limit)
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to fetch audit-events!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+		ctx.JSON(http.StatusOK,events)
+	}
+}
+
+//! 6️⃣ GET Users (user:manage, oldest first), ?q= searches e-mail & names, ?role= & ?disabled=true|false filter. Paged with limit & after (next_page_token)
+func GetUsersHandler(users repository.UserRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		query:=repository.UserQuery{Search: strings.TrimSpace(ctx.Query("q"))}
+
+		if role:=ctx.Query("role"); role!=""{
+			if _,ok:=models.RolePermissions[role];!ok{
+				ctx.JSON(http.StatusBadRequest,gin.H{
+					"error":"⚠️ Unknown role!",
+					"status_code":http.StatusBadRequest,
+				})
+				return
+			}
+			query.Role = role
+		}
+
+		if disabledStr:=ctx.Query("disabled"); disabledStr!=""{
+			disabled,err:=strconv.ParseBool(disabledStr)
+			if err!=nil{
+				ctx.JSON(http.StatusBadRequest,gin.H{
+					"error":"⚠️ disabled must be true or false!",
+					"status_code":http.StatusBadRequest,
+				})
+				return
+			}
+			query.Disabled = &disabled
+		}
+
+		limit:=defaultUserPageLimit
+		if limitStr:=ctx.Query("limit"); limitStr!=""{
+			val,err:=strconv.ParseInt(limitStr,10,64)
+			if err!=nil || val<1{
+				ctx.JSON(http.StatusBadRequest,gin.H{
+					"error":"⚠️ limit must be a positive number!",
+					"status_code":http.StatusBadRequest,
+				})
+				return
+			}
+			limit = min(val,maxUserPageLimit)
+		}
+
+		if after:=ctx.Query("after"); after!=""{
+			pageCursor,err:=utils.DecodePageCursor(after)
+			if err==nil{
+				_,err=bson.ObjectIDFromHex(pageCursor.ID)
+			}
+			if err!=nil{
+				ctx.JSON(http.StatusBadRequest,gin.H{
+					"error":"⚠️ Invalid page token!",
+					"status_code":http.StatusBadRequest,
+				})
+				return
+			}
+			query.AfterID = pageCursor.ID
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		total,err:=users.Count(c,query)
+		var page []models.User
+		if err==nil{
+			// One extra, to know if there's a next page
+			query.Limit = limit+1
+			page,err=users.Find(c,query)
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to fetch users!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		nextPageToken:=""
+		if int64(len(page))>limit{
+			page = page[:limit]
+			nextPageToken,err = utils.EncodePageCursor(utils.PageCursor{ID: page[len(page)-1].ID.Hex()})
+			if err!=nil{
+				ctx.JSON(http.StatusInternalServerError,gin.H{
+					"error":"⚠️ Failed to create page token!",
+					"status_code":http.StatusInternalServerError,
+				})
+				return
+			}
+		}
+
+		result:=make([]models.AdminUserResponse,0,len(page))
+		for i:=range page{
+			result = append(result,toAdminUserResponse(&page[i]))
+		}
+
+		ctx.JSON(http.StatusOK,gin.H{
+			"users":result,
+			"next_page_token":nextPageToken,
+			"total":total,
+		})
+	}
+}
+
+//! 7️⃣ GET a single user (user:manage)
+func GetUserHandler(users repository.UserRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,ok:=findTargetUser(ctx,c,users)
+		if !ok{
+			return
+		}
+		ctx.JSON(http.StatusOK,toAdminUserResponse(user))
+	}
+}
+
+//! 8️⃣ PUT Promote/demote a user (user:manage). Their access-tokens are revoked, the next refresh carries the new role
+func UpdateUserRoleHandler(users repository.UserRepository,revoker *utils.Revoker,audit repository.AuditRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		var req models.RoleChange
+		if !bindAccountRequest(ctx,&req){
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,ok:=findTargetUser(ctx,c,users)
+		if !ok{
+			return
+		}
+		if user.Role==req.Role{
+			ctx.JSON(http.StatusOK,toAdminUserResponse(user))
+			return
+		}
+
+		adminId,_:=utils.GetUserIdFromCtx(ctx)
+		if rejectSelfChange(ctx,adminId,user) || rejectLastAdmin(ctx,c,users,user){
+			return
+		}
+
+		previousRole:=user.Role
+		user.Role = req.Role
+		user.UpdatedAt = time.Now()
+		err:=users.SetRole(c,user.UserID,user.Role,user.UpdatedAt)
+		if err==nil{
+			err=revoker.RevokeUser(c,user.UserID,models.SessionRevokedRoleChange)
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to change role!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		utils.RecordAudit(c,audit,&models.AuditEvent{
+			Type: models.AuditRoleChanged,
+			ActorID: adminId,
+			TargetUserID: user.UserID,
+			IPAddress: ctx.ClientIP(),
+			Details: map[string]string{"from":previousRole,"role":user.Role},
+		})
+
+		ctx.JSON(http.StatusOK,toAdminUserResponse(user))
+	}
+}
+
+//! 9️⃣ POST Disable an account (user:manage): no more log-ins, and every session, access-token & API-key stops working
+func DisableUserHandler(users repository.UserRepository,sessions repository.SessionRepository,revoker *utils.Revoker,audit repository.AuditRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		var req userDisableRequest
+		// The body is optional
+		if ctx.Request.ContentLength!=0 && !bindAccountRequest(ctx,&req){
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,ok:=findTargetUser(ctx,c,users)
+		if !ok{
+			return
+		}
+		if user.Disabled{
+			ctx.JSON(http.StatusOK,toAdminUserResponse(user))
+			return
+		}
+
+		adminId,_:=utils.GetUserIdFromCtx(ctx)
+		if rejectSelfChange(ctx,adminId,user) || rejectLastAdmin(ctx,c,users,user){
+			return
+		}
+
+		// Disabled first: the refresh-endpoint & API-keys check the flag, the revocations cover the rest
+		now:=time.Now()
+		reason:=strings.TrimSpace(req.Reason)
+		err:=users.SetDisabled(c,user.UserID,true,reason,now)
+		if err==nil{
+			_,err=sessions.RevokeAllForUser(c,user.UserID,models.SessionRevokedAccountDisabled,now)
+		}
+		if err==nil{
+			err=revoker.RevokeUser(c,user.UserID,models.SessionRevokedAccountDisabled)
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to disable user!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		details:=map[string]string{}
+		if reason!=""{
+			details["reason"]=reason
+		}
+		utils.RecordAudit(c,audit,&models.AuditEvent{
+			Type: models.AuditAccountDisabled,
+			ActorID: adminId,
+			TargetUserID: user.UserID,
+			IPAddress: ctx.ClientIP(),
+			Details: details,
+		})
+
+		user.Disabled, user.DisabledAt, user.DisabledReason, user.UpdatedAt = true, &now, reason, now
+		ctx.JSON(http.StatusOK,toAdminUserResponse(user))
+	}
+}
+
+//! 🔟 POST Re-enable a disabled account (user:manage). The user has to log in again
+func EnableUserHandler(users repository.UserRepository,audit repository.AuditRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,ok:=findTargetUser(ctx,c,users)
+		if !ok{
+			return
+		}
+		if !user.Disabled{
+			ctx.JSON(http.StatusOK,toAdminUserResponse(user))
+			return
+		}
+
+		now:=time.Now()
+		if err:=users.SetDisabled(c,user.UserID,false,"",now);err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to enable user!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		adminId,_:=utils.GetUserIdFromCtx(ctx)
+		utils.RecordAudit(c,audit,&models.AuditEvent{
+			Type: models.AuditAccountEnabled,
+			ActorID: adminId,
+			TargetUserID: user.UserID,
+			IPAddress: ctx.ClientIP(),
+		})
+
+		user.Disabled, user.DisabledAt, user.DisabledReason, user.UpdatedAt = false, nil, "", now
+		ctx.JSON(http.StatusOK,toAdminUserResponse(user))
+	}
+}
+
+//! 1️⃣1️⃣ POST Force a password-reset (user:manage): signed out everywhere, no log-in until a new password is set via the mailed link
+func ForcePasswordResetHandler(store *repository.Store,revoker *utils.Revoker,mail mailer.Mailer)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,ok:=findTargetUser(ctx,c,store.Users)
+		if !ok{
+			return
+		}
+
+		now:=time.Now()
+		err:=store.Users.RequirePasswordReset(c,user.UserID,now)
+		if err==nil{
+			_,err=store.Sessions.RevokeAllForUser(c,user.UserID,models.SessionRevokedByAdmin,now)
+		}
+		if err==nil{
+			err=revoker.RevokeUser(c,user.UserID,models.SessionRevokedByAdmin)
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to force password-reset!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		// Best effort, the user can always ask for a new link (POST /password-reset/request)
+		mailSent:=true
+		if err:=sendPasswordResetEmail(c,store.ActionTokens,mail,user);err!=nil{
+			log.Printf("⚠️ ERROR sending the password-reset mail to %s --- %v",user.UserID,err)
+			mailSent = false
+		}
+
+		adminId,_:=utils.GetUserIdFromCtx(ctx)
+		utils.RecordAudit(c,store.Audit,&models.AuditEvent{
+			Type: models.AuditPasswordResetForced,
+			ActorID: adminId,
+			TargetUserID: user.UserID,
+			IPAddress: ctx.ClientIP(),
+		})
+
+		ctx.JSON(http.StatusOK,gin.H{
+			"message":"User signed out, a new password is required ✅",
+			"mail_sent":mailSent,
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
+)
+
+// POST-body for minting an API-key
+type APIKeyCreate struct{
+	Name string `json:"name" validate:"required,min=2,max=100"`
+	UserID string `json:"user_id"` // defaults to the caller
+	Scopes []string `json:"scopes" validate:"required,min=1"`
+	ExpiresInDays int `json:"expires_in_days" validate:"omitempty,min=1,max=3650"`
+}
+
+type APIKeyScopes struct{
+	Scopes []string `json:"scopes" validate:"required,min=1"`
+}
+
+// Unknown scopes, and scopes the owner's role doesn't have (they could never be used)
+func invalidScopes(scopes []string,role string)[]string{
+	invalid:=[]string{}
+	for _,scope:=range scopes{
+		if !models.IsPermission(scope) || !models.HasPermission(role,scope){
+			invalid = append(invalid,scope)
+		}
+	}
+	return invalid
+}
+
+func apiKeyError(ctx *gin.Context,err error){
+	if errors.Is(err,repository.ErrNotFound){
+		ctx.JSON(http.StatusNotFound,gin.H{
+			"error":"⚠️ API-key NOT FOUND!",
+			"status_code":http.StatusNotFound,
+		})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError,gin.H{
+		"error":"⚠️ Failed to UPDATE API-key!",
+		"status_code":http.StatusInternalServerError,
+	})
+}
+
+//! 1️⃣ POST Mint an API-key (apikey:manage). The key itself is only ever returned here
+func CreateAPIKeyHandler(apiKeys repository.APIKeyRepository,users repository.UserRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		adminId,err:=utils.GetUserIdFromCtx(ctx)
+		if err!=nil{
+			ctx.JSON(http.StatusBadRequest ,gin.H{
+				"error":"⚠️ ID NOT FOUND IN CONTEXT!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		var req APIKeyCreate
+		if err:=ctx.ShouldBindJSON(&req);err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Invalid input!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+		if err:=validate.Struct(req);err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Validation failed!",
+				"details":err.Error(),
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+		if req.UserID==""{
+			req.UserID = adminId
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		owner,err:=users.FindByUserID(c,req.UserID)
+		if err!=nil{
+			ctx.JSON(http.StatusNotFound,gin.H{
+				"error":"⚠️ User NOT FOUND!",
+				"status_code":http.StatusNotFound,
+			})
+			return
+		}
+		if invalid:=invalidScopes(req.Scopes,owner.Role); len(invalid)>0{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Invalid scopes for this user!",
+				"invalid_scopes":invalid,
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		key,prefix:=utils.NewAPIKey()
+		apiKey:=&models.APIKey{
+			KeyID: utils.NewTokenID(),
+			Name: req.Name,
+			Prefix: prefix,
+			KeyHash: utils.HashAPIKey(key),
+			UserID: owner.UserID,
+			Scopes: slices.Compact(slices.Sorted(slices.Values(req.Scopes))),
+			CreatedBy: adminId,
+			CreatedAt: time.Now(),
+		}
+		if req.ExpiresInDays>0{
+			expiresAt:=apiKey.CreatedAt.AddDate(0,0,req.ExpiresInDays)
+			apiKey.ExpiresAt = &expiresAt
+		}
+
+		if err:=apiKeys.Insert(c,apiKey);err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to create API-key!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated,gin.H{
+			"api_key":apiKey,
+			"key":key, // shown once, only the hash is stored
+		})
+	}
+}
+
+//! 2️⃣ GET all API-keys (apikey:manage), without the keys themselves
+func GetAPIKeysHandler(apiKeys repository.APIKeyRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		keys,err:=apiKeys.List(c)
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to fetch API-keys!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+		ctx.JSON(http.StatusOK,keys)
+	}
+}
+
+//! 3️⃣ PUT the scopes of an API-key (apikey:manage)
+func UpdateAPIKeyScopesHandler(apiKeys repository.APIKeyRepository,users repository.UserRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		var req APIKeyScopes
+		if err:=ctx.ShouldBindJSON(&req);err!=nil || validate.Struct(req)!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ scopes is required!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		keyId:=ctx.Param("key_id")
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		apiKey,err:=apiKeys.FindByKeyID(c,keyId)
+		if err!=nil{
+			apiKeyError(ctx,err)
+			return
+		}
+
+		role:=""
+		if owner,err:=users.FindByUserID(c,apiKey.UserID);err==nil{
+			role = owner.Role
+		}
+		if invalid:=invalidScopes(req.Scopes,role); len(invalid)>0{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Invalid scopes for this user!",
+				"invalid_scopes":invalid,
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		apiKey.Scopes = slices.Compact(slices.Sorted(slices.Values(req.Scopes)))
+		if err:=apiKeys.UpdateScopes(c,keyId,apiKey.Scopes);err!=nil{
+			apiKeyError(ctx,err)
+			return
+		}
+		ctx.JSON(http.StatusOK,apiKey)
+	}
+}
+
+//! 4️⃣ DELETE (revoke) an API-key (apikey:manage), it stops working right away
+func RevokeAPIKeyHandler(apiKeys repository.APIKeyRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		if err:=apiKeys.Revoke(c,ctx.Param("key_id"),time.Now());err!=nil{
+			apiKeyError(ctx,err)
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
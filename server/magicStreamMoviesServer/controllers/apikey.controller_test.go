@@ -0,0 +1,59 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+func TestAPIKeysOnlyReachWhatTheirScopesGrant(t *testing.T) {
+	api, admin, _ := catalogAdmins(t)
+
+	rec := api.do(http.MethodPost, "/admin/api-keys", map[string]any{"name": "importer", "scopes": []string{models.PermMovieCreate}}, admin...)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("creating an API-key: %d %s", rec.Code, rec.Body)
+	}
+	key := decode[map[string]any](t, rec)["key"].(string)
+
+	withKey := func(method, path string, body any) *httptest.ResponseRecorder {
+		t.Helper()
+		return api.doWithHeader(method, path, body, "X-API-Key", key)
+	}
+
+	movie := models.Movie{
+		ImdbID:     "tt9",
+		Title:      "Imported",
+		PosterPath: "https://example.com/poster.jpg",
+		YouTubeID:  "yt",
+		Genre:      []models.Genre{{GenreID: 2, GenreName: "Drama"}},
+		Ranking:    models.Ranking{RankingValue: 1, RankingName: "Excellent"},
+	}
+	if rec := withKey(http.MethodPost, "/add-movie", movie); rec.Code != http.StatusCreated {
+		t.Fatalf("scoped route: got %d %s, want 201", rec.Code, rec.Body)
+	}
+	if rec := withKey(http.MethodGet, "/admin/genres", nil); rec.Code != http.StatusForbidden {
+		t.Fatalf("route outside the scopes: got %d, want 403", rec.Code)
+	}
+
+	// Routes without a permission are the owner's account, no scope grants them
+	for _, route := range []struct{ method, path string }{
+		{http.MethodGet, "/me"},
+		{http.MethodPatch, "/me"},
+		{http.MethodGet, "/me/export"},
+		{http.MethodPost, "/logout-all"},
+		{http.MethodGet, "/sessions"},
+		{http.MethodDelete, "/sessions/" + api.currentSession(admin)},
+		{http.MethodPost, "/me/watchlist"},
+		{http.MethodPut, "/movie/tt1/review"},
+		{http.MethodGet, "/recommended-movies"},
+	} {
+		if rec := withKey(route.method, route.path, map[string]any{}); rec.Code != http.StatusForbidden {
+			t.Fatalf("%s %s: got %d, want 403", route.method, route.path, rec.Code)
+		}
+	}
+	if rec := api.do(http.MethodGet, "/me", nil, admin...); rec.Code != http.StatusOK {
+		t.Fatalf("the owner's session: got %d, want 200", rec.Code)
+	}
+}
@@ -0,0 +1,457 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/search"
+)
+
+// PATCH-body for a genre/ranking, nil = keep
+type CatalogEntryUpdate struct{
+	Name *string `json:"name" validate:"omitempty,min=2,max=100"`
+	Retired *bool `json:"retired"`
+}
+
+type GenreOrder struct{
+	GenreIDs []int `json:"genre_ids" validate:"required,min=1"`
+}
+
+type RankingOrder struct{
+	RankingValues []int `json:"ranking_values" validate:"required,min=1"`
+}
+
+func catalogParam(ctx *gin.Context,name string)(int,bool){
+	val,err:=strconv.Atoi(ctx.Param(name))
+	if err!=nil{
+		ctx.JSON(http.StatusBadRequest,gin.H{
+			"error":"⚠️ "+name+" must be a number!",
+			"status_code":http.StatusBadRequest,
+		})
+		return 0,false
+	}
+	return val,true
+}
+
+// A reorder must name every entry (retired ones included) exactly once
+func isPermutation(ids []int,existing map[int]bool)bool{
+	if len(ids)!=len(existing){
+		return false
+	}
+	seen:=map[int]bool{}
+	for _,id:=range ids{
+		if !existing[id] || seen[id]{
+			return false
+		}
+		seen[id]=true
+	}
+	return true
+}
+
+func catalogError(ctx *gin.Context,err error,what string){
+	switch{
+	case errors.Is(err,repository.ErrNotFound):
+		ctx.JSON(http.StatusNotFound,gin.H{
+			"error":"⚠️ "+what+" NOT FOUND!",
+			"status_code":http.StatusNotFound,
+		})
+	case errors.Is(err,repository.ErrDuplicate):
+		ctx.JSON(http.StatusConflict,gin.H{
+			"error":"⚠️ "+what+" with this id or name already exists!",
+			"status_code":http.StatusConflict,
+		})
+	default:
+		ctx.JSON(http.StatusInternalServerError,gin.H{
+			"error":"⚠️ Failed to UPDATE "+what+"!",
+			"status_code":http.StatusInternalServerError,
+		})
+	}
+}
+
+//! 1️⃣ GET all genres, retired ones included (catalog:manage)
+func GetGenreEntriesHandler(genreRepo repository.GenreRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		entries,err:=genreRepo.ListEntries(c,true)
+		if err!=nil{
+			catalogError(ctx,err,"Genre")
+			return
+		}
+		ctx.JSON(http.StatusOK,entries)
+	}
+}
+
+//! 2️⃣ POST a new genre (catalog:manage), appended at the end unless a sort_order is given
+func AddGenreHandler(genreRepo repository.GenreRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		var entry models.GenreEntry
+		if err:=ctx.ShouldBindJSON(&entry);err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Invalid input!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+		if err:=validate.Struct(entry);err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Validation failed!",
+				"details":err.Error(),
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		if entry.SortOrder==0{
+			entries,err:=genreRepo.ListEntries(c,true)
+			if err!=nil{
+				catalogError(ctx,err,"Genre")
+				return
+			}
+			for _,existing:=range entries{
+				entry.SortOrder = max(entry.SortOrder,existing.SortOrder)
+			}
+			entry.SortOrder++
+		}
+
+		if err:=genreRepo.Insert(c,&entry);err!=nil{
+			catalogError(ctx,err,"Genre")
+			return
+		}
+		ctx.JSON(http.StatusCreated,entry)
+	}
+}
+
+//! 3️⃣ PATCH rename and/or retire a genre (catalog:manage), a rename cascades into movies & favourite_genres
+func UpdateGenreHandler(genreRepo repository.GenreRepository,movies repository.MovieRepository,users repository.UserRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		genreId,ok:=catalogParam(ctx,"genre_id")
+		if !ok{
+			return
+		}
+
+		var update CatalogEntryUpdate
+		if err:=ctx.ShouldBindJSON(&update);err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Invalid input!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+		if err:=validate.Struct(update);err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Validation failed!",
+				"details":err.Error(),
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		var moviesUpdated,usersUpdated int64
+		if update.Name!=nil{
+			if err:=genreRepo.Rename(c,genreId,*update.Name);err!=nil{
+				catalogError(ctx,err,"Genre")
+				return
+			}
+
+			var err error
+			if moviesUpdated,err=movies.RenameGenre(c,genreId,*update.Name);err==nil{
+				usersUpdated,err=users.RenameFavouriteGenre(c,genreId,*update.Name)
+			}
+			if err!=nil{
+				log.Printf("⚠️ ERROR cascading rename of genre %d --- %v",genreId,err)
+				catalogError(ctx,err,"Genre")
+				return
+			}
+			search.Invalidate()
+		}
+		if update.Retired!=nil{
+			if err:=genreRepo.SetRetired(c,genreId,*update.Retired);err!=nil{
+				catalogError(ctx,err,"Genre")
+				return
+			}
+		}
+
+		entry,err:=genreRepo.FindByID(c,genreId)
+		if err!=nil{
+			catalogError(ctx,err,"Genre")
+			return
+		}
+		ctx.JSON(http.StatusOK,gin.H{
+			"genre":entry,
+			"movies_updated":moviesUpdated,
+			"users_updated":usersUpdated,
+		})
+	}
+}
+
+//! 4️⃣ PUT the order of all genres (catalog:manage)
+func ReorderGenresHandler(genreRepo repository.GenreRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		var order GenreOrder
+		if err:=ctx.ShouldBindJSON(&order);err!=nil || validate.Struct(order)!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ genre_ids is required!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		entries,err:=genreRepo.ListEntries(c,true)
+		if err!=nil{
+			catalogError(ctx,err,"Genre")
+			return
+		}
+		existing:=map[int]bool{}
+		for _,entry:=range entries{
+			existing[entry.GenreID]=true
+		}
+		if !isPermutation(order.GenreIDs,existing){
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ genre_ids must list every genre exactly once!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		if err:=genreRepo.Reorder(c,order.GenreIDs);err!=nil{
+			catalogError(ctx,err,"Genre")
+			return
+		}
+
+		entries,err=genreRepo.ListEntries(c,true)
+		if err!=nil{
+			catalogError(ctx,err,"Genre")
+			return
+		}
+		ctx.JSON(http.StatusOK,entries)
+	}
+}
+
+//! 5️⃣ DELETE (retire) a genre (catalog:manage). Movies keep their copy, it's only hidden from GET /genres
+func RetireGenreHandler(genreRepo repository.GenreRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		genreId,ok:=catalogParam(ctx,"genre_id")
+		if !ok{
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		if err:=genreRepo.SetRetired(c,genreId,true);err!=nil{
+			catalogError(ctx,err,"Genre")
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+//! 6️⃣ GET all rankings, retired ones included (catalog:manage)
+func GetRankingEntriesHandler(rankingRepo repository.RankingRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		entries,err:=rankingRepo.ListEntries(c,true)
+		if err!=nil{
+			catalogError(ctx,err,"Ranking")
+			return
+		}
+		ctx.JSON(http.StatusOK,entries)
+	}
+}
+
+//! 7️⃣ POST a new ranking (catalog:manage). Active rankings are what the sentiment-rankers choose from
+func AddRankingHandler(rankingRepo repository.RankingRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		var entry models.RankingEntry
+		if err:=ctx.ShouldBindJSON(&entry);err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Invalid input!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+		if err:=validate.Struct(entry);err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Validation failed!",
+				"details":err.Error(),
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		if entry.SortOrder==0{
+			entries,err:=rankingRepo.ListEntries(c,true)
+			if err!=nil{
+				catalogError(ctx,err,"Ranking")
+				return
+			}
+			for _,existing:=range entries{
+				entry.SortOrder = max(entry.SortOrder,existing.SortOrder)
+			}
+			entry.SortOrder++
+		}
+
+		if err:=rankingRepo.Insert(c,&entry);err!=nil{
+			catalogError(ctx,err,"Ranking")
+			return
+		}
+		ctx.JSON(http.StatusCreated,entry)
+	}
+}
+
+//! 8️⃣ PATCH rename and/or retire a ranking (catalog:manage), a rename cascades into the movies & the user-reviews' sentiment
+func UpdateRankingHandler(rankingRepo repository.RankingRepository,movies repository.MovieRepository,reviews repository.UserReviewRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		rankingValue,ok:=catalogParam(ctx,"ranking_value")
+		if !ok{
+			return
+		}
+
+		var update CatalogEntryUpdate
+		if err:=ctx.ShouldBindJSON(&update);err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Invalid input!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+		if err:=validate.Struct(update);err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Validation failed!",
+				"details":err.Error(),
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		var moviesUpdated,reviewsUpdated int64
+		if update.Name!=nil{
+			if err:=rankingRepo.Rename(c,rankingValue,*update.Name);err!=nil{
+				catalogError(ctx,err,"Ranking")
+				return
+			}
+
+			var err error
+			if moviesUpdated,err=movies.RenameRanking(c,rankingValue,*update.Name);err!=nil{
+				log.Printf("⚠️ ERROR cascading rename of ranking %d --- %v",rankingValue,err)
+				catalogError(ctx,err,"Ranking")
+				return
+			}
+			search.Invalidate()
+
+			if reviewsUpdated,err=reviews.RenameSentiment(c,rankingValue,*update.Name);err!=nil{
+				log.Printf("⚠️ ERROR cascading rename of ranking %d into the user-reviews --- %v",rankingValue,err)
+				catalogError(ctx,err,"Ranking")
+				return
+			}
+		}
+		if update.Retired!=nil{
+			if err:=rankingRepo.SetRetired(c,rankingValue,*update.Retired);err!=nil{
+				catalogError(ctx,err,"Ranking")
+				return
+			}
+		}
+
+		entry,err:=rankingRepo.FindByValue(c,rankingValue)
+		if err!=nil{
+			catalogError(ctx,err,"Ranking")
+			return
+		}
+		ctx.JSON(http.StatusOK,gin.H{
+			"ranking":entry,
+			"movies_updated":moviesUpdated,
+			"reviews_updated":reviewsUpdated,
+		})
+	}
+}
+
+//! 9️⃣ PUT the order of all rankings (catalog:manage)
+func ReorderRankingsHandler(rankingRepo repository.RankingRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		var order RankingOrder
+		if err:=ctx.ShouldBindJSON(&order);err!=nil || validate.Struct(order)!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ ranking_values is required!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		entries,err:=rankingRepo.ListEntries(c,true)
+		if err!=nil{
+			catalogError(ctx,err,"Ranking")
+			return
+		}
+		existing:=map[int]bool{}
+		for _,entry:=range entries{
+			existing[entry.RankingValue]=true
+		}
+		if !isPermutation(order.RankingValues,existing){
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ ranking_values must list every ranking exactly once!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		if err:=rankingRepo.Reorder(c,order.RankingValues);err!=nil{
+			catalogError(ctx,err,"Ranking")
+			return
+		}
+
+		entries,err=rankingRepo.ListEntries(c,true)
+		if err!=nil{
+			catalogError(ctx,err,"Ranking")
+			return
+		}
+		ctx.JSON(http.StatusOK,entries)
+	}
+}
+
+//! 🔟 DELETE (retire) a ranking (catalog:manage). New reviews can't be ranked with it anymore
+func RetireRankingHandler(rankingRepo repository.RankingRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		rankingValue,ok:=catalogParam(ctx,"ranking_value")
+		if !ok{
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		if err:=rankingRepo.SetRetired(c,rankingValue,true);err!=nil{
+			catalogError(ctx,err,"Ranking")
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
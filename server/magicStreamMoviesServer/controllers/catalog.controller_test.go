@@ -0,0 +1,83 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+// An admin (catalog:manage) & a plain user
+func catalogAdmins(t *testing.T) (*testAPI, []*http.Cookie, []*http.Cookie) {
+	api := newTestAPI(t)
+	api.seedCatalog()
+	return api, api.loginAdmin("admin@example.com"), api.login("user@example.com")
+}
+
+func TestGenres(t *testing.T) {
+	api, admin, user := catalogAdmins(t)
+
+	genre := models.GenreEntry{Genre: models.Genre{GenreID: 4, GenreName: "Horror"}}
+	if rec := api.do(http.MethodPost, "/admin/genres", genre, user...); rec.Code != http.StatusForbidden {
+		t.Fatalf("user adding a genre: got %d, want 403", rec.Code)
+	}
+	if rec := api.do(http.MethodPost, "/admin/genres", genre, admin...); rec.Code != http.StatusCreated {
+		t.Fatalf("admin adding a genre: %d %s", rec.Code, rec.Body)
+	}
+	for _, dup := range []models.Genre{{GenreID: 4, GenreName: "Thriller"}, {GenreID: 5, GenreName: "Horror"}} {
+		rec := api.do(http.MethodPost, "/admin/genres", models.GenreEntry{Genre: dup}, admin...)
+		if rec.Code != http.StatusConflict {
+			t.Fatalf("duplicate %+v: got %d %s, want 409", dup, rec.Code, rec.Body)
+		}
+	}
+
+	if rec := api.do(http.MethodDelete, "/admin/genres/1", nil, admin...); rec.Code != http.StatusNoContent {
+		t.Fatalf("retiring Comedy: %d %s", rec.Code, rec.Body)
+	}
+
+	rec := api.do(http.MethodGet, "/genres", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /genres: %d %s", rec.Code, rec.Body)
+	}
+	var names []string
+	for _, genre := range decode[[]models.Genre](t, rec) {
+		names = append(names, genre.GenreName)
+	}
+	want := []string{"Drama", "Western", "Horror"}
+	if len(names) != len(want) || names[0] != want[0] || names[1] != want[1] || names[2] != want[2] {
+		t.Fatalf("got %v, want %v (retired left out, in sort order)", names, want)
+	}
+}
+
+func TestRankings(t *testing.T) {
+	api, admin, user := catalogAdmins(t)
+
+	ranking := models.RankingEntry{Ranking: models.Ranking{RankingValue: 4, RankingName: "Bad"}}
+	if rec := api.do(http.MethodPost, "/admin/rankings", ranking, user...); rec.Code != http.StatusForbidden {
+		t.Fatalf("user adding a ranking: got %d, want 403", rec.Code)
+	}
+	if rec := api.do(http.MethodPost, "/admin/rankings", ranking, admin...); rec.Code != http.StatusCreated {
+		t.Fatalf("admin adding a ranking: %d %s", rec.Code, rec.Body)
+	}
+	if rec := api.do(http.MethodPost, "/admin/rankings", ranking, admin...); rec.Code != http.StatusConflict {
+		t.Fatalf("duplicate ranking: got %d %s, want 409", rec.Code, rec.Body)
+	}
+
+	// A rename cascades into the movies ranked with it
+	rec := api.do(http.MethodPatch, "/admin/rankings/2", map[string]any{"name": "Great"}, admin...)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("renaming ranking 2: %d %s", rec.Code, rec.Body)
+	}
+	rec = api.do(http.MethodGet, "/movie/tt2", nil, user...)
+	if movie := decode[models.Movie](t, rec); movie.Ranking.RankingName != "Great" {
+		t.Fatalf("movie tt2 ranking: got %q, want Great", movie.Ranking.RankingName)
+	}
+
+	rec = api.do(http.MethodGet, "/admin/rankings", nil, admin...)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /admin/rankings: %d %s", rec.Code, rec.Body)
+	}
+	if entries := decode[[]models.RankingEntry](t, rec); len(entries) != 4 {
+		t.Fatalf("got %d rankings, want 4", len(entries))
+	}
+}
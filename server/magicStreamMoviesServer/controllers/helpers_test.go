@@ -0,0 +1,181 @@
+package controllers_test
+
+// Test-API: the real routes & middleware over the in-memory store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/ai"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/jobs"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/keystore"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/mailer"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/routes"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
+)
+
+const testPassword = "secret-password"
+
+type testAPI struct {
+	t      *testing.T
+	router *gin.Engine
+	store  *repository.Store
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	keys, err := keystore.Open(t.TempDir(), keystore.AlgEdDSA, 24*time.Hour, utils.RefreshTokenLifetime)
+	if err != nil {
+		t.Fatalf("opening keystore: %v", err)
+	}
+	utils.SetKeystore(keys)
+
+	store := repository.NewMemoryStore()
+	ranker := ai.NewLexiconRanker()
+	revoker := utils.NewRevoker(store.Revocations)
+	guard := utils.NewLoginGuard(store.LoginAttempts, store.Audit)
+	mail := &mailer.LogMailer{}
+
+	router := gin.New()
+	routes.SetUpUnProtectedRoutes(router, store, revoker, keys, mail, guard)
+	routes.SetUpProtectedRoutes(router, store, ranker,
+		jobs.NewRerankManager(store.Jobs, store.Movies, store.Rankings, store.History, ranker),
+		jobs.NewSentimentWorker(store.UserReviews, store.Movies, store.Rankings, ranker),
+		jobs.NewPlaybackRecorder(store.WatchHistory),
+		revoker, guard, mail)
+
+	return &testAPI{t: t, router: router, store: store}
+}
+
+// do sends a request (body as JSON), with the given cookies
+func (api *testAPI) do(method, path string, body any, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	api.t.Helper()
+	return api.doWithHeader(method, path, body, "", "", cookies...)
+}
+
+func (api *testAPI) doWithHeader(method, path string, body any, header, value string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	api.t.Helper()
+	var reader *bytes.Reader
+	if body == nil {
+		reader = bytes.NewReader(nil)
+	} else {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			api.t.Fatalf("encoding body: %v", err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if header != "" {
+		req.Header.Set(header, value)
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	api.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// register & log in, returns the auth-cookies (token, refresh_token)
+func (api *testAPI) login(email string) []*http.Cookie {
+	api.t.Helper()
+	rec := api.do(http.MethodPost, "/register", models.UserRegister{
+		FirstName:       "Test",
+		LastName:        "User",
+		Email:           email,
+		Password:        testPassword,
+		FavouriteGenres: []models.Genre{{GenreID: 2, GenreName: "Drama"}},
+	})
+	if rec.Code != http.StatusCreated {
+		api.t.Fatalf("register %s: %d %s", email, rec.Code, rec.Body)
+	}
+
+	rec = api.do(http.MethodPost, "/login", models.UserLogin{Email: email, Password: testPassword})
+	if rec.Code != http.StatusOK {
+		api.t.Fatalf("login %s: %d %s", email, rec.Code, rec.Body)
+	}
+	return rec.Result().Cookies()
+}
+
+// loginAdmin registers the user and makes it ADMIN in the store (as cmd/promote-admin does), then logs in
+func (api *testAPI) loginAdmin(email string) []*http.Cookie {
+	api.t.Helper()
+	api.login(email)
+	if err := api.store.Users.SetRole(context.Background(), api.userID(email), models.RoleAdmin, time.Now()); err != nil {
+		api.t.Fatalf("promoting %s: %v", email, err)
+	}
+	return api.loginAgain(email)
+}
+
+func (api *testAPI) userID(email string) string {
+	api.t.Helper()
+	user, err := api.store.Users.FindByEmail(context.Background(), email)
+	if err != nil {
+		api.t.Fatalf("finding %s: %v", email, err)
+	}
+	return user.UserID
+}
+
+// Catalog: 3 genres, 3 rankings & one movie per ranking
+func (api *testAPI) seedCatalog() {
+	api.t.Helper()
+	ctx := context.Background()
+	for i, name := range []string{"Comedy", "Drama", "Western"} {
+		entry := models.GenreEntry{Genre: models.Genre{GenreID: i + 1, GenreName: name}}
+		if err := api.store.Genres.Insert(ctx, &entry); err != nil {
+			api.t.Fatalf("seeding genre: %v", err)
+		}
+	}
+	rankings := []models.Ranking{{RankingValue: 1, RankingName: "Excellent"}, {RankingValue: 2, RankingName: "Good"}, {RankingValue: 3, RankingName: "Okay"}}
+	for _, ranking := range rankings {
+		entry := models.RankingEntry{Ranking: ranking}
+		if err := api.store.Rankings.Insert(ctx, &entry); err != nil {
+			api.t.Fatalf("seeding ranking: %v", err)
+		}
+	}
+	for i, title := range []string{"Charlie", "Alpha", "Bravo"} {
+		movie := models.Movie{
+			ImdbID:     fmt.Sprintf("tt%d", i+1),
+			Title:      title,
+			PosterPath: "https://example.com/poster.jpg",
+			YouTubeID:  "yt",
+			Genre:      []models.Genre{{GenreID: 2, GenreName: "Drama"}},
+			Ranking:    rankings[i],
+		}
+		if err := api.store.Movies.Insert(ctx, &movie); err != nil {
+			api.t.Fatalf("seeding movie: %v", err)
+		}
+	}
+}
+
+func decode[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	var out T
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body, err)
+	}
+	return out
+}
+
+func cookie(cookies []*http.Cookie, name string) *http.Cookie {
+	for _, c := range cookies {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/jobs"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
+)
+
+// Job + progress (in %) for the status-endpoints
+func jobResponse(job *models.RerankJob)gin.H{
+	progress:=100.0
+	if job.Total>0{
+		progress = min(100.0, float64(job.Processed)*100/float64(job.Total))
+	}
+	return gin.H{
+		"job":job,
+		"progress":progress,
+	}
+}
+
+//! 1️⃣ POST Start Re-Rank Job (rerank:run) - re-runs the AI ranking over the whole catalog
+func StartRerankJobHandler(manager *jobs.RerankManager)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		userId,err:=utils.GetUserIdFromCtx(ctx)
+		if err!=nil{
+			ctx.JSON(http.StatusBadRequest ,gin.H{
+				"error":"⚠️ ID NOT FOUND IN CONTEXT!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		var req struct{
+			DryRun bool `json:"dry_run"`
+			Concurrency int `json:"concurrency" validate:"gte=0,lte=16"`
+		}
+		// Empty body = defaults
+		if ctx.Request.ContentLength!=0{
+			if err:=ctx.ShouldBindJSON(&req);err!=nil{
+				ctx.JSON(http.StatusBadRequest,gin.H{
+					"error":"⚠️ Invalid Input!",
+					"status_code":http.StatusBadRequest,
+				})
+				return 
+			}
+		}
+		if err:=validate.Struct(req);err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Validation failed! concurrency must be between 0 and 16",
+				"status_code":http.StatusBadRequest,
+			})
+			return 
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		job,err:=manager.Start(c,userId,req.DryRun,req.Concurrency)
+		if errors.Is(err,jobs.ErrJobAlreadyRunning){
+			ctx.JSON(http.StatusConflict,gin.H{
+				"error":"⚠️ A re-rank job is already running!",
+				"status_code":http.StatusConflict,
+			})
+			return 
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ ERROR starting re-rank job!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return 
+		}
+
+		ctx.JSON(http.StatusAccepted,jobResponse(job))
+	}
+}
+
+//! 2️⃣ GET Re-Rank Jobs (rerank:run)
+func GetRerankJobsHandler(jobRepo repository.JobRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		rerankJobs,err:=jobRepo.List(c,20)
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to fetch re-rank jobs!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return 
+		}
+		ctx.JSON(http.StatusOK,rerankJobs)
+	}
+}
+
+//! 3️⃣ GET Re-Rank Job status (rerank:run)
+func GetRerankJobHandler(jobRepo repository.JobRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		job,err:=jobRepo.FindByJobID(c,ctx.Param("job_id"))
+		if errors.Is(err,repository.ErrNotFound){
+			ctx.JSON(http.StatusNotFound,gin.H{
+				"error":"⚠️ Job NOT FOUND!",
+				"status_code":http.StatusNotFound,
+			})
+			return 
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to fetch re-rank job!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return 
+		}
+		ctx.JSON(http.StatusOK,jobResponse(job))
+	}
+}
+
+//! 4️⃣ POST Cancel Re-Rank Job (rerank:run)
+func CancelRerankJobHandler(manager *jobs.RerankManager)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		job,err:=manager.Cancel(c,ctx.Param("job_id"))
+		if errors.Is(err,repository.ErrNotFound){
+			ctx.JSON(http.StatusNotFound,gin.H{
+				"error":"⚠️ Job NOT FOUND!",
+				"status_code":http.StatusNotFound,
+			})
+			return 
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to cancel re-rank job!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return 
+		}
+		ctx.JSON(http.StatusAccepted,jobResponse(job))
+	}
+}
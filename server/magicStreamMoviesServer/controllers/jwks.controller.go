@@ -0,0 +1,17 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/keystore"
+)
+
+//! 1️⃣ GET the public JWT verification keys (JWKS), for services that verify our tokens themselves
+func JWKSHandler(keys *keystore.Store)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		// Short cache, so a rotated-in key shows up quickly
+		ctx.Header("Cache-Control","public, max-age=300")
+		ctx.JSON(http.StatusOK,keys.JWKS())
+	}
+}
@@ -0,0 +1,394 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/jobs"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Account deletion needs the password again (and the second factor, if enabled)
+type accountDeletion struct{
+	Password string `json:"password" validate:"required"`
+	Code string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// Audit-events of a user, in their data-export
+const maxExportedAuditEvents int64 = 1000
+
+func toUserResponse(user *models.User)models.UserResponse{
+	return models.UserResponse{
+		UserID: user.UserID,
+		FirstName: user.FirstName,
+		LastName: user.LastName,
+		Email: user.Email,
+		Role: user.Role,
+		FavouriteGenres: user.FavouriteGenres,
+		EmailVerified: user.EmailVerified,
+	}
+}
+
+// Resolves the picked genres against the (active) catalog: unknown/retired ID's fail, names come from the catalog
+func resolveFavouriteGenres(c context.Context,genres repository.GenreRepository,picked []models.Genre)([]models.Genre,[]int,error){
+	catalog,err:=genres.List(c)
+	if err!=nil{
+		return nil,nil,err
+	}
+	byId:=map[int]models.Genre{}
+	for _,genre:=range catalog{
+		byId[genre.GenreID]=genre
+	}
+
+	resolved:=[]models.Genre{}
+	unknown:=[]int{}
+	seen:=map[int]bool{}
+	for _,genre:=range picked{
+		if seen[genre.GenreID]{
+			continue
+		}
+		seen[genre.GenreID]=true
+		if entry,ok:=byId[genre.GenreID];ok{
+			resolved = append(resolved,entry)
+		}else{
+			unknown = append(unknown,genre.GenreID)
+		}
+	}
+	return resolved,unknown,nil
+}
+
+// Removes the user's reviews one by one, so every movie's user_rating (& audience_sentiment) loses their part
+func deleteUserReviews(c context.Context,store *repository.Store,userId string)error{
+	reviews,err:=store.UserReviews.Find(c,repository.UserReviewQuery{UserID: userId})
+	if err!=nil{
+		return err
+	}
+	for _,review:=range reviews{
+		deleted,err:=store.UserReviews.Delete(c,review.ReviewID)
+		if errors.Is(err,repository.ErrNotFound){
+			continue
+		}
+		if err!=nil{
+			return err
+		}
+		utils.UpdateReviewAggregates(c,store.Movies,deleted.ImdbID,deleted,nil)
+	}
+	return nil
+}
+
+func wrongPassword(ctx *gin.Context){
+	ctx.JSON(http.StatusUnauthorized,gin.H{
+		"error":"⚠️ Current password is wrong!",
+		"status_code":http.StatusUnauthorized,
+	})
+}
+
+//! 1️⃣ GET the logged-in user's profile
+func GetMeHandler(users repository.UserRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,ok:=currentUser(ctx,c,users)
+		if !ok{
+			return
+		}
+		ctx.JSON(http.StatusOK,toUserResponse(user))
+	}
+}
+
+//! 2️⃣ PATCH the profile: first/last name & favourite genres (these drive the recommendations)
+func UpdateMeHandler(users repository.UserRepository,genres repository.GenreRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		var update models.UserProfileUpdate
+		if !bindAccountRequest(ctx,&update){
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,ok:=currentUser(ctx,c,users)
+		if !ok{
+			return
+		}
+
+		if update.FirstName!=nil{
+			user.FirstName = strings.TrimSpace(*update.FirstName)
+		}
+		if update.LastName!=nil{
+			user.LastName = strings.TrimSpace(*update.LastName)
+		}
+		if update.FavouriteGenres!=nil{
+			resolved,unknown,err:=resolveFavouriteGenres(c,genres,*update.FavouriteGenres)
+			if err!=nil{
+				ctx.JSON(http.StatusInternalServerError,gin.H{
+					"error":"⚠️ Failed to fetch genres!",
+					"status_code":http.StatusInternalServerError,
+				})
+				return
+			}
+			if len(unknown)>0{
+				ctx.JSON(http.StatusBadRequest,gin.H{
+					"error":"⚠️ Unknown genre(s)!",
+					"genre_ids":unknown,
+					"status_code":http.StatusBadRequest,
+				})
+				return
+			}
+			user.FavouriteGenres = resolved
+		}
+
+		// Trimmed names have to pass the User model's rules as well
+		if err:=validator.New().StructPartial(user,"FirstName","LastName");err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Validation failed!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		if err:=users.UpdateProfile(c,user.UserID,user.FirstName,user.LastName,user.FavouriteGenres,time.Now());err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to update profile!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+		ctx.JSON(http.StatusOK,toUserResponse(user))
+	}
+}
+
+//! 3️⃣ POST Change the password (the current one is checked again). Every other device gets signed out
+func ChangePasswordHandler(users repository.UserRepository,sessions repository.SessionRepository,revoker *utils.Revoker,audit repository.AuditRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		if rejectAPIKey(ctx){
+			return
+		}
+
+		var req models.PasswordChange
+		if !bindAccountRequest(ctx,&req){
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,ok:=currentUser(ctx,c,users)
+		if !ok{
+			return
+		}
+		if bcrypt.CompareHashAndPassword([]byte(user.Password),[]byte(req.CurrentPassword))!=nil{
+			wrongPassword(ctx)
+			return
+		}
+
+		hashedPassword,err:=HashPassword(req.NewPassword)
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Hashing Error!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		// All sessions & access-tokens go (this one too), then this device gets a fresh session
+		now:=time.Now()
+		err=users.UpdatePassword(c,user.UserID,hashedPassword,now)
+		if err==nil{
+			_,err=sessions.RevokeAllForUser(c,user.UserID,models.SessionRevokedPasswordChange,now)
+		}
+		if err==nil{
+			err=revoker.RevokeUser(c,user.UserID,models.SessionRevokedPasswordChange)
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to change password!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		utils.RecordAudit(c,audit,&models.AuditEvent{
+			Type: models.AuditPasswordChanged,
+			ActorID: user.UserID,
+			TargetUserID: user.UserID,
+			IPAddress: ctx.ClientIP(),
+		})
+
+		token,refreshToken,err:=startSession(ctx,c,sessions,user)
+		if err!=nil{
+			// The password is changed, only this device has to log in again
+			log.Printf("⚠️ ERROR starting a session for %s after the password-change --- %v",user.UserID,err)
+			clearAuthCookies(ctx)
+			ctx.JSON(http.StatusOK,gin.H{"message":"Password changed, please log in again ✅"})
+			return
+		}
+		setAuthCookies(ctx,token,refreshToken)
+
+		ctx.JSON(http.StatusOK,gin.H{"message":"Password changed, other devices were signed out ✅"})
+	}
+}
+
+//! 4️⃣ GET Export of everything stored about the logged-in user (GDPR), as a JSON download
+func ExportMeHandler(store *repository.Store)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,ok:=currentUser(ctx,c,store.Users)
+		if !ok{
+			return
+		}
+
+		activeSessions,err:=store.Sessions.ListActiveByUser(c,user.UserID,time.Now())
+		var apiKeys []models.APIKey
+		if err==nil{
+			apiKeys,err=store.APIKeys.ListByUser(c,user.UserID)
+		}
+		var watchlistEntries []models.WatchlistEntry
+		if err==nil{
+			watchlistEntries,err=store.Watchlist.List(c,user.UserID)
+		}
+		var userReviews []models.UserReview
+		if err==nil{
+			userReviews,err=store.UserReviews.Find(c,repository.UserReviewQuery{UserID: user.UserID})
+		}
+		var watchHistory []models.WatchHistory
+		if err==nil{
+			watchHistory,err=store.WatchHistory.Find(c,repository.WatchHistoryQuery{UserID: user.UserID})
+		}
+		var auditEvents []models.AuditEvent
+		if err==nil{
+			auditEvents,err=store.Audit.List(c,repository.AuditFilter{UserID: user.UserID},maxExportedAuditEvents)
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to export account data!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		ctx.Header("Content-Disposition",`attachment; filename="magikstream-export-`+user.UserID+`.json"`)
+		ctx.JSON(http.StatusOK,gin.H{
+			"exported_at":time.Now(),
+			"profile":gin.H{
+				"user_id":user.UserID,
+				"first_name":user.FirstName,
+				"last_name":user.LastName,
+				"email":user.Email,
+				"role":user.Role,
+				"favourite_genres":user.FavouriteGenres,
+				"email_verified":user.EmailVerified,
+				"email_verified_at":user.EmailVerifiedAt,
+				"mfa_enabled":user.MFA.Enabled,
+				"created_at":user.CreatedAt,
+				"updated_at":user.UpdatedAt,
+			},
+			"sessions":activeSessions,
+			"api_keys":apiKeys,
+			"watchlist":watchlistEntries,
+			"reviews":userReviews,
+			"watch_history":watchHistory,
+			"audit_events":auditEvents,
+		})
+	}
+}
+
+//! 5️⃣ DELETE the logged-in user's account for good (GDPR): the user & their sessions/API-keys/watchlist/reviews/watch-history are removed
+func DeleteMeHandler(store *repository.Store,revoker *utils.Revoker,guard *utils.LoginGuard,recorder *jobs.PlaybackRecorder)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		if rejectAPIKey(ctx){
+			return
+		}
+
+		var req accountDeletion
+		if !bindAccountRequest(ctx,&req){
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,ok:=currentUser(ctx,c,store.Users)
+		if !ok{
+			return
+		}
+		if bcrypt.CompareHashAndPassword([]byte(user.Password),[]byte(req.Password))!=nil{
+			wrongPassword(ctx)
+			return
+		}
+		if user.MFA.Enabled{
+			ok,err:=verifyMFA(c,store.Users,user,req.Code,req.RecoveryCode)
+			if err!=nil{
+				ctx.JSON(http.StatusInternalServerError,gin.H{
+					"error":"⚠️ Failed to verify MFA code!",
+					"status_code":http.StatusInternalServerError,
+				})
+				return
+			}
+			if !ok{
+				invalidMFACode(ctx)
+				return
+			}
+		}
+
+		// Tokens first: if anything after fails, the account at least can't be used anymore
+		err:=revoker.RevokeUser(c,user.UserID,models.SessionRevokedAccountDeleted)
+		if err==nil{
+			_,err=store.Sessions.DeleteAllForUser(c,user.UserID)
+		}
+		if err==nil{
+			_,err=store.APIKeys.DeleteAllForUser(c,user.UserID)
+		}
+		if err==nil{
+			_,err=store.Watchlist.DeleteAllForUser(c,user.UserID)
+		}
+		if err==nil{
+			err=deleteUserReviews(c,store,user.UserID)
+		}
+		if err==nil{
+			// Held-back heartbeats would bring it back
+			recorder.Forget(user.UserID)
+			_,err=store.WatchHistory.DeleteAllForUser(c,user.UserID)
+		}
+		if err==nil{
+			// The audit-trail stays, without the IPs & details (e-mail, ...) of the user's events
+			_,err=store.Audit.Redact(c,user.UserID)
+		}
+		if err==nil{
+			err=store.Users.Delete(c,user.UserID)
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to delete account!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		if err:=guard.Unlock(c,user.Email);err!=nil{
+			log.Printf("⚠️ ERROR clearing failed log-ins of %s --- %v",user.UserID,err)
+		}
+		// Only the (pseudonymous) ID is kept, as proof of the deletion (like on the redacted earlier events)
+		utils.RecordAudit(c,store.Audit,&models.AuditEvent{
+			Type: models.AuditAccountDeleted,
+			ActorID: user.UserID,
+			TargetUserID: user.UserID,
+		})
+
+		clearAuthCookies(ctx)
+		ctx.JSON(http.StatusOK,gin.H{"message":"Account deleted ✅"})
+	}
+}
@@ -0,0 +1,54 @@
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+)
+
+func TestDeleteMeRedactsTheUsersAuditEvents(t *testing.T) {
+	api := newTestAPI(t)
+	alice := api.login("alice@example.com")
+	api.login("bob@example.com")
+	aliceID, bobID := api.userID("alice@example.com"), api.userID("bob@example.com")
+
+	ctx := context.Background()
+	for _, event := range []models.AuditEvent{
+		{Type: models.AuditPasswordChanged, ActorID: aliceID, TargetUserID: aliceID, IPAddress: "203.0.113.7"},
+		{Type: models.AuditLoginLockout, TargetUserID: aliceID, IPAddress: "203.0.113.8", Details: map[string]string{"subject": "alice@example.com"}},
+		{Type: models.AuditPasswordChanged, ActorID: bobID, TargetUserID: bobID, IPAddress: "198.51.100.1"},
+	} {
+		if err := api.store.Audit.Append(ctx, &event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec := api.do(http.MethodDelete, "/me", map[string]string{"password": testPassword}, alice...)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("DELETE /me: %d %s", rec.Code, rec.Body)
+	}
+
+	events, err := api.store.Audit.List(ctx, repository.AuditFilter{UserID: aliceID}, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 || events[0].Type != models.AuditAccountDeleted {
+		t.Fatalf("got %+v, want the 2 earlier events & ACCOUNT_DELETED", events)
+	}
+	for _, event := range events {
+		if event.IPAddress != "" || event.Details != nil {
+			t.Fatalf("%s kept %q %v, want no IP & details", event.Type, event.IPAddress, event.Details)
+		}
+	}
+
+	bobs, err := api.store.Audit.List(ctx, repository.AuditFilter{UserID: bobID}, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bobs) != 1 || bobs[0].IPAddress != "198.51.100.1" {
+		t.Fatalf("got %+v, want bob's event untouched", bobs)
+	}
+}
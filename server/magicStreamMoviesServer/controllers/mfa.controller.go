@@ -0,0 +1,436 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
+)
+
+// A TOTP code, or one of the recovery-codes instead
+type mfaCodeRequest struct{
+	Code string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type mfaLoginRequest struct{
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// Secret & otpauth URI of a (pending) enrolment. The URI is the QR-code payload
+func mfaEnrolment(user *models.User,secret string)gin.H{
+	uri:=utils.TOTPURI(user.Email,secret)
+	return gin.H{
+		"secret":secret,
+		"otpauth_uri":uri,
+		"qr_payload":uri,
+	}
+}
+
+// Second factor of an enabled MFA: a TOTP code (each works once) or a recovery-code (used up).
+// user.MFA is kept in step with what got stored
+func verifyMFA(c context.Context,users repository.UserRepository,user *models.User,code,recoveryCode string)(bool,error){
+	var err error
+	switch{
+	case code!="":
+		step,ok:=utils.ValidateTOTP(user.MFA.Secret,code,time.Now())
+		if !ok{
+			return false,nil
+		}
+		if err=users.UseMFAStep(c,user.UserID,step);err==nil{
+			user.MFA.LastUsedStep = step
+		}
+	case recoveryCode!="":
+		hash:=utils.HashRecoveryCode(recoveryCode)
+		if err=users.UseRecoveryCode(c,user.UserID,hash);err==nil{
+			user.MFA.RecoveryCodes = slices.DeleteFunc(slices.Clone(user.MFA.RecoveryCodes),func(h string)bool{ return h==hash })
+		}
+	default:
+		return false,nil
+	}
+	if errors.Is(err,repository.ErrNotFound){
+		return false,nil
+	}
+	return err==nil,err
+}
+
+// Confirms a pending enrolment with its first code: MFA gets enabled & the recovery-codes are issued
+func activateMFA(c context.Context,users repository.UserRepository,user *models.User,code string)([]string,bool,error){
+	step,ok:=utils.ValidateTOTP(user.MFA.PendingSecret,code,time.Now())
+	if user.MFA.PendingSecret=="" || !ok{
+		return nil,false,nil
+	}
+
+	now:=time.Now()
+	codes,hashes:=utils.NewRecoveryCodes()
+	err:=users.SetMFA(c,user.UserID,models.UserMFA{
+		Enabled: true,
+		Secret: user.MFA.PendingSecret,
+		RecoveryCodes: hashes,
+		LastUsedStep: step,
+		EnrolledAt: &now,
+	},now)
+	if err!=nil{
+		return nil,false,err
+	}
+	return codes,true,nil
+}
+
+// Password checked out: answer with a short-lived, single-use MFA challenge instead of tokens.
+// A user who must use MFA but hasn't enrolled yet gets a (pending) enrolment along with it
+func startMFAChallenge(ctx *gin.Context,c context.Context,users repository.UserRepository,tokens repository.ActionTokenRepository,user *models.User){
+	response:=gin.H{"mfa_required":true}
+
+	if !user.MFA.Enabled{
+		secret:=utils.NewTOTPSecret()
+		if err:=users.SetMFA(c,user.UserID,models.UserMFA{PendingSecret: secret},time.Now());err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to start MFA enrolment!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+		response["mfa_enrollment_required"]=true
+		response["enrollment"]=mfaEnrolment(user,secret)
+	}
+
+	challenge,err:=utils.GenerateActionToken(c,tokens,user.UserID,models.ActionMFALogin)
+	if err!=nil{
+		ctx.JSON(http.StatusInternalServerError,gin.H{
+			"error":"⚠️ Failed to GENERATE MFA challenge!",
+			"status_code":http.StatusInternalServerError,
+		})
+		return
+	}
+	response["mfa_token"]=challenge
+	response["expires_in"]=int(utils.MFAChallengeLifetime.Seconds())
+
+	ctx.JSON(http.StatusOK,response)
+}
+
+// MFA settings are changed with the user's own log-in only, never with an API-key
+func rejectAPIKey(ctx *gin.Context)bool{
+	if utils.GetAuthMethodFromCtx(ctx)==utils.AuthMethodAPIKey{
+		ctx.JSON(http.StatusForbidden,gin.H{
+			"error":"⚠️ Not available with an API-key!",
+			"status_code":http.StatusForbidden,
+		})
+		return true
+	}
+	return false
+}
+
+// The logged-in user, writes the error response itself
+func currentUser(ctx *gin.Context,c context.Context,users repository.UserRepository)(*models.User,bool){
+	userId,err:=utils.GetUserIdFromCtx(ctx)
+	if err!=nil{
+		ctx.JSON(http.StatusBadRequest,gin.H{
+			"error":"⚠️ ID NOT FOUND IN CONTEXT!",
+			"status_code":http.StatusBadRequest,
+		})
+		return nil,false
+	}
+	user,err:=users.FindByUserID(c,userId)
+	if err!=nil{
+		if errors.Is(err,repository.ErrNotFound){
+			ctx.JSON(http.StatusNotFound,gin.H{
+				"error":"⚠️ User NOT FOUND!",
+				"status_code":http.StatusNotFound,
+			})
+			return nil,false
+		}
+		ctx.JSON(http.StatusInternalServerError,gin.H{
+			"error":"⚠️ Failed to fetch user!",
+			"status_code":http.StatusInternalServerError,
+		})
+		return nil,false
+	}
+	return user,true
+}
+
+func invalidMFACode(ctx *gin.Context){
+	ctx.JSON(http.StatusUnauthorized,gin.H{
+		"error":"⚠️ Invalid MFA code!",
+		"status_code":http.StatusUnauthorized,
+	})
+}
+
+//! 1️⃣ POST Second step of the log-in: the challenge from POST /login plus a TOTP/recovery-code
+func LoginMFAHandler(users repository.UserRepository,sessions repository.SessionRepository,tokens repository.ActionTokenRepository,guard *utils.LoginGuard)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		var req mfaLoginRequest
+		if !bindAccountRequest(ctx,&req){
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		// Single-use: a wrong code means starting over with the password (no guessing within one challenge)
+		claims,err:=utils.ConsumeActionToken(c,tokens,req.MFAToken,models.ActionMFALogin)
+		if err!=nil{
+			if !errors.Is(err,utils.ErrInvalidActionToken){
+				log.Println("⚠️ ERROR consuming MFA challenge ---",err)
+			}
+			ctx.JSON(http.StatusUnauthorized,gin.H{
+				"error":"⚠️ MFA challenge invalid or expired, please log in again!",
+				"status_code":http.StatusUnauthorized,
+			})
+			return
+		}
+
+		user,err:=users.FindByUserID(c,claims.UserId)
+		if err!=nil{
+			ctx.JSON(http.StatusUnauthorized,gin.H{
+				"error":"⚠️ MFA challenge invalid or expired, please log in again!",
+				"status_code":http.StatusUnauthorized,
+			})
+			return
+		}
+		// Disabled (or reset) by an admin since the password was checked
+		if loginRefused(ctx,user){
+			return
+		}
+
+		var recoveryCodes []string
+		ok:=false
+		if user.MFA.Enabled{
+			ok,err=verifyMFA(c,users,user,req.Code,req.RecoveryCode)
+		}else{
+			recoveryCodes,ok,err=activateMFA(c,users,user,req.Code)
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to verify MFA code!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+		if !ok{
+			// Counts like a wrong password, the password alone doesn't get to guess codes forever
+			if err:=guard.Fail(c,user.Email,ctx.ClientIP(),user.UserID);err!=nil{
+				log.Println("⚠️ ERROR recording failed log-in ---",err)
+			}
+			ctx.JSON(http.StatusUnauthorized,gin.H{
+				"error":"⚠️ Invalid MFA code, please log in again!",
+				"status_code":http.StatusUnauthorized,
+			})
+			return
+		}
+
+		completeLogin(ctx,c,sessions,guard,user,recoveryCodes)
+	}
+}
+
+//! 2️⃣ GET the caller's MFA status
+func GetMFAStatusHandler(users repository.UserRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,ok:=currentUser(ctx,c,users)
+		if !ok{
+			return
+		}
+
+		ctx.JSON(http.StatusOK,gin.H{
+			"enabled":user.MFA.Enabled,
+			"required":utils.MFARequired(user.Role),
+			"enrolled_at":user.MFA.EnrolledAt,
+			"recovery_codes_left":len(user.MFA.RecoveryCodes),
+		})
+	}
+}
+
+//! 3️⃣ POST Start an enrolment: a new secret, confirmed with POST /me/mfa/activate
+func EnrollMFAHandler(users repository.UserRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		if rejectAPIKey(ctx){
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,ok:=currentUser(ctx,c,users)
+		if !ok{
+			return
+		}
+		if user.MFA.Enabled{
+			ctx.JSON(http.StatusConflict,gin.H{
+				"error":"⚠️ MFA is already enabled!",
+				"status_code":http.StatusConflict,
+			})
+			return
+		}
+
+		secret:=utils.NewTOTPSecret()
+		if err:=users.SetMFA(c,user.UserID,models.UserMFA{PendingSecret: secret},time.Now());err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to start MFA enrolment!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK,mfaEnrolment(user,secret))
+	}
+}
+
+//! 4️⃣ POST Confirm the enrolment with a code from the app, returns the recovery-codes (only this once)
+func ActivateMFAHandler(users repository.UserRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		if rejectAPIKey(ctx){
+			return
+		}
+
+		var req mfaCodeRequest
+		if !bindAccountRequest(ctx,&req){
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,ok:=currentUser(ctx,c,users)
+		if !ok{
+			return
+		}
+		if user.MFA.Enabled{
+			ctx.JSON(http.StatusConflict,gin.H{
+				"error":"⚠️ MFA is already enabled!",
+				"status_code":http.StatusConflict,
+			})
+			return
+		}
+
+		codes,ok,err:=activateMFA(c,users,user,req.Code)
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to enable MFA!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+		if !ok{
+			invalidMFACode(ctx)
+			return
+		}
+
+		ctx.JSON(http.StatusOK,gin.H{
+			"message":"MFA enabled ✅",
+			"recovery_codes":codes,
+		})
+	}
+}
+
+//! 5️⃣ POST New recovery-codes (the old ones stop working), needs a current TOTP code
+func RegenerateRecoveryCodesHandler(users repository.UserRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		if rejectAPIKey(ctx){
+			return
+		}
+
+		var req mfaCodeRequest
+		if !bindAccountRequest(ctx,&req){
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,ok:=currentUser(ctx,c,users)
+		if !ok{
+			return
+		}
+		if !user.MFA.Enabled{
+			ctx.JSON(http.StatusConflict,gin.H{
+				"error":"⚠️ MFA is not enabled!",
+				"status_code":http.StatusConflict,
+			})
+			return
+		}
+
+		ok,err:=verifyMFA(c,users,user,req.Code,"")
+		if err==nil && ok{
+			codes,hashes:=utils.NewRecoveryCodes()
+			mfa:=user.MFA
+			mfa.RecoveryCodes = hashes
+			if err=users.SetMFA(c,user.UserID,mfa,time.Now());err==nil{
+				ctx.JSON(http.StatusOK,gin.H{"recovery_codes":codes})
+				return
+			}
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to regenerate recovery-codes!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+		invalidMFACode(ctx)
+	}
+}
+
+//! 6️⃣ DELETE (disable) MFA, with a TOTP or recovery-code. Not possible for roles MFA is mandatory for
+func DisableMFAHandler(users repository.UserRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		if rejectAPIKey(ctx){
+			return
+		}
+
+		var req mfaCodeRequest
+		if !bindAccountRequest(ctx,&req){
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,ok:=currentUser(ctx,c,users)
+		if !ok{
+			return
+		}
+		if utils.MFARequired(user.Role){
+			ctx.JSON(http.StatusForbidden,gin.H{
+				"error":"⚠️ MFA is mandatory for your role!",
+				"status_code":http.StatusForbidden,
+			})
+			return
+		}
+		if !user.MFA.Enabled{
+			ctx.JSON(http.StatusConflict,gin.H{
+				"error":"⚠️ MFA is not enabled!",
+				"status_code":http.StatusConflict,
+			})
+			return
+		}
+
+		ok,err:=verifyMFA(c,users,user,req.Code,req.RecoveryCode)
+		if err==nil && ok{
+			err=users.SetMFA(c,user.UserID,models.UserMFA{},time.Now())
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to disable MFA!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+		if !ok{
+			invalidMFACode(ctx)
+			return
+		}
+
+		ctx.JSON(http.StatusOK,gin.H{"message":"MFA disabled ✅"})
+	}
+}
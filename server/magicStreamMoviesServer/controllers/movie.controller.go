@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log"
+	"math"
 	"net/http"
 	"os"
 	"strconv"
@@ -12,52 +13,203 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
-	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/database"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/ai"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/jobs"
 	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/recommend"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/search"
 	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
 	"github.com/joho/godotenv"
-	"github.com/tmc/langchaingo/llms/openai"
-	"go.mongodb.org/mongo-driver/v2/bson"
-	"go.mongodb.org/mongo-driver/v2/mongo"
-	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 // Validator instance
 var validate = validator.New()
 
-//! 1️⃣ GET All Movies
-func GetMoviesHandler(client *mongo.Client)gin.HandlerFunc{
+// Sort-options for GET /movies ("-" prefix = descending)
+var movieSortFields = map[string]bool{
+	repository.SortByTitle:true,
+	repository.SortByRanking:true,
+	repository.SortByImdbID:true,
+}
+
+const (
+	defaultMoviePageLimit int64 = 20
+	maxMoviePageLimit int64 = 100
+)
+
+// The sort-value of a page-token, only a plain value of the sort's type (a string for title/imdb_id, a whole number
+// for ranking). Anything else (e.g. {"$ne":null}) would end up as a query operator
+func movieCursorValue(sortBy string,value any)(any,bool){
+	switch sortBy{
+	case repository.SortByRanking:
+		num,ok:=value.(float64) // JSON numbers
+		if !ok || num!=math.Trunc(num) || math.Abs(num)>math.MaxInt32{
+			return nil,false
+		}
+		return int(num),true
+	case repository.SortByTitle,repository.SortByImdbID:
+		str,ok:=value.(string)
+		return str,ok
+	}
+	return nil,false
+}
+
+// Builds the movie-query out of the query-params (genre, min_ranking, max_ranking, title_prefix)
+func buildMovieQuery(ctx *gin.Context)(repository.MovieQuery,error){
+	var query repository.MovieQuery
+
+	if genre:=strings.TrimSpace(ctx.Query("genre")); genre!=""{
+		genres:=strings.Split(genre, ",")
+		for i:=range genres{
+			genres[i] = strings.TrimSpace(genres[i])
+		}
+		query.Genres = genres
+	}
+
+	if minRanking:=ctx.Query("min_ranking"); minRanking!=""{
+		val,err:=strconv.Atoi(minRanking)
+		if err!=nil{
+			return query,errors.New("min_ranking must be a number")
+		}
+		query.MinRanking = &val
+	}
+	if maxRanking:=ctx.Query("max_ranking"); maxRanking!=""{
+		val,err:=strconv.Atoi(maxRanking)
+		if err!=nil{
+			return query,errors.New("max_ranking must be a number")
+		}
+		query.MaxRanking = &val
+	}
+
+	query.TitlePrefix = strings.TrimSpace(ctx.Query("title_prefix"))
+
+	return query,nil
+}
+
+//! 1️⃣ GET All Movies (paginated, filterable & sortable)
+// Query-params: limit, after (next_page_token), sort, genre, min_ranking, max_ranking, title_prefix.
+// all=true returns the whole (filtered) list as a plain array, like before.
+// A logged-in caller (optional here) gets an on_watchlist flag on every movie.
+func GetMoviesHandler(movies repository.MovieRepository,watchlist repository.WatchlistRepository)gin.HandlerFunc{
 	return func(ctx *gin.Context) {
 		ctxt,cancel:=context.WithTimeout(ctx, 100*time.Second)
 		defer cancel()
 
-		var movies []models.Movie
-		var movieCollection *mongo.Collection = database.OpenCollection("movies",client)
+		query,err:=buildMovieQuery(ctx)
+		if err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ "+err.Error(),
+				"status":http.StatusBadRequest,
+			})
+			return 
+		}
 
-		cursor,err:= movieCollection.Find(ctxt, bson.M{})
+		sortParam:=ctx.DefaultQuery("sort","title")
+		query.Sort = strings.TrimPrefix(sortParam,"-")
+		query.Descending = strings.HasPrefix(sortParam,"-")
+		if !movieSortFields[query.Sort]{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Invalid sort! Use title, ranking or imdb_id (prefix with - for descending)",
+				"status":http.StatusBadRequest,
+			})
+			return 
+		}
+
+		// Unbounded list, only when explicitly asked for
+		if ctx.Query("all")=="true"{
+			allMovies,err:= movies.Find(ctxt, query)
+			if err!=nil{
+				ctx.JSON(http.StatusInternalServerError,gin.H{
+					"error":"⚠️ Failed to fetch movies!",
+					"status":http.StatusInternalServerError,
+				})
+				return 
+			}
+			ctx.JSON(http.StatusOK,withWatchlistFlags(ctx,ctxt,watchlist,allMovies))
+			return
+		}
+
+		limit:=defaultMoviePageLimit
+		if limitStr:=ctx.Query("limit"); limitStr!=""{
+			limit,err = strconv.ParseInt(limitStr,10,64)
+			if err!=nil || limit<1{
+				ctx.JSON(http.StatusBadRequest,gin.H{
+					"error":"⚠️ limit must be a positive number!",
+					"status":http.StatusBadRequest,
+				})
+				return 
+			}
+		}
+		limit = min(limit,maxMoviePageLimit)
 
+		// Total count of the filtered list (before the page-cursor)
+		total,err:=movies.Count(ctxt,query)
 		if err!=nil{
 			ctx.JSON(http.StatusInternalServerError,gin.H{
-				"error":"⚠️ Failed to fetch movies!",
+				"error":"⚠️ Failed to count movies!",
 				"status":http.StatusInternalServerError,
 			})
 			return 
 		}
-		defer cursor.Close(ctxt)	
 
-		if err = cursor.All(ctxt, &movies); err!=nil{
-				ctx.JSON(http.StatusInternalServerError,gin.H{
-				"error":"⚠️ Failed to decode movies!",
+		if after:=ctx.Query("after"); after!=""{
+			pageCursor,err:=utils.DecodePageCursor(after)
+			var lastValue any
+			valid:=err==nil && pageCursor.Sort==sortParam
+			if valid{
+				lastValue,valid = movieCursorValue(query.Sort,pageCursor.Value)
+			}
+			if !valid{
+				ctx.JSON(http.StatusBadRequest,gin.H{
+					"error":"⚠️ Invalid page token!",
+					"status":http.StatusBadRequest,
+				})
+				return 
+			}
+			query.After = &repository.MovieCursor{Value:lastValue, ID:pageCursor.ID}
+		}
+
+		// One extra, to know if there's a next page
+		query.Limit = limit+1
+
+		page,err:= movies.Find(ctxt, query)
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to fetch movies!",
 				"status":http.StatusInternalServerError,
 			})
 			return 
 		}
-		ctx.JSON(http.StatusOK,movies)
+
+		nextPageToken:=""
+		if int64(len(page))>limit{
+			page = page[:limit]
+			last:=page[len(page)-1]
+			nextPageToken,err = utils.EncodePageCursor(utils.PageCursor{
+				Sort: sortParam,
+				Value: repository.SortValue(last,query.Sort),
+				ID: last.ID.Hex(),
+			})
+			if err!=nil{
+				ctx.JSON(http.StatusInternalServerError,gin.H{
+					"error":"⚠️ Failed to create page token!",
+					"status":http.StatusInternalServerError,
+				})
+				return 
+			}
+		}
+
+		ctx.JSON(http.StatusOK,gin.H{
+			"movies":withWatchlistFlags(ctx,ctxt,watchlist,page),
+			"next_page_token":nextPageToken,
+			"total":total,
+		})
 	}
 }
 
-//! 2️⃣ GET Single Movie
-func GetSingleMovieHandler(client *mongo.Client)gin.HandlerFunc{
+//! 2️⃣ GET Single Movie (with the caller's on_watchlist flag & playback position)
+func GetSingleMovieHandler(movies repository.MovieRepository,watchlist repository.WatchlistRepository,history repository.WatchHistoryRepository,recorder *jobs.PlaybackRecorder)gin.HandlerFunc{
 	return func(ctx *gin.Context) {
 		c,cancel:=context.WithTimeout(ctx, 100*time.Second)
 		defer cancel() // always defer to free-up resources
@@ -70,10 +222,8 @@ func GetSingleMovieHandler(client *mongo.Client)gin.HandlerFunc{
 			})
 			return
 		}
-		var movieCollection *mongo.Collection = database.OpenCollection("movies",client)
-		var movie models.Movie
 
-		err:= movieCollection.FindOne(c, bson.M{"imdb_id":movieID}).Decode(&movie)	
+		movie,err:= movies.FindByImdbID(c, movieID)	
 		if err!=nil{
 			ctx.JSON(http.StatusNotFound,gin.H{
 				"error":"⚠️ Movie Not Found!",
@@ -81,12 +231,17 @@ func GetSingleMovieHandler(client *mongo.Client)gin.HandlerFunc{
 			})
 			return 
 		}
-		ctx.JSON(http.StatusOK,movie)
+		detail:=movieDetail{watchlistFlaggedMovie: watchlistFlaggedMovie{Movie: *movie}}
+		if flaggedMovies,ok:=withWatchlistFlags(ctx,c,watchlist,[]models.Movie{*movie}).([]watchlistFlaggedMovie);ok{
+			detail.watchlistFlaggedMovie = flaggedMovies[0]
+		}
+		detail.Playback = playbackOf(ctx,c,history,recorder,movie.ImdbID)
+		ctx.JSON(http.StatusOK,detail)
 	}	
 }
 
 //! 3️⃣ POST/Add Movie
-func AddMovieHandler(client *mongo.Client)gin.HandlerFunc{
+func AddMovieHandler(movies repository.MovieRepository)gin.HandlerFunc{
 	return func(ctx *gin.Context) {
 		c,cancel:=context.WithTimeout(ctx, 100*time.Second)
 		defer cancel() // always defer to free-up resources
@@ -111,9 +266,28 @@ func AddMovieHandler(client *mongo.Client)gin.HandlerFunc{
 			return 
 		}
 
-		// Finally, add/insert data into MongoDB
-		var movieCollection *mongo.Collection = database.OpenCollection("movies",client)
-		result,err:=movieCollection.InsertOne(c,movie)
+		exists,err:=movies.ImdbIDExists(c, movie.ImdbID)
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ ERROR checking existing movie!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return 
+		}
+
+		// Return if the imdb_id is already taken (tombstones included)
+		if exists{
+			ctx.JSON(http.StatusConflict,gin.H{
+				"error":"⚠️ Movie with this IMDB-ID already exists!",
+				"status_code":http.StatusConflict,
+			})
+			return 
+		}
+
+		movie.DeletedAt = nil
+
+		// Finally, add/insert the movie
+		err=movies.Insert(c,&movie)
 		if err!=nil{
 			ctx.JSON(http.StatusInternalServerError,gin.H{
 				"error":"⚠️ ERROR adding movie!",
@@ -121,28 +295,241 @@ func AddMovieHandler(client *mongo.Client)gin.HandlerFunc{
 			})
 			return 
 		}
-		ctx.JSON(http.StatusCreated,result) // DONE ✅
+		search.Invalidate()
+		ctx.JSON(http.StatusCreated,gin.H{"InsertedID":movie.ID}) // DONE ✅
 	}
 }
 
+// The admin-review & its ranking only change together, AI-ranked & recorded in the review-history (PATCH /update-review)
+func rejectReviewEdit(ctx *gin.Context){
+	ctx.JSON(http.StatusBadRequest,gin.H{
+		"error":"⚠️ admin_review & ranking are changed via /update-review!",
+		"status_code":http.StatusBadRequest,
+	})
+}
 
-//! 4️⃣ Update/PATCH Admin-Review (LangChain AI 🤖🧠)
-func AdminReviewUpdateHandler(client *mongo.Client)gin.HandlerFunc{
+//! 3️⃣.1️⃣ PUT/Replace Movie (movie:update)
+func ReplaceMovieHandler(movies repository.MovieRepository)gin.HandlerFunc{
 	return func(ctx *gin.Context) {
+		movieId:=ctx.Param("imdb_id")
+		if movieId == ""{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Movie-ID is required!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
 
-		role,err:=utils.GetRoleFromCtx(ctx)
+		var movie models.Movie
+		if err:=ctx.ShouldBindJSON(&movie);err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Invalid Input!",
+				"status_code":http.StatusBadRequest,
+			})
+			return 
+		}
+
+		// imdb_id is the identity of the movie, it can't be changed
+		if movie.ImdbID == ""{
+			movie.ImdbID = movieId
+		}
+		if movie.ImdbID != movieId{
+			ctx.JSON(http.StatusConflict,gin.H{
+				"error":"⚠️ IMDB-ID can't be changed!",
+				"status_code":http.StatusConflict,
+			})
+			return 
+		}
+
+		c,cancel:=context.WithTimeout(ctx, 100*time.Second)
+		defer cancel()
+
+		before,err:=movies.FindByImdbID(c,movieId)
+		if err==nil{
+			// Left out: the admin-review & ranking stay as they are
+			if movie.AdminReview=="" && movie.Ranking==(models.Ranking{}){
+				movie.AdminReview,movie.Ranking = before.AdminReview,before.Ranking
+			}
+			if movie.AdminReview!=before.AdminReview || movie.Ranking!=before.Ranking{
+				rejectReviewEdit(ctx)
+				return
+			}
+
+			if err:= validate.Struct(movie); err!=nil{
+				ctx.JSON(http.StatusBadRequest,gin.H{
+					"error":"⚠️ Validation failed!",
+					"status_code":http.StatusBadRequest,
+				})
+				return 
+			}
+			err=movies.Replace(c,movie)
+		}
+		if errors.Is(err,repository.ErrNotFound){
+			ctx.JSON(http.StatusNotFound,gin.H{
+				"error":"⚠️ Movie NOT FOUND!",
+				"status_code":http.StatusNotFound,
+			})
+			return
+		}
 		if err!=nil{
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"error":"⚠️ Member-ROLE not found in context!",
-				"status_code:":http.StatusBadRequest,
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to UPDATE movie!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return 
+		}
+
+		search.Invalidate()
+		ctx.JSON(http.StatusOK,movie)
+	}
+}
+
+//! 3️⃣.2️⃣ PATCH/Update Movie (movie:update)
+func UpdateMovieHandler(movies repository.MovieRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		movieId:=ctx.Param("imdb_id")
+		if movieId == ""{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Movie-ID is required!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		var req models.MovieUpdate
+		if err:=ctx.ShouldBindJSON(&req);err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Invalid Input!",
+				"status_code":http.StatusBadRequest,
+			})
+			return 
+		}
+
+		if req.ImdbID!=nil && *req.ImdbID!=movieId{
+			ctx.JSON(http.StatusConflict,gin.H{
+				"error":"⚠️ IMDB-ID can't be changed!",
+				"status_code":http.StatusConflict,
+			})
+			return 
+		}
+		if req.AdminReview!=nil || req.Ranking!=nil{
+			rejectReviewEdit(ctx)
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx, 100*time.Second)
+		defer cancel()
+
+		movie,err:=movies.FindByImdbID(c, movieId)
+		if err!=nil{
+			if errors.Is(err,repository.ErrNotFound){
+				ctx.JSON(http.StatusNotFound,gin.H{
+					"error":"⚠️ Movie NOT FOUND!",
+					"status_code":http.StatusNotFound,
+				})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to fetch movie!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		// Apply only the passed-in fields, then validate the whole movie
+		if req.Title!=nil{
+			movie.Title = *req.Title
+		}
+		if req.PosterPath!=nil{
+			movie.PosterPath = *req.PosterPath
+		}
+		if req.YouTubeID!=nil{
+			movie.YouTubeID = *req.YouTubeID
+		}
+		if req.Genre!=nil{
+			movie.Genre = *req.Genre
+		}
+
+		if err:= validate.Struct(movie); err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Validation failed!",
+				"status_code":http.StatusBadRequest,
+			})
+			return 
+		}
+
+		err=movies.Replace(c,*movie)
+		// Deleted in between
+		if errors.Is(err,repository.ErrNotFound){
+			ctx.JSON(http.StatusNotFound,gin.H{
+				"error":"⚠️ Movie NOT FOUND!",
+				"status_code":http.StatusNotFound,
+			})
+			return
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to UPDATE movie!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return 
+		}
+
+		search.Invalidate()
+		ctx.JSON(http.StatusOK,movie)
+	}
+}
+
+//! 3️⃣.3️⃣ DELETE Movie (movie:delete, soft-delete)
+func DeleteMovieHandler(movies repository.MovieRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		movieId:=ctx.Param("imdb_id")
+		if movieId == ""{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Movie-ID is required!",
+				"status_code":http.StatusBadRequest,
 			})
 			return
 		}
 
-		if role!="ADMIN"{
-			ctx.JSON(http.StatusUnauthorized, gin.H{
-				"error":"⚠️ User must be part of the ADMIN role!",
-				"status_code:":http.StatusUnauthorized,
+		c,cancel:=context.WithTimeout(ctx, 100*time.Second)
+		defer cancel()
+
+		// Keep the document as a tombstone, so the imdb_id can't be re-used by accident
+		err:=movies.SoftDelete(c,movieId,time.Now())
+		if errors.Is(err,repository.ErrNotFound){
+			ctx.JSON(http.StatusNotFound,gin.H{
+				"error":"⚠️ Movie NOT FOUND!",
+				"status_code":http.StatusNotFound,
+			})
+			return
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to DELETE movie!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return 
+		}
+
+		search.Invalidate()
+		ctx.JSON(http.StatusOK,gin.H{
+			"message":"Movie deleted ✅",
+			"imdb_id":movieId,
+		})
+	}
+}
+
+
+//! 4️⃣ Update/PATCH Admin-Review (LangChain AI 🤖🧠)
+func AdminReviewUpdateHandler(movies repository.MovieRepository,rankings repository.RankingRepository,history repository.ReviewHistoryRepository,ranker ai.SentimentRanker)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+
+		userId,err:=utils.GetUserIdFromCtx(ctx)
+		if err!=nil{
+			ctx.JSON(http.StatusBadRequest ,gin.H{
+				"error":"⚠️ ID NOT FOUND IN CONTEXT!",
+				"status_code:":http.StatusBadRequest,
 			})
 			return
 		}
@@ -177,52 +564,79 @@ func AdminReviewUpdateHandler(client *mongo.Client)gin.HandlerFunc{
 			return 
 			}
 
-			// AI to extract the sentiment of the admin-review ✨
-			sentiment,rankVal,err:= GetReviewRanking(req.AdminReview,client,ctx)
-			if err!=nil{
+		// clearing resources
+		var ctxt,cancel = context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		// Current review/ranking, for the history (and no AI-call for a missing movie)
+		before,err:=movies.FindByImdbID(ctxt,movieId)
+		if errors.Is(err,repository.ErrNotFound){
+			ctx.JSON(http.StatusNotFound ,gin.H{
+				"error":"⚠️ Movie NOT FOUND!",
+				"status_code:":http.StatusNotFound,
+			})
+			return
+		}	
+		if err!=nil{
 				ctx.JSON(http.StatusInternalServerError, gin.H{
-				"error":"⚠️ ERROR getting review ranking!",
+				"error":"⚠️ Failed to fetch movie!",
 				"status_code:":http.StatusInternalServerError,
 			})
 			return 
 			}
 
-			// Update/PATCH - 
-			filter:=bson.M{"imdb_id":movieId}
-			update:=bson.M{
-				"$set":bson.M{
-					"admin_review":req.AdminReview,
-					"ranking":bson.M{
-						"ranking_value":rankVal,
-						"ranking_name":sentiment,
-					},
-				},
+			// AI to extract the sentiment of the admin-review ✨
+			classification,err:= GetReviewRanking(req.AdminReview,rankings,ranker,ctx)
+			if errors.Is(err,ai.ErrUnknownRanking){
+				ctx.JSON(http.StatusBadGateway, gin.H{
+				"error":"⚠️ AI answered with an unknown ranking, review NOT saved!",
+				"details":err.Error(),
+				"status_code:":http.StatusBadGateway,
+			})
+			return 
 			}
-
-		// clearing resources
-		var ctxt,cancel = context.WithTimeout(ctx,100*time.Second)
-		defer cancel()
-
-		var movieCollection *mongo.Collection = database.OpenCollection("movies",client)
-		result,err:=movieCollection.UpdateOne(ctxt,filter,update)
-		if err!=nil{
+			if err!=nil{
 				ctx.JSON(http.StatusInternalServerError, gin.H{
-				"error":"⚠️ Failed to UPDATE movie!",
+				"error":"⚠️ ERROR getting review ranking!",
 				"status_code:":http.StatusInternalServerError,
 			})
 			return 
 			}
 
-		if result.MatchedCount==0{
+		// Update/PATCH - 
+		err=movies.UpdateReview(ctxt,movieId,req.AdminReview,classification.Ranking)
+		if errors.Is(err,repository.ErrNotFound){
 			ctx.JSON(http.StatusNotFound ,gin.H{
 				"error":"⚠️ Movie NOT FOUND!",
 				"status_code:":http.StatusNotFound,
 			})
 			return
 		}	
+		if err!=nil{
+				ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":"⚠️ Failed to UPDATE movie!",
+				"status_code:":http.StatusInternalServerError,
+			})
+			return 
+			}
+
+		search.Invalidate()
+
+		recordReviewHistory(ctxt,history,&models.ReviewHistory{
+			ImdbID: movieId,
+			Action: models.ReviewActionUpdate,
+			AdminUserID: userId,
+			ReviewBefore: before.AdminReview,
+			ReviewAfter: req.AdminReview,
+			RankingBefore: before.Ranking,
+			RankingAfter: classification.Ranking,
+			RawResponse: classification.Result.Response,
+			Provider: classification.Result.Provider,
+			PromptVersion: ai.PromptVersion(),
+		})
 
 		// Create a response
-		resp.RankingName = sentiment
+		resp.RankingName = classification.Ranking.RankingName
 		resp.AdminReview = req.AdminReview
 
 		ctx.JSON(http.StatusOK, resp)
@@ -230,81 +644,88 @@ func AdminReviewUpdateHandler(client *mongo.Client)gin.HandlerFunc{
 		}
 }
 
- func GetReviewRanking(admin_review string,client *mongo.Client,ctx *gin.Context) (string, int, error) {
+ func GetReviewRanking(admin_review string,rankingRepo repository.RankingRepository,ranker ai.SentimentRanker,ctx *gin.Context) (ai.Classification, error) {
 
-	rankings, err := GetRankings(client,ctx)
+	rankings, err := GetRankings(rankingRepo,ctx)
 	if err != nil {
-		return "", 0, err
-	}
-
-	sentimentDelimited := ""
-	for _, ranking := range rankings {
-		if ranking.RankingValue != 999 {
-			sentimentDelimited += ranking.RankingName + ","
-		}
+		return ai.Classification{}, err
 	}
-	sentimentDelimited = strings.Trim(sentimentDelimited, ",")
 
-	_ = godotenv.Load(".env")
-
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
-		return "", 0, errors.New("could not read OPENAI_API_KEY")
-	}
-
-	llm, err := openai.New(openai.WithToken(apiKey))
-	if err != nil {
-		return "", 0, err
-	}
+	// Validated against the rankings (normalised/fuzzy, with corrective retries)
+	return ai.Classify(ctx.Request.Context(), ranker, admin_review, rankings)
+}
 
-	basePrompt := os.Getenv("BASE_PROMPT_TEMPLATE")
-	prompt := strings.Replace(basePrompt, "{rankings}", sentimentDelimited, 1)
 
-	response, err := llm.Call(ctx.Request.Context(), prompt+admin_review)
-	if err != nil {
-		return "", 0, err
-	}
-
-	response = strings.TrimSpace(response)
+ func GetRankings(rankingRepo repository.RankingRepository, ctx *gin.Context)([]models.Ranking,error){
+	var ctxt,cancel = context.WithTimeout(ctx,100*time.Second)
+	defer cancel()
 
-	rankVal := 999
-	for _, ranking := range rankings {
-		if ranking.RankingName == response {
-			response = strings.TrimSpace(response) // FIX
-			rankVal = ranking.RankingValue
-			break
-		}
+	rankings,err:=rankingRepo.List(ctxt)
+	if err!=nil{
+		log.Println("⚠️ ERROR:",err.Error())
+		return nil,err
 	}
 
-	return response, rankVal, nil
+	return  rankings,nil
 }
 
 
- func GetRankings(client *mongo.Client, ctx *gin.Context)([]models.Ranking,error){
-	var rankings []models.Ranking
+// A recommended movie, with its score & why it's recommended
+type recommendedMovie struct{
+	models.Movie
+	Score float64 `json:"score"`
+	Explanation string `json:"explanation"`
+}
 
-	var ctxt,cancel = context.WithTimeout(ctx,100*time.Second)
-	defer cancel()
+// Caps of the recommendations' queries: the latest watched/reviewed movies that make up the genre-affinity (the
+// seen ones are left out all the same), and the candidates of the liked genres & by ranking
+const (
+	recommendProfileLimit = 200
+	recommendCandidateLimit = 200
+)
 
-	var rankingCollection *mongo.Collection = database.OpenCollection("rankings",client)
-	cursor,err:=rankingCollection.Find(ctxt, bson.M{})
-	if err!=nil{
-		log.Println("⚠️ ERROR:",err.Error())
-		return nil,err
+// loadRecommendCandidates loads the profile's movies (their genres make up the affinity) and the unseen candidates:
+// the best-ranked movies of the liked genres, and the best-ranked ones overall (for users without much of a profile)
+func loadRecommendCandidates(ctx context.Context,movies repository.MovieRepository,profile recommend.Profile)([]models.Movie,error){
+	var profileMovies []models.Movie
+	if imdbIDs:=profile.ImdbIDs();len(imdbIDs)>0{
+		var err error
+		profileMovies,err = movies.Find(ctx,repository.MovieQuery{ImdbIDs: imdbIDs})
+		if err!=nil{
+			return nil,err
+		}
 	}
-	defer cursor.Close(ctxt)
 
-	if err:=cursor.All(ctxt, &rankings); err!=nil{
-		log.Println("⚠️ ERROR:",err.Error())
-		return nil,err
+	ranked:=1 // leaves out the never-ranked (0) ones, they'd come first
+	queries:=[]repository.MovieQuery{{MinRanking: &ranked,ExcludeImdbIDs: profile.Seen,Sort: repository.SortByRanking,Limit: recommendCandidateLimit}}
+	if genres:=recommend.LikedGenres(profile,profileMovies);len(genres)>0{
+		// Not ranked (0) ones included, the genre weighs more than the ranking
+		queries = append(queries,repository.MovieQuery{Genres: genres,ExcludeImdbIDs: profile.Seen,Sort: repository.SortByRanking,Limit: recommendCandidateLimit})
 	}
 
-	return  rankings,nil
+	catalog:=profileMovies
+	loaded:=map[string]bool{}
+	for _,movie:=range catalog{
+		loaded[movie.ImdbID] = true
+	}
+	for _,query:=range queries{
+		candidates,err:=movies.Find(ctx,query)
+		if err!=nil{
+			return nil,err
+		}
+		for _,movie:=range candidates{
+			if !loaded[movie.ImdbID]{
+				loaded[movie.ImdbID] = true
+				catalog = append(catalog,movie)
+			}
+		}
+	}
+	return catalog,nil
 }
 
-
- //! 5️⃣ GET Recommended-Movies
- func GetRecommendedMoviesHandler(client *mongo.Client)gin.HandlerFunc{
+ //! 5️⃣ GET Recommended-Movies: scored on the user's genres (favourite, watched, watchlist, rated) & the Ranking,
+ // without the ones they've seen, spread across genres. Every movie says why it's recommended
+func GetRecommendedMoviesHandler(store *repository.Store)gin.HandlerFunc{
 	return func(ctx *gin.Context) {
 		userId,err:=utils.GetUserIdFromCtx(ctx)
 		if err!=nil{
@@ -315,16 +736,6 @@ func AdminReviewUpdateHandler(client *mongo.Client)gin.HandlerFunc{
 			return
 		}
 
-		// Query the db.
-		favourite_genres,err:=GetUsersFavGenres(userId,client,ctx)
-		if err!=nil{
-			ctx.JSON(http.StatusInternalServerError ,gin.H{
-				"error:":err.Error(),
-				"status_code:":http.StatusInternalServerError,
-			})
-			return
-		}
-
 		err = godotenv.Load(".env")
 		if err!=nil{
 			log.Println("⚠️ WARNING: .env file not found!")
@@ -337,21 +748,40 @@ func AdminReviewUpdateHandler(client *mongo.Client)gin.HandlerFunc{
 			recommendedMovieLimitVal,_= strconv.ParseInt(recommendedMovieLimitStr,10,64)
 		}
 
-		findOptions:=options.Find()
-		findOptions.SetSort(bson.D{{Key:"ranking.ranking_value", Value:1}})
-
-		findOptions.SetLimit(recommendedMovieLimitVal)
-
-		filter:=bson.M{"genre.genre_name":bson.M{"$in":favourite_genres}}
-
 		var ctxt,cancel = context.WithTimeout(ctx,100*time.Second)
 		defer cancel()
 
-		var movieCollection *mongo.Collection = database.OpenCollection("movies",client)
-		cursor,err:=movieCollection.Find(ctxt,filter,findOptions)
-
-			
-
+		// What's known about the user
+		var profile recommend.Profile
+		user,err:=store.Users.FindByUserID(ctxt,userId)
+		if err==nil{
+			profile.FavouriteGenres = user.FavouriteGenres
+		}else if errors.Is(err,repository.ErrNotFound){
+			err = nil
+		}
+		if err==nil{
+			// The latest ones only, they say enough about the user's taste
+			profile.History,err = store.WatchHistory.Find(ctxt,repository.WatchHistoryQuery{UserID: userId,Limit: recommendProfileLimit})
+		}
+		if err==nil{
+			profile.Watchlist,err = store.Watchlist.List(ctxt,userId)
+		}
+		if err==nil{
+			profile.Reviews,err = store.UserReviews.Find(ctxt,repository.UserReviewQuery{UserID: userId,Limit: recommendProfileLimit})
+		}
+		// Every seen movie (only the IDs), the capped lists above don't hold the older ones
+		var watched,reviewed []string
+		if err==nil{
+			watched,err = store.WatchHistory.ImdbIDsForUser(ctxt,userId)
+		}
+		if err==nil{
+			reviewed,err = store.UserReviews.ImdbIDsForUser(ctxt,userId)
+		}
+		profile.Seen = append(watched,reviewed...)
+		var catalog []models.Movie
+		if err==nil{
+			catalog,err = loadRecommendCandidates(ctxt,store.Movies,profile)
+		}
 		if err!=nil{
 			ctx.JSON(http.StatusInternalServerError ,gin.H{
 				"error":"⚠️ ERROR fetching recommended-movies!",
@@ -360,89 +790,31 @@ func AdminReviewUpdateHandler(client *mongo.Client)gin.HandlerFunc{
 			return
 		}
 
-		defer cursor.Close(ctxt)
-		var recommendedMovies []models.Movie
-
-		if err:=cursor.All(ctxt,&recommendedMovies);err!=nil{
-			ctx.JSON(http.StatusInternalServerError ,gin.H{
-				"error":err.Error(),
-				"status_code:":http.StatusInternalServerError,
+		recommendations:=recommend.Recommend(profile,catalog,int(recommendedMovieLimitVal))
+		recommendedMovies:=make([]recommendedMovie,0,len(recommendations))
+		for _,recommendation:=range recommendations{
+			recommendedMovies = append(recommendedMovies,recommendedMovie{
+				Movie: recommendation.Movie,
+				Score: recommendation.Score,
+				Explanation: recommendation.Explanation,
 			})
-			return
 		}
-
 		ctx.JSON(http.StatusOK, recommendedMovies)
 	}
  }
 
-
- func GetUsersFavGenres(userId string,client *mongo.Client, c *gin.Context)([]string,error){
-	
-	var ctxt,cancel = context.WithTimeout(c,100*time.Second)
-	defer cancel()
-
-	filter:=bson.M{"user_id":userId}
-	projection:=bson.M{
-		"favourite_genres.genre_name":1,
-		"_id":0,
-	}
-
-	opts:= options.FindOne().SetProjection(projection)
-
-	var results bson.M
-   var userCollection *mongo.Collection = database.OpenCollection("users",client)
-	err:=userCollection.FindOne(ctxt,filter,opts).Decode(&results)
-	if err!=nil{
-		if err==mongo.ErrNoDocuments{
-			return []string{},nil
-		}
-		return []string{}, err // FIX: stop execution
-	}
-	
-		favGenresArr,ok:=results["favourite_genres"].(bson.A)
-		if !ok{
-			return []string{},errors.New("Unable to retrieve favourite genres for user!")
-		}
-	
-		var genreNames []string
-
-		for _,item:=range favGenresArr{
-			if genreMap, ok:=item.(bson.D);ok{
-				for _,elem:=range genreMap{
-					if elem.Key=="genre_name"{
-						if name,ok:=elem.Value.(string);ok{
-							genreNames = append(genreNames, name)
-						}
-					}
-				}
-			}
-		}
-
-		return genreNames,nil
- }
-
   //! 6️⃣ GET Genres
-  func GetGenresHandler(client *mongo.Client) gin.HandlerFunc {
+  func GetGenresHandler(genreRepo repository.GenreRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var ctx, cancel = context.WithTimeout(c, 100*time.Second)
 		defer cancel()
 
-		var genreCollection *mongo.Collection = database.OpenCollection("genres", client)
-
-		cursor, err := genreCollection.Find(ctx, bson.D{})
+		genres, err := genreRepo.List(ctx)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching movie genres"})
 			return
 		}
-		defer cursor.Close(ctx)
-
-		var genres []models.Genre
-		if err := cursor.All(ctx, &genres); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
 		c.JSON(http.StatusOK, genres)
 
 	}
 }
- 
\ No newline at end of file
@@ -0,0 +1,222 @@
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
+)
+
+type moviePage struct {
+	Movies        []models.Movie `json:"movies"`
+	NextPageToken string         `json:"next_page_token"`
+}
+
+func TestGetMoviesPagesThroughTheCatalog(t *testing.T) {
+	for _, tc := range []struct {
+		sort string
+		want []string
+	}{
+		{"title", []string{"Alpha", "Bravo", "Charlie"}},
+		{"-ranking", []string{"Bravo", "Alpha", "Charlie"}},
+		{"imdb_id", []string{"Charlie", "Alpha", "Bravo"}},
+	} {
+		t.Run(tc.sort, func(t *testing.T) {
+			api := newTestAPI(t)
+			api.seedCatalog()
+
+			var titles []string
+			after := ""
+			for range 5 {
+				rec := api.do(http.MethodGet, "/movies?limit=1&sort="+url.QueryEscape(tc.sort)+"&after="+after, nil)
+				if rec.Code != http.StatusOK {
+					t.Fatalf("GET /movies: %d %s", rec.Code, rec.Body)
+				}
+				page := decode[moviePage](t, rec)
+				for _, movie := range page.Movies {
+					titles = append(titles, movie.Title)
+				}
+				if page.NextPageToken == "" {
+					break
+				}
+				after = page.NextPageToken
+			}
+
+			if len(titles) != len(tc.want) {
+				t.Fatalf("got %v, want %v", titles, tc.want)
+			}
+			for i := range titles {
+				if titles[i] != tc.want[i] {
+					t.Fatalf("got %v, want %v", titles, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetMoviesRejectsForgedPageTokens(t *testing.T) {
+	api := newTestAPI(t)
+	api.seedCatalog()
+
+	for name, tc := range map[string]struct {
+		sort   string
+		cursor utils.PageCursor
+	}{
+		"operator as value": {"title", utils.PageCursor{Sort: "title", Value: map[string]any{"$ne": nil}, ID: "6ad4785d7a18a02fb6cbdeb2"}},
+		"number for title":  {"title", utils.PageCursor{Sort: "title", Value: 3, ID: "6ad4785d7a18a02fb6cbdeb2"}},
+		"fraction ranking":  {"ranking", utils.PageCursor{Sort: "ranking", Value: 1.5, ID: "6ad4785d7a18a02fb6cbdeb2"}},
+		"other sort":        {"ranking", utils.PageCursor{Sort: "title", Value: "Alpha", ID: "6ad4785d7a18a02fb6cbdeb2"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			token, err := utils.EncodePageCursor(tc.cursor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rec := api.do(http.MethodGet, "/movies?sort="+tc.sort+"&after="+token, nil)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("got %d %s, want 400", rec.Code, rec.Body)
+			}
+		})
+	}
+}
+
+func TestGetSingleMovie(t *testing.T) {
+	api := newTestAPI(t)
+	api.seedCatalog()
+	cookies := api.login("viewer@example.com")
+
+	rec := api.do(http.MethodGet, "/movie/tt2", nil, cookies...)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /movie/tt2: %d %s", rec.Code, rec.Body)
+	}
+	if movie := decode[models.Movie](t, rec); movie.Title != "Alpha" {
+		t.Fatalf("got %q, want Alpha", movie.Title)
+	}
+
+	if rec := api.do(http.MethodGet, "/movie/tt404", nil, cookies...); rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown movie: got %d, want 404", rec.Code)
+	}
+	if rec := api.do(http.MethodGet, "/movie/tt2", nil); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("no cookies: got %d, want 401", rec.Code)
+	}
+}
+
+func TestMovieEditsLeaveTheAdminReviewAlone(t *testing.T) {
+	api, admin, _ := catalogAdmins(t)
+
+	rec := api.do(http.MethodPatch, "/movie/tt2", map[string]any{"title": "Alpha II"}, admin...)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PATCH title: %d %s", rec.Code, rec.Body)
+	}
+	for _, body := range []map[string]any{
+		{"admin_review": "Loved it"},
+		{"ranking": models.Ranking{RankingValue: 1, RankingName: "Excellent"}},
+	} {
+		if rec := api.do(http.MethodPatch, "/movie/tt2", body, admin...); rec.Code != http.StatusBadRequest {
+			t.Fatalf("PATCH %v: got %d %s, want 400", body, rec.Code, rec.Body)
+		}
+	}
+
+	movie := models.Movie{
+		ImdbID:     "tt2",
+		Title:      "Alpha III",
+		PosterPath: "https://example.com/poster.jpg",
+		YouTubeID:  "yt",
+		Genre:      []models.Genre{{GenreID: 3, GenreName: "Western"}},
+		Ranking:    models.Ranking{RankingValue: 1, RankingName: "Excellent"},
+	}
+	if rec := api.do(http.MethodPut, "/movie/tt2", movie, admin...); rec.Code != http.StatusBadRequest {
+		t.Fatalf("PUT with another ranking: got %d %s, want 400", rec.Code, rec.Body)
+	}
+	movie.Ranking = models.Ranking{}
+	if rec := api.do(http.MethodPut, "/movie/tt2", movie, admin...); rec.Code != http.StatusOK {
+		t.Fatalf("PUT without the ranking: %d %s", rec.Code, rec.Body)
+	}
+
+	got := decode[models.Movie](t, api.do(http.MethodGet, "/movie/tt2", nil, admin...))
+	if got.Title != "Alpha III" || got.Ranking.RankingName != "Good" {
+		t.Fatalf("got %q ranked %q, want Alpha III still ranked Good", got.Title, got.Ranking.RankingName)
+	}
+}
+
+func TestRecommendationsLearnFromTheProfilesMovies(t *testing.T) {
+	api := newTestAPI(t)
+	api.seedCatalog()
+	western := []models.Genre{{GenreID: 3, GenreName: "Western"}}
+	for _, movie := range []models.Movie{
+		{ImdbID: "tt4", Title: "Delta", Genre: western, Ranking: models.Ranking{RankingValue: 3, RankingName: "Okay"}},
+		{ImdbID: "tt5", Title: "Echo", Genre: western},
+	} {
+		if err := api.store.Movies.Insert(context.Background(), &movie); err != nil {
+			t.Fatalf("seeding movie: %v", err)
+		}
+	}
+	cookies := api.login("alice@example.com")
+
+	if rec := api.do(http.MethodPut, "/movie/tt4/review", models.UserReviewInput{Rating: 10}, cookies...); rec.Code != http.StatusCreated {
+		t.Fatalf("review: %d %s", rec.Code, rec.Body)
+	}
+
+	rec := api.do(http.MethodGet, "/recommended-movies", nil, cookies...)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /recommended-movies: %d %s", rec.Code, rec.Body)
+	}
+	explanations := map[string]string{}
+	for _, movie := range decode[[]struct {
+		ImdbID      string `json:"imdb_id"`
+		Explanation string `json:"explanation"`
+	}](t, rec) {
+		explanations[movie.ImdbID] = movie.Explanation
+	}
+	if _, ok := explanations["tt4"]; ok {
+		t.Fatal("recommended tt4, which alice reviewed already")
+	}
+	// Never ranked, it's only a candidate by its (liked) genre
+	if got := explanations["tt5"]; got != "because you liked Western" {
+		t.Fatalf("tt5: got %q, want it recommended because you liked Western", got)
+	}
+	if _, ok := explanations["tt1"]; !ok {
+		t.Fatalf("got %v, want the best-ranked tt1 among them", explanations)
+	}
+}
+
+func TestRecommendationsLeaveOutMoviesReviewedLongAgo(t *testing.T) {
+	api := newTestAPI(t)
+	api.seedCatalog()
+	cookies := api.login("alice@example.com")
+	alice := api.userID("alice@example.com")
+
+	// More reviews than the profile-cap, the oldest one of the best-ranked movie
+	ctx := context.Background()
+	drama := []models.Genre{{GenreID: 2, GenreName: "Drama"}}
+	for i := range 250 {
+		ranking := models.Ranking{RankingValue: 3, RankingName: "Okay"}
+		if i == 0 {
+			ranking = models.Ranking{RankingValue: 1, RankingName: "Excellent"}
+		}
+		movie := models.Movie{ImdbID: fmt.Sprintf("tt%d", 100+i), Title: fmt.Sprintf("Seen %d", i), Genre: drama, Ranking: ranking}
+		if err := api.store.Movies.Insert(ctx, &movie); err != nil {
+			t.Fatalf("seeding movie: %v", err)
+		}
+		review := models.UserReview{ReviewID: fmt.Sprintf("r%d", i), ImdbID: movie.ImdbID, UserID: alice, Rating: 9}
+		if _, err := api.store.UserReviews.Upsert(ctx, &review); err != nil {
+			t.Fatalf("seeding review: %v", err)
+		}
+	}
+
+	rec := api.do(http.MethodGet, "/recommended-movies", nil, cookies...)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /recommended-movies: %d %s", rec.Code, rec.Body)
+	}
+	var got []string
+	for _, movie := range decode[[]models.Movie](t, rec) {
+		got = append(got, movie.ImdbID)
+	}
+	if fmt.Sprint(got) != "[tt1 tt2 tt3]" {
+		t.Fatalf("got %v, want only the unseen tt1 tt2 tt3", got)
+	}
+}
@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/search"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	defaultHistoryLimit int64 = 50
+	maxHistoryLimit int64 = 500
+)
+
+// Appends to the review-history. The movie is already updated at this point, so a failure is only logged
+func recordReviewHistory(c context.Context,history repository.ReviewHistoryRepository,entry *models.ReviewHistory){
+	entry.HistoryID = bson.NewObjectID().Hex()
+	entry.CreatedAt = time.Now()
+
+	if err:=history.Append(c,entry);err!=nil{
+		log.Printf("⚠️ ERROR recording review-history for %s --- %v",entry.ImdbID,err)
+	}
+}
+
+//! 1️⃣ GET Review-History of a movie (review:history, newest first)
+func GetReviewHistoryHandler(history repository.ReviewHistoryRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		limit:=defaultHistoryLimit
+		if limitStr:=ctx.Query("limit"); limitStr!=""{
+			val,err:=strconv.ParseInt(limitStr,10,64)
+			if err!=nil || val<1{
+				ctx.JSON(http.StatusBadRequest,gin.H{
+					"error":"⚠️ limit must be a positive number!",
+					"status_code":http.StatusBadRequest,
+				})
+				return
+			}
+			limit = min(val,maxHistoryLimit)
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		entries,err:=history.ListByMovie(c,ctx.Param("imdb_id"),limit)
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to fetch review-history!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+		ctx.JSON(http.StatusOK,entries)
+	}
+}
+
+//! 2️⃣ POST Revert a movie to the review/ranking of a history-entry (review:write, no AI-call)
+func RevertReviewHandler(movies repository.MovieRepository,history repository.ReviewHistoryRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		userId,err:=utils.GetUserIdFromCtx(ctx)
+		if err!=nil{
+			ctx.JSON(http.StatusBadRequest ,gin.H{
+				"error":"⚠️ ID NOT FOUND IN CONTEXT!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		movieId:=ctx.Param("imdb_id")
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		entry,err:=history.FindByHistoryID(c,ctx.Param("history_id"))
+		if errors.Is(err,repository.ErrNotFound) || (err==nil && entry.ImdbID!=movieId){
+			ctx.JSON(http.StatusNotFound,gin.H{
+				"error":"⚠️ History-entry NOT FOUND for this movie!",
+				"status_code":http.StatusNotFound,
+			})
+			return
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to fetch review-history!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		before,err:=movies.FindByImdbID(c,movieId)
+		if err==nil{
+			err=movies.UpdateReview(c,movieId,entry.ReviewAfter,entry.RankingAfter)
+		}
+		if errors.Is(err,repository.ErrNotFound){
+			ctx.JSON(http.StatusNotFound,gin.H{
+				"error":"⚠️ Movie NOT FOUND!",
+				"status_code":http.StatusNotFound,
+			})
+			return
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to UPDATE movie!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		search.Invalidate()
+
+		revert:=&models.ReviewHistory{
+			ImdbID: movieId,
+			Action: models.ReviewActionRevert,
+			AdminUserID: userId,
+			ReviewBefore: before.AdminReview,
+			ReviewAfter: entry.ReviewAfter,
+			RankingBefore: before.Ranking,
+			RankingAfter: entry.RankingAfter,
+			RevertedFrom: entry.HistoryID,
+		}
+		recordReviewHistory(c,history,revert)
+
+		ctx.JSON(http.StatusOK,revert)
+	}
+}
@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/search"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit = 100
+	maxTitleCandidates = 200
+)
+
+// Loads all (non-deleted) movies for the in-process index
+func loadSearchableMovies(movies repository.MovieRepository)func()([]models.Movie,error){
+	return func()([]models.Movie,error){
+		ctxt,cancel:=context.WithTimeout(context.Background(),100*time.Second)
+		defer cancel()
+
+		return movies.Find(ctxt,repository.MovieQuery{})
+	}
+}
+
+// Movies with a title word that could be a prefix/typo match for one of the terms.
+// Short terms must match as a prefix, longer ones (typo-tolerant) only need their first two letters
+func loadTitleCandidates(ctxt context.Context,movies repository.MovieRepository,query string)([]models.Movie,error){
+	var prefixes []string
+	for _,term:=range search.Tokenize(query){
+		if runes:=[]rune(term);len(runes)>=4{
+			term = string(runes[:2])
+		}
+		prefixes = append(prefixes, term)
+	}
+	if len(prefixes)==0{
+		return nil,nil
+	}
+	return movies.Find(ctxt,repository.MovieQuery{TitleWordPrefixes: prefixes,Limit: maxTitleCandidates})
+}
+
+// Text-index search, ranked by the text-score
+func textSearchMovies(ctxt context.Context,movies repository.MovieRepository,query string,limit int)([]search.Result,error){
+	hits,err:=movies.TextSearch(ctxt,query,limit)
+	if err!=nil{
+		return nil,err
+	}
+
+	terms:=search.Tokenize(query)
+	results:=make([]search.Result,0,len(hits))
+	for _,hit:=range hits{
+		results = append(results, search.Result{
+			Movie: hit.Movie,
+			Score: hit.Score,
+			Snippet: search.Highlight(hit.Movie.AdminReview,terms),
+		})
+	}
+	return results,nil
+}
+
+// Merges the prefix/typo-tolerant title matches into the text-index results
+func mergeTitleMatches(results,titleMatches []search.Result,limit int)[]search.Result{
+	byImdbID:=make(map[string]int,len(results))
+	for i,result:=range results{
+		byImdbID[result.Movie.ImdbID] = i
+	}
+	for _,match:=range titleMatches{
+		if i,ok:=byImdbID[match.Movie.ImdbID];ok{
+			results[i].Score += match.Score
+			continue
+		}
+		results = append(results, match)
+	}
+
+	sortResults(results)
+	if len(results)>limit{
+		results = results[:limit]
+	}
+	return results
+}
+
+func sortResults(results []search.Result){
+	sort.SliceStable(results,func(i,j int)bool{
+		return results[i].Score>results[j].Score
+	})
+}
+
+//! 🔎 GET Search Movies (?q=&limit=)
+func SearchMoviesHandler(movies repository.MovieRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		query:=strings.TrimSpace(ctx.Query("q"))
+		if query==""{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Search-query (q) is required!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		limit:=defaultSearchLimit
+		if limitStr:=ctx.Query("limit"); limitStr!=""{
+			val,err:=strconv.Atoi(limitStr)
+			if err!=nil || val<1{
+				ctx.JSON(http.StatusBadRequest,gin.H{
+					"error":"⚠️ limit must be a positive number!",
+					"status_code":http.StatusBadRequest,
+				})
+				return
+			}
+			limit = min(val,maxSearchLimit)
+		}
+
+		ctxt,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		failed:=func(){
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to search movies!",
+				"status_code":http.StatusInternalServerError,
+			})
+		}
+
+		source:="text_index"
+		results,err:=textSearchMovies(ctxt,movies,query,limit)
+		if err!=nil{
+			if !errors.Is(err,repository.ErrTextIndexMissing){
+				failed()
+				return
+			}
+
+			// No text-index on this deployment, search in-process instead
+			log.Println("⚠️ WARNING: movie text-index missing, using the fallback index")
+			index,err:=search.CachedIndex(loadSearchableMovies(movies))
+			if err!=nil{
+				failed()
+				return
+			}
+			source = "fallback"
+			results = index.Search(query,limit)
+		}else{
+			candidates,err:=loadTitleCandidates(ctxt,movies,query)
+			if err!=nil{
+				failed()
+				return
+			}
+			results = mergeTitleMatches(results,search.NewIndex(candidates).TitleMatches(query,limit),limit)
+		}
+
+		ctx.JSON(http.StatusOK,gin.H{
+			"query":query,
+			"results":results,
+			"total":len(results),
+			"source":source,
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/ai"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+)
+
+// When audience & admin disagree: the audience's mean ranking_value is at least minGap off the admin Ranking,
+// over at least minReviews AI-ranked user-reviews
+const (
+	defaultSentimentMinGap float64 = 1.5
+	defaultSentimentMinReviews int = 3
+	defaultDisagreementLimit int = 20
+	maxDisagreementLimit int = 100
+)
+
+// Puts the admin Ranking next to the audience-sentiment. entries (retired included) name the ranking_values
+func movieSentiment(movie models.Movie,entries []models.RankingEntry,minGap float64,minReviews int)models.MovieSentiment{
+	audience:=movie.AudienceSentiment
+	sentiment:=models.MovieSentiment{
+		ImdbID: movie.ImdbID,
+		Title: movie.Title,
+		AdminRanking: movie.Ranking,
+		AudienceCount: audience.Count,
+		AudienceAverage: audience.Average,
+		Distribution: []models.SentimentShare{},
+	}
+
+	for _,entry:=range entries{
+		count:=audience.Histogram[strconv.Itoa(entry.RankingValue)]
+		if entry.RankingValue==ai.NotRankedValue || (entry.Retired && count==0){
+			continue
+		}
+		share:=models.SentimentShare{Ranking: entry.Ranking,Count: count}
+		if audience.Count>0{
+			share.Share = math.Round(float64(count)/float64(audience.Count)*1000)/1000
+		}
+		sentiment.Distribution = append(sentiment.Distribution,share)
+
+		// The most common one, the first (by sort_order) on a tie
+		if count>0 && (sentiment.AudienceRanking==nil || count>audience.Histogram[strconv.Itoa(sentiment.AudienceRanking.RankingValue)]){
+			ranking:=entry.Ranking
+			sentiment.AudienceRanking = &ranking
+		}
+	}
+
+	adminRanked:=movie.Ranking.RankingValue>0 && movie.Ranking.RankingValue!=ai.NotRankedValue
+	if adminRanked && audience.Count>0{
+		sentiment.Gap = math.Round(math.Abs(audience.Average-float64(movie.Ranking.RankingValue))*100)/100
+		sentiment.Disagreement = audience.Count>=minReviews && sentiment.Gap>=minGap
+	}
+	return sentiment
+}
+
+//! 1️⃣ GET the audience-sentiment of a movie (its AI-ranked user-reviews) next to the admin Ranking
+func GetMovieSentimentHandler(movies repository.MovieRepository,rankingRepo repository.RankingRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		movie,err:=movies.FindByImdbID(c,ctx.Param("imdb_id"))
+		if errors.Is(err,repository.ErrNotFound){
+			ctx.JSON(http.StatusNotFound,gin.H{
+				"error":"⚠️ Movie Not Found!",
+				"status_code":http.StatusNotFound,
+			})
+			return
+		}
+		var entries []models.RankingEntry
+		if err==nil{
+			entries,err=rankingRepo.ListEntries(c,true)
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to fetch movie sentiment!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK,movieSentiment(*movie,entries,defaultSentimentMinGap,defaultSentimentMinReviews))
+	}
+}
+
+//! 2️⃣ GET Movies where the audience disagrees with the admin Ranking (review:history), biggest gap first.
+// ?min_gap= (ranking steps), ?min_reviews= & ?limit=
+func GetSentimentDisagreementsHandler(movies repository.MovieRepository,rankingRepo repository.RankingRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		minGap,minReviews,limit:=defaultSentimentMinGap,defaultSentimentMinReviews,defaultDisagreementLimit
+		if val:=ctx.Query("min_gap"); val!=""{
+			gap,err:=strconv.ParseFloat(val,64)
+			if err!=nil || gap<0{
+				ctx.JSON(http.StatusBadRequest,gin.H{
+					"error":"⚠️ min_gap must be a number >= 0!",
+					"status_code":http.StatusBadRequest,
+				})
+				return
+			}
+			minGap = gap
+		}
+		for name,target:=range map[string]*int{"min_reviews":&minReviews,"limit":&limit}{
+			val:=ctx.Query(name)
+			if val==""{
+				continue
+			}
+			n,err:=strconv.Atoi(val)
+			if err!=nil || n<1{
+				ctx.JSON(http.StatusBadRequest,gin.H{
+					"error":"⚠️ "+name+" must be a positive number!",
+					"status_code":http.StatusBadRequest,
+				})
+				return
+			}
+			*target = n
+		}
+		limit = min(limit,maxDisagreementLimit)
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		candidates,err:=movies.Find(c,repository.MovieQuery{MinAudienceSentiment: minReviews})
+		var entries []models.RankingEntry
+		if err==nil{
+			entries,err=rankingRepo.ListEntries(c,true)
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to fetch movie sentiments!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		disagreements:=[]models.MovieSentiment{}
+		for _,movie:=range candidates{
+			if sentiment:=movieSentiment(movie,entries,minGap,minReviews); sentiment.Disagreement{
+				disagreements = append(disagreements,sentiment)
+			}
+		}
+		slices.SortFunc(disagreements,func(a,b models.MovieSentiment)int{
+			return cmp.Or(cmp.Compare(b.Gap,a.Gap),cmp.Compare(b.AudienceCount,a.AudienceCount),cmp.Compare(a.ImdbID,b.ImdbID))
+		})
+
+		total:=len(disagreements)
+		if len(disagreements)>limit{
+			disagreements = disagreements[:limit]
+		}
+		ctx.JSON(http.StatusOK,gin.H{
+			"movies":disagreements,
+			"total":total,
+			"min_gap":minGap,
+			"min_reviews":minReviews,
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
+)
+
+// Access-token (24h) & refresh-token (1 week) cookies
+func setAuthCookies(ctx *gin.Context,token,refreshToken string){
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:  "token",
+		Value: token,
+		Path:  "/",
+		MaxAge:   86400,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteNoneMode,
+	})
+
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:  "refresh_token",
+		Value: refreshToken,
+		Path:  "/",
+		MaxAge:   int(utils.RefreshTokenLifetime.Seconds()),
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteNoneMode,
+	})
+}
+
+func clearAuthCookies(ctx *gin.Context){
+	for _,name:=range []string{"token","refresh_token"}{
+		http.SetCookie(ctx.Writer, &http.Cookie{
+			Name:     name,
+			Value:    "",
+			Path:     "/",
+			MaxAge:   -1,
+			Secure:   true,
+			HttpOnly: true,
+			SameSite: http.SameSiteNoneMode,
+		})
+	}
+}
+
+// Opens a new session (device) for the user and issues its first token-pair
+func startSession(ctx *gin.Context,c context.Context,sessions repository.SessionRepository,user *models.User)(string,string,error){
+	now:=time.Now()
+	session:=&models.Session{
+		SessionID: utils.NewTokenID(),
+		UserID: user.UserID,
+		RefreshTokenID: utils.NewTokenID(),
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+		CreatedAt: now,
+		LastUsedAt: now,
+		ExpiresAt: now.Add(utils.RefreshTokenLifetime),
+	}
+
+	token,refreshToken,err:=utils.GenerateAllTokens(user.Email,user.FirstName,user.LastName,user.Role,user.UserID,session.SessionID,session.RefreshTokenID)
+	if err!=nil{
+		return "","",err
+	}
+	if err:=sessions.Insert(c,session);err!=nil{
+		return "","",err
+	}
+	return token,refreshToken,nil
+}
+
+// Ends a session: its refresh-token stops working, and so do the access-tokens it already issued
+func endSession(c context.Context,sessions repository.SessionRepository,revoker *utils.Revoker,session *models.Session,reason string)error{
+	if err:=sessions.Revoke(c,session.SessionID,reason,time.Now());err!=nil{
+		return err
+	}
+	return revoker.RevokeSession(c,session.SessionID,session.UserID,reason)
+}
+
+// A refresh-token that is no longer the current one of its family was replayed: the family can't be trusted anymore
+func revokeReusedSession(c context.Context,sessions repository.SessionRepository,revoker *utils.Revoker,session *models.Session){
+	log.Printf("⚠️ Refresh-token REUSE detected, revoking session %s",session.SessionID)
+	err:=endSession(c,sessions,revoker,session,models.SessionRevokedReuse)
+	if err!=nil && !errors.Is(err,repository.ErrNotFound){
+		log.Printf("⚠️ ERROR revoking session %s --- %v",session.SessionID,err)
+	}
+}
+
+//! 1️⃣ GET the caller's active sessions (devices)
+func GetSessionsHandler(sessions repository.SessionRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		userId,err:=utils.GetUserIdFromCtx(ctx)
+		if err!=nil{
+			ctx.JSON(http.StatusBadRequest ,gin.H{
+				"error":"⚠️ ID NOT FOUND IN CONTEXT!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		active,err:=sessions.ListActiveByUser(c,userId,time.Now())
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to fetch sessions!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		currentId:=utils.GetSessionIdFromCtx(ctx)
+		result:=make([]gin.H,0,len(active))
+		for _,session:=range active{
+			result = append(result,gin.H{
+				"session_id":session.SessionID,
+				"user_agent":session.UserAgent,
+				"ip_address":session.IPAddress,
+				"created_at":session.CreatedAt,
+				"last_used_at":session.LastUsedAt,
+				"expires_at":session.ExpiresAt,
+				"current":session.SessionID==currentId,
+			})
+		}
+		ctx.JSON(http.StatusOK,result)
+	}
+}
+
+//! 2️⃣ DELETE (revoke) one of the caller's own sessions, its refresh- & access-tokens stop working
+func RevokeSessionHandler(sessions repository.SessionRepository,revoker *utils.Revoker)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		userId,err:=utils.GetUserIdFromCtx(ctx)
+		if err!=nil{
+			ctx.JSON(http.StatusBadRequest ,gin.H{
+				"error":"⚠️ ID NOT FOUND IN CONTEXT!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		sessionId:=ctx.Param("session_id")
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		// Someone else's session is reported as missing, not as forbidden
+		session,err:=sessions.FindBySessionID(c,sessionId)
+		if err==nil && session.UserID!=userId{
+			err = repository.ErrNotFound
+		}
+		if err==nil{
+			err = endSession(c,sessions,revoker,session,models.SessionRevokedByUser)
+		}
+		if errors.Is(err,repository.ErrNotFound){
+			ctx.JSON(http.StatusNotFound,gin.H{
+				"error":"⚠️ Session NOT FOUND!",
+				"status_code":http.StatusNotFound,
+			})
+			return
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to revoke session!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,87 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+type sessionInfo struct {
+	SessionID string `json:"session_id"`
+	Current   bool   `json:"current"`
+}
+
+// A 2nd device of an already registered user
+func (api *testAPI) loginAgain(email string) []*http.Cookie {
+	api.t.Helper()
+	rec := api.do(http.MethodPost, "/login", models.UserLogin{Email: email, Password: testPassword})
+	if rec.Code != http.StatusOK {
+		api.t.Fatalf("login %s: %d %s", email, rec.Code, rec.Body)
+	}
+	return rec.Result().Cookies()
+}
+
+func (api *testAPI) currentSession(cookies []*http.Cookie) string {
+	api.t.Helper()
+	rec := api.do(http.MethodGet, "/sessions", nil, cookies...)
+	if rec.Code != http.StatusOK {
+		api.t.Fatalf("GET /sessions: %d %s", rec.Code, rec.Body)
+	}
+	for _, session := range decode[[]sessionInfo](api.t, rec) {
+		if session.Current {
+			return session.SessionID
+		}
+	}
+	api.t.Fatal("no current session")
+	return ""
+}
+
+func TestRevokeSessionRevokesItsAccessTokens(t *testing.T) {
+	api := newTestAPI(t)
+	laptop := api.login("alice@example.com")
+	phone := api.loginAgain("alice@example.com")
+
+	rec := api.do(http.MethodDelete, "/sessions/"+api.currentSession(phone), nil, laptop...)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("revoking the phone's session: %d %s", rec.Code, rec.Body)
+	}
+	if rec := api.do(http.MethodGet, "/me", nil, phone...); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("phone's access-token: got %d, want 401", rec.Code)
+	}
+	if rec := api.do(http.MethodPost, "/refresh", nil, cookie(phone, "refresh_token")); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("phone's refresh-token: got %d, want 401", rec.Code)
+	}
+	if rec := api.do(http.MethodGet, "/me", nil, laptop...); rec.Code != http.StatusOK {
+		t.Fatalf("laptop: got %d, want 200", rec.Code)
+	}
+}
+
+func TestRevokeSessionOfAnotherUserIsNotFound(t *testing.T) {
+	api := newTestAPI(t)
+	alice := api.login("alice@example.com")
+	bob := api.login("bob@example.com")
+
+	if rec := api.do(http.MethodDelete, "/sessions/"+api.currentSession(bob), nil, alice...); rec.Code != http.StatusNotFound {
+		t.Fatalf("got %d, want 404", rec.Code)
+	}
+	if rec := api.do(http.MethodGet, "/me", nil, bob...); rec.Code != http.StatusOK {
+		t.Fatalf("bob: got %d, want 200", rec.Code)
+	}
+}
+
+func TestRefreshTokenReuseRevokesTheSessionsAccessTokens(t *testing.T) {
+	api := newTestAPI(t)
+	stolen := api.login("alice@example.com")
+
+	rotated := api.do(http.MethodPost, "/refresh", nil, cookie(stolen, "refresh_token"))
+	if rotated.Code != http.StatusOK {
+		t.Fatalf("refresh: %d %s", rotated.Code, rotated.Body)
+	}
+	if rec := api.do(http.MethodPost, "/refresh", nil, cookie(stolen, "refresh_token")); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("replayed refresh-token: got %d, want 401", rec.Code)
+	}
+	if rec := api.do(http.MethodGet, "/me", nil, rotated.Result().Cookies()...); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("access-token of the revoked session: got %d, want 401", rec.Code)
+	}
+}
@@ -2,21 +2,26 @@ package controllers
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
-	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/database"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/mailer"
 	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
 	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
 	"go.mongodb.org/mongo-driver/v2/bson"
-	"go.mongodb.org/mongo-driver/v2/mongo"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Compared against for unknown e-mails, so they take as long as a wrong password
+var dummyPasswordHash,_ = HashPassword("not-a-real-password")
+
 // Hashing f(x) 🛡️
 func HashPassword(password string)(string,error){
 	HashedPassword, err:=bcrypt.GenerateFromPassword([]byte(password),bcrypt.DefaultCost)
@@ -27,11 +32,11 @@ func HashPassword(password string)(string,error){
 	return string(HashedPassword),nil	
 }
 
-//! 1️⃣ POST/Add/Register User
-func RegisterUserHandler(client *mongo.Client)gin.HandlerFunc{
+//! 1️⃣ POST/Add/Register User. The body is a UserRegister: the role is always USER (admins hand out the others)
+func RegisterUserHandler(users repository.UserRepository,tokens repository.ActionTokenRepository,mail mailer.Mailer)gin.HandlerFunc{
 	return func(ctx *gin.Context){
-		var user models.User
-		err:=ctx.ShouldBindJSON(&user); 
+		var register models.UserRegister
+		err:=ctx.ShouldBindJSON(&register); 
 
 		if err!=nil{
 			ctx.JSON(http.StatusBadRequest,gin.H{
@@ -42,14 +47,14 @@ func RegisterUserHandler(client *mongo.Client)gin.HandlerFunc{
 		}
 			// Validator instance
 			var validate = validator.New()
-			if err:= validate.Struct(user); err!=nil{
+			if err:= validate.Struct(register); err!=nil{
 			ctx.JSON(http.StatusBadRequest,gin.H{
 				"error":"⚠️ Validation failed!",
 				"status_code":http.StatusBadRequest,
 			})
 			return 
 		}
-		hashedPassword,err:=HashPassword(user.Password)
+		hashedPassword,err:=HashPassword(register.Password)
 		if err!=nil{
 			ctx.JSON(http.StatusInternalServerError,gin.H{
 				"error":"⚠️ Hashing Error!",
@@ -60,8 +65,7 @@ func RegisterUserHandler(client *mongo.Client)gin.HandlerFunc{
 
 		var ctxt,cancel = context.WithTimeout(context.Background(), 100*time.Second)
 		defer cancel()
-		var userCollection *mongo.Collection = database.OpenCollection("users",client)
-		count,err:=userCollection.CountDocuments(ctxt, bson.M{"email":user.Email})
+		exists,err:=users.EmailExists(ctxt, register.Email)
 		if err!=nil{
 			ctx.JSON(http.StatusInternalServerError,gin.H{
 				"error":"⚠️ ERROR checking existing user!",
@@ -71,7 +75,7 @@ func RegisterUserHandler(client *mongo.Client)gin.HandlerFunc{
 		}
 
 		// Return if user/email_ID already exists in the DB
-		if count>0{
+		if exists{
 			ctx.JSON(http.StatusConflict,gin.H{
 				"error":"⚠️ User with this Email-ID already exists!",
 				"status_code":http.StatusConflict,
@@ -79,13 +83,20 @@ func RegisterUserHandler(client *mongo.Client)gin.HandlerFunc{
 			return 
 		}
 
-		user.UserID = bson.NewObjectID().Hex()
-		user.CreatedAt = time.Now()
-		user.UpdatedAt = time.Now()
-		user.Password = hashedPassword
+		user:=models.User{
+			UserID: bson.NewObjectID().Hex(),
+			FirstName: register.FirstName,
+			LastName: register.LastName,
+			Email: register.Email,
+			Password: hashedPassword,
+			Role: models.RoleUser,
+			FavouriteGenres: register.FavouriteGenres,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
 		
 		// finally add/register the user
-		result,err:= userCollection.InsertOne(ctxt,user)
+		err= users.Insert(ctxt,&user)
 
 		if err!=nil{
 			ctx.JSON(http.StatusInternalServerError,gin.H{
@@ -95,12 +106,18 @@ func RegisterUserHandler(client *mongo.Client)gin.HandlerFunc{
 			})
 			return 
 		}
-		ctx.JSON(http.StatusCreated, result)
+
+		// Best effort, the user can ask for a new link (POST /verify-email/request)
+		if err:=sendVerificationEmail(ctxt,tokens,mail,&user);err!=nil{
+			log.Printf("⚠️ ERROR sending the verification-mail to %s --- %v",user.UserID,err)
+		}
+
+		ctx.JSON(http.StatusCreated, gin.H{"InsertedID":user.ID})
 	}
 }
 
 //! 2️⃣ POST/Log-In User
-func LoginUserHandler(client *mongo.Client)gin.HandlerFunc{
+func LoginUserHandler(users repository.UserRepository,sessions repository.SessionRepository,tokens repository.ActionTokenRepository,guard *utils.LoginGuard)gin.HandlerFunc{
 	return func(ctx *gin.Context){
 
 		var userLogin models.UserLogin
@@ -117,133 +134,208 @@ func LoginUserHandler(client *mongo.Client)gin.HandlerFunc{
 		var ctxt,cancel = context.WithTimeout(context.Background(), 100*time.Second)
 		defer cancel()
 		
-		var foundUser models.User
-
-		var userCollection *mongo.Collection = database.OpenCollection("users",client)
-		err=userCollection.FindOne(ctxt, bson.M{"email":userLogin.Email}).Decode(&foundUser)
+		// Too many failures for this account/IP: refused before the password is even looked at.
+		// Otherwise the attempt counts (as a failure) until the password turns out right
+		reservation,wait,err:=guard.Reserve(ctxt, userLogin.Email, ctx.ClientIP())
 		if err!=nil{
-			ctx.JSON(http.StatusUnauthorized,gin.H{
-				"error":"⚠️ Invalid email or password!",
-				"status_code":http.StatusUnauthorized,
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ ERROR checking log-in attempts!",
+				"status_code":http.StatusInternalServerError,
 			})
 			return 
 		}
-
-		// compare the entered password with the hashed-password from the DB
-		err =bcrypt.CompareHashAndPassword([]byte(foundUser.Password),[]byte(userLogin.Password))
-		if err!=nil{
-			ctx.JSON(http.StatusUnauthorized,gin.H{
-				"error":"⚠️ Invalid password!",
-				"status_code":http.StatusUnauthorized,
-			})
+		if wait>0{
+			tooManyLoginAttempts(ctx, wait)
 			return 
 		}
 
-		// If all ok, generate access-token 🔐
-		token, refreshToken, err:= utils.GenerateAllTokens(foundUser.Email, foundUser.FirstName, foundUser.LastName, foundUser.Role, foundUser.UserID)
-		if err!=nil{
+		foundUser,err:=users.FindByEmail(ctxt, userLogin.Email)
+		if err!=nil && !errors.Is(err, repository.ErrNotFound){
 			ctx.JSON(http.StatusInternalServerError,gin.H{
-				"error":"⚠️ Failed to GENERATE tokens!",
+				"error":"⚠️ ERROR looking up user!",
 				"status_code":http.StatusInternalServerError,
 			})
 			return 
 		}
 
-		err=utils.UpdateAllTokens(foundUser.UserID, token, refreshToken,client)
-		if err!=nil{
-			ctx.JSON(http.StatusInternalServerError,gin.H{
-				"error":"⚠️ Failed to UPDATE tokens!",
-				"status_code":http.StatusInternalServerError,
+		// compare the entered password with the hashed-password from the DB.
+		// Unknown e-mails get compared as well (same time) & the same answer, so accounts can't be told apart
+		passwordHash, userId:= dummyPasswordHash, ""
+		if foundUser!=nil{
+			passwordHash, userId = foundUser.Password, foundUser.UserID
+		}
+		err =bcrypt.CompareHashAndPassword([]byte(passwordHash),[]byte(userLogin.Password))
+		if err!=nil || foundUser==nil{
+			if err:=reservation.Fail(ctxt, userId); err!=nil{
+				log.Println("⚠️ ERROR recording failed log-in ---",err)
+			}
+			invalidCredentials(ctx)
+			return 
+		}
+		if err:=reservation.Release(ctxt); err!=nil{
+			log.Println("⚠️ ERROR releasing log-in attempt ---",err)
+		}
+
+		if loginRefused(ctx, foundUser){
+			return 
+		}
+
+		if !foundUser.EmailVerified && emailVerificationRequired(){
+			ctx.JSON(http.StatusForbidden,gin.H{
+				"error":"⚠️ Please verify your e-mail address first!",
+				"status_code":http.StatusForbidden,
 			})
 			return 
 		}
 
-		http.SetCookie(ctx.Writer, &http.Cookie{
-			Name:  "token",
-			Value: token,
-			Path:  "/",
-			// Domain:   "localhost",
-			MaxAge:   86400,
-			Secure:   true,
-			HttpOnly: true,
-			SameSite: http.SameSiteNoneMode,
-		})
+		// MFA enrolled (or mandatory for the role): no tokens yet, only a challenge for POST /login/mfa
+		if foundUser.MFA.Enabled || utils.MFARequired(foundUser.Role){
+			startMFAChallenge(ctx, ctxt, users, tokens, foundUser)
+			return 
+		}
 
-		http.SetCookie(ctx.Writer, &http.Cookie{
-			Name:  "refresh_token",
-			Value: refreshToken,
-			Path:  "/",
-			// Domain:   "localhost",
-			MaxAge:   604800,
-			Secure:   true,
-			HttpOnly: true,
-			SameSite: http.SameSiteNoneMode,
+		completeLogin(ctx, ctxt, sessions, guard, foundUser, nil)
+	}
+}
+
+// Same for a wrong password & an unknown e-mail (no user-enumeration)
+func invalidCredentials(ctx *gin.Context){
+	ctx.JSON(http.StatusUnauthorized,gin.H{
+		"error":"⚠️ Invalid email or password!",
+		"status_code":http.StatusUnauthorized,
+	})
+}
+
+// Right password, but an admin disabled the account or asked for a new password (writes the 403 itself)
+func loginRefused(ctx *gin.Context,user *models.User)bool{
+	if user.Disabled{
+		ctx.JSON(http.StatusForbidden,gin.H{
+			"error":"⚠️ This account has been disabled!",
+			"status_code":http.StatusForbidden,
+		})
+		return true
+	}
+	if user.PasswordResetRequired{
+		ctx.JSON(http.StatusForbidden,gin.H{
+			"error":"⚠️ Please reset your password first, a reset link was sent to your e-mail address!",
+			"password_reset_required":true,
+			"status_code":http.StatusForbidden,
 		})
+		return true
+	}
+	return false
+}
 
-		// return user-resp. // Later saving them
-		ctx.JSON(http.StatusOK, models.UserResponse{
-			UserID: foundUser.UserID,
-			FirstName: foundUser.FirstName,
-			LastName: foundUser.LastName,
-			Email: foundUser.Email,
-			Role: foundUser.Role,
-			//Token: token,
-			//RefreshToken: refreshToken,
-			FavouriteGenres: foundUser.FavouriteGenres,
+func tooManyLoginAttempts(ctx *gin.Context,wait time.Duration){
+	ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	ctx.JSON(http.StatusTooManyRequests,gin.H{
+		"error":"⚠️ Too many failed log-ins, please try again later!",
+		"retry_after":int(math.Ceil(wait.Seconds())),
+		"status_code":http.StatusTooManyRequests,
+	})
+}
+
+// If all ok, open a session (one per device) & generate its tokens 🔐
+func completeLogin(ctx *gin.Context,ctxt context.Context,sessions repository.SessionRepository,guard *utils.LoginGuard,foundUser *models.User,recoveryCodes []string){
+	token, refreshToken, err:= startSession(ctx, ctxt, sessions, foundUser)
+	if err!=nil{
+		ctx.JSON(http.StatusInternalServerError,gin.H{
+			"error":"⚠️ Failed to GENERATE tokens!",
+			"status_code":http.StatusInternalServerError,
 		})
+		return 
+	}
+
+	if err:=guard.Succeed(ctxt, foundUser.Email); err!=nil{
+		log.Println("⚠️ ERROR resetting failed log-ins ---",err)
 	}
+
+	setAuthCookies(ctx, token, refreshToken)
+
+	// return user-resp. // Later saving them
+	response:=toUserResponse(foundUser)
+	response.RecoveryCodes = recoveryCodes
+	ctx.JSON(http.StatusOK, response)
 }
 
 
-//! 3️⃣ POST/Log-Out User
- func LogoutUserHandler(client *mongo.Client) gin.HandlerFunc {
+//! 3️⃣ POST/Log-Out User (the caller's own session, taken from its cookies - never from the body)
+ func LogoutUserHandler(sessions repository.SessionRepository, revoker *utils.Revoker) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Clear the access_token cookie
+		var ctx, cancel = context.WithTimeout(c.Request.Context(), 100*time.Second)
+		defer cancel()
 
-		var UserLogout struct {
-			UserId string `json:"user_id"`
+		var userId, sessionId string
+
+		// A valid (not revoked) access-token identifies the caller, and gets revoked as it would otherwise work until it expires
+		if method, token, err := utils.GetCredential(c); err == nil && method != utils.AuthMethodAPIKey {
+			if claims, err := utils.ValidateToken(token); err == nil && !revoker.IsRevoked(ctx, claims) {
+				userId, sessionId = claims.UserId, claims.SessionId
+				if err := revoker.RevokeToken(ctx, claims, models.SessionRevokedLogout); err != nil {
+					log.Printf("⚠️ ERROR revoking access-token of %s --- %v", claims.UserId, err)
+				}
+			}
 		}
 
-		err := c.ShouldBindJSON(&UserLogout)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		// An expired access-token falls back to the refresh-token (of the same user)
+		if refreshToken, err := c.Cookie("refresh_token"); err == nil {
+			if claim, err := utils.ValidateRefreshToken(refreshToken); err == nil && (userId == "" || claim.UserId == userId) {
+				userId = claim.UserId
+				if sessionId == "" {
+					sessionId = claim.SessionId
+				}
+			}
+		}
+
+		if userId == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Not logged in"})
 			return
 		}
 
-		fmt.Println("User ID from Logout request:", UserLogout.UserId)
+		// End this device's session, so its refresh-token can't be used anymore
+		if sessionId != "" {
+			session, err := sessions.FindBySessionID(ctx, sessionId)
+			if err == nil && session.UserID == userId {
+				err = endSession(ctx, sessions, revoker, session, models.SessionRevokedLogout)
+			}
+			if err != nil && !errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error logging out"})
+				return
+			}
+		}
 
-		err = utils.UpdateAllTokens(UserLogout.UserId, "", "", client) // Clear tokens in the database
-		// Optionally, we can also remove the user session from the database if needed
+		clearAuthCookies(c)
+		c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully ✅"})
+	}
+}
 
+//! 4️⃣ POST/Log-Out User everywhere (every session & access-token of the caller)
+ func LogoutAllHandler(sessions repository.SessionRepository, revoker *utils.Revoker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userId, err := utils.GetUserIdFromCtx(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User ID not found in context"})
+			return
+		}
+
+		var ctx, cancel = context.WithTimeout(c.Request.Context(), 100*time.Second)
+		defer cancel()
+
+		revoked, err := sessions.RevokeAllForUser(ctx, userId, models.SessionRevokedLogout, time.Now())
+		if err == nil {
+			err = revoker.RevokeUser(ctx, userId, models.SessionRevokedLogout)
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error logging out"})
 			return
 		}
-		http.SetCookie(c.Writer, &http.Cookie{
-			Name:  "token",
-			Value: "",
-			Path:  "/",
-			// Domain:   "localhost",
-			MaxAge:   -1,
-			Secure:   true,
-			HttpOnly: true,
-			SameSite: http.SameSiteNoneMode,
-		})
-		http.SetCookie(c.Writer, &http.Cookie{
-			Name:     "refresh_token",
-			Value:    "",
-			Path:     "/",
-			MaxAge:   -1,
-			Secure:   true,
-			HttpOnly: true,
-			SameSite: http.SameSiteNoneMode,
-		})
 
-		c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully ✅"})
+		clearAuthCookies(c)
+		c.JSON(http.StatusOK, gin.H{"message": "Logged out everywhere ✅", "sessions_revoked": revoked})
 	}
 }
 
- func RefreshTokenHandler(client *mongo.Client) gin.HandlerFunc {
+ func RefreshTokenHandler(users repository.UserRepository, sessions repository.SessionRepository, revoker *utils.Revoker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var ctx, cancel = context.WithTimeout(c.Request.Context(), 100*time.Second)
 		defer cancel()
@@ -251,42 +343,69 @@ func LoginUserHandler(client *mongo.Client)gin.HandlerFunc{
 		refreshToken, err := c.Cookie("refresh_token")
 
 		if err != nil {
-			fmt.Println("error", err.Error())
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unable to retrieve refresh token from cookie"})
 			return
 		}
 
 		claim, err := utils.ValidateRefreshToken(refreshToken)
 		if err != nil || claim == nil {
-			fmt.Println("error", err.Error())
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
 			return
 		}
 
-		var userCollection *mongo.Collection = database.OpenCollection("users", client)
+		// Tokens from before sessions existed can't be rotated, log in again
+		if claim.SessionId == "" || claim.ID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session expired, please log in again"})
+			return
+		}
 
-		var user models.User
-		err = userCollection.FindOne(ctx, bson.D{{Key: "user_id", Value: claim.UserId}}).Decode(&user)
+		session, err := sessions.FindBySessionID(ctx, claim.SessionId)
+		if err != nil || session.UserID != claim.UserId || session.RevokedAt != nil || !session.ExpiresAt.After(time.Now()) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session revoked or expired"})
+			return
+		}
+
+		// Only the latest refresh-token of a family is valid, an older one means it was stolen (or replayed)
+		if session.RefreshTokenID != claim.ID {
+			revokeReusedSession(ctx, sessions, revoker, session)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token reuse detected, session revoked"})
+			return
+		}
+
+		user, err := users.FindByUserID(ctx, claim.UserId)
 
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
 			return
 		}
+		if user.Disabled || user.PasswordResetRequired {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session revoked or expired"})
+			return
+		}
 
-		newToken, newRefreshToken, err := utils.GenerateAllTokens(user.Email, user.FirstName, user.LastName, user.Role, user.UserID)
+		newRefreshTokenId := utils.NewTokenID()
+		newToken, newRefreshToken, err := utils.GenerateAllTokens(user.Email, user.FirstName, user.LastName, user.Role, user.UserID, session.SessionID, newRefreshTokenId)
 		if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Token generation failed"})
 		return
 		}
-		err = utils.UpdateAllTokens(user.UserID, newToken, newRefreshToken, client)
+
+		// Rotate: fails when a concurrent refresh already used this token, which is a reuse as well
+		now := time.Now()
+		err = sessions.Rotate(ctx, session.SessionID, claim.ID, newRefreshTokenId, now, now.Add(utils.RefreshTokenLifetime))
+		if errors.Is(err, repository.ErrNotFound) {
+			revokeReusedSession(ctx, sessions, revoker, session)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token reuse detected, session revoked"})
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating tokens"})
 			return
 		}
 
-		c.SetCookie("token", newToken, 86400, "/", "localhost", true, true)          // expires in 24 hours
-		c.SetCookie("refresh_token", newRefreshToken, 604800, "/", "localhost", true, true) //expires in 1 week
+		// Same (host-only) cookies as the login, a second domain-cookie would keep sending the rotated-away token
+		setAuthCookies(c, newToken, newRefreshToken)
 
 		c.JSON(http.StatusOK, gin.H{"message": "Tokens refreshed"})
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,194 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+func TestRegisterAndLogin(t *testing.T) {
+	api := newTestAPI(t)
+	cookies := api.login("alice@example.com")
+	if cookie(cookies, "token") == nil || cookie(cookies, "refresh_token") == nil {
+		t.Fatalf("login set %v, want token & refresh_token cookies", cookies)
+	}
+
+	rec := api.do(http.MethodGet, "/me", nil, cookies...)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /me: %d %s", rec.Code, rec.Body)
+	}
+	me := decode[models.UserResponse](t, rec)
+	if me.Email != "alice@example.com" || me.Role != models.RoleUser {
+		t.Fatalf("got %s (%s), want alice@example.com (USER)", me.Email, me.Role)
+	}
+}
+
+func TestRegisterNeverGrantsAdmin(t *testing.T) {
+	// No (env-configured) bootstrap admin: the first admin is promoted out of band (cmd/promote-admin)
+	t.Setenv("BOOTSTRAP_ADMIN_EMAIL", "admin@example.com")
+	api := newTestAPI(t)
+
+	me := decode[models.UserResponse](t, api.do(http.MethodGet, "/me", nil, api.login("admin@example.com")...))
+	if me.Role != models.RoleUser {
+		t.Fatalf("registered as %s, want USER", me.Role)
+	}
+}
+
+func TestRegisterRejectsATakenEmail(t *testing.T) {
+	api := newTestAPI(t)
+	api.login("alice@example.com")
+
+	rec := api.do(http.MethodPost, "/register", models.UserRegister{
+		FirstName:       "Other",
+		LastName:        "Alice",
+		Email:           "alice@example.com",
+		Password:        testPassword,
+		FavouriteGenres: []models.Genre{},
+	})
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("got %d %s, want 409", rec.Code, rec.Body)
+	}
+}
+
+func TestLoginAnswersTheSameForWrongPasswordAndUnknownEmail(t *testing.T) {
+	api := newTestAPI(t)
+	api.login("alice@example.com")
+
+	wrong := api.do(http.MethodPost, "/login", models.UserLogin{Email: "alice@example.com", Password: "wrong-password"})
+	unknown := api.do(http.MethodPost, "/login", models.UserLogin{Email: "nobody@example.com", Password: "wrong-password"})
+	if wrong.Code != http.StatusUnauthorized || unknown.Code != http.StatusUnauthorized {
+		t.Fatalf("got %d & %d, want 401 for both", wrong.Code, unknown.Code)
+	}
+	if wrong.Body.String() != unknown.Body.String() {
+		t.Fatalf("answers differ: %s vs %s", wrong.Body, unknown.Body)
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	api := newTestAPI(t)
+	cookies := api.login("alice@example.com")
+	refresh := cookie(cookies, "refresh_token")
+
+	if rec := api.do(http.MethodPost, "/refresh", nil); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("no cookie: got %d, want 401", rec.Code)
+	}
+	forged := &http.Cookie{Name: "refresh_token", Value: "not-a-token"}
+	if rec := api.do(http.MethodPost, "/refresh", nil, forged); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("forged cookie: got %d, want 401", rec.Code)
+	}
+
+	rotated := api.do(http.MethodPost, "/refresh", nil, refresh)
+	if rotated.Code != http.StatusOK {
+		t.Fatalf("refresh: %d %s", rotated.Code, rotated.Body)
+	}
+	// Rotated: the old refresh-token is spent (reuse revokes the session)
+	if rec := api.do(http.MethodPost, "/refresh", nil, refresh); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("reused refresh-token: got %d, want 401", rec.Code)
+	}
+	if rec := api.do(http.MethodPost, "/refresh", nil, cookie(rotated.Result().Cookies(), "refresh_token")); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("refresh-token of a revoked session: got %d, want 401", rec.Code)
+	}
+}
+
+func TestLogoutEndsOnlyTheCallersSession(t *testing.T) {
+	api := newTestAPI(t)
+	alice := api.login("alice@example.com")
+	alicePhone := api.loginAgain("alice@example.com")
+	bob := api.login("bob@example.com")
+
+	// The body's user_id is ignored, the caller is who the cookies say
+	rec := api.do(http.MethodPost, "/logout", map[string]string{"user_id": api.userID("bob@example.com")}, alice...)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("logout: %d %s", rec.Code, rec.Body)
+	}
+
+	if rec := api.do(http.MethodGet, "/me", nil, alice...); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("alice's access-token: got %d, want 401", rec.Code)
+	}
+	if rec := api.do(http.MethodPost, "/refresh", nil, cookie(alice, "refresh_token")); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("alice's refresh-token: got %d, want 401", rec.Code)
+	}
+	if rec := api.do(http.MethodGet, "/me", nil, alicePhone...); rec.Code != http.StatusOK {
+		t.Fatalf("alice's other device: got %d, want 200", rec.Code)
+	}
+	if rec := api.do(http.MethodGet, "/me", nil, bob...); rec.Code != http.StatusOK {
+		t.Fatalf("bob: got %d, want 200", rec.Code)
+	}
+	if rec := api.do(http.MethodPost, "/refresh", nil, cookie(bob, "refresh_token")); rec.Code != http.StatusOK {
+		t.Fatalf("bob's refresh-token: got %d, want 200", rec.Code)
+	}
+}
+
+func TestLogoutIgnoresAnotherUsersRefreshToken(t *testing.T) {
+	api := newTestAPI(t)
+	alice := api.login("alice@example.com")
+	bob := api.login("bob@example.com")
+
+	// Alice's access-token with Bob's refresh-cookie only logs Alice out
+	rec := api.do(http.MethodPost, "/logout", nil, cookie(alice, "token"), cookie(bob, "refresh_token"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("logout: %d %s", rec.Code, rec.Body)
+	}
+	if rec := api.do(http.MethodGet, "/me", nil, alice...); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("alice's access-token: got %d, want 401", rec.Code)
+	}
+	if rec := api.do(http.MethodPost, "/refresh", nil, cookie(alice, "refresh_token")); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("alice's refresh-token: got %d, want 401", rec.Code)
+	}
+	if rec := api.do(http.MethodPost, "/refresh", nil, cookie(bob, "refresh_token")); rec.Code != http.StatusOK {
+		t.Fatalf("bob's refresh-token: got %d, want 200", rec.Code)
+	}
+}
+
+func TestLogoutWithOnlyTheRefreshToken(t *testing.T) {
+	api := newTestAPI(t)
+	alice := api.login("alice@example.com")
+
+	if rec := api.do(http.MethodPost, "/logout", nil); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("no cookies: got %d, want 401", rec.Code)
+	}
+
+	// An expired access-token falls back to the refresh-token
+	rec := api.do(http.MethodPost, "/logout", nil, cookie(alice, "refresh_token"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("logout: %d %s", rec.Code, rec.Body)
+	}
+	if rec := api.do(http.MethodPost, "/refresh", nil, cookie(alice, "refresh_token")); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("alice's refresh-token: got %d, want 401", rec.Code)
+	}
+}
+
+func TestLogoutAllEndsEverySessionOfTheCallerOnly(t *testing.T) {
+	api := newTestAPI(t)
+	alice := api.login("alice@example.com")
+	alicePhone := api.loginAgain("alice@example.com")
+	bob := api.login("bob@example.com")
+
+	if rec := api.do(http.MethodPost, "/logout-all", nil); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("no cookies: got %d, want 401", rec.Code)
+	}
+
+	rec := api.do(http.MethodPost, "/logout-all", map[string]string{"user_id": api.userID("bob@example.com")}, alice...)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("logout-all: %d %s", rec.Code, rec.Body)
+	}
+	if revoked := decode[map[string]any](t, rec)["sessions_revoked"]; revoked != float64(2) {
+		t.Fatalf("sessions_revoked: got %v, want 2", revoked)
+	}
+
+	for name, cookies := range map[string][]*http.Cookie{"laptop": alice, "phone": alicePhone} {
+		if rec := api.do(http.MethodGet, "/me", nil, cookies...); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("alice's %s access-token: got %d, want 401", name, rec.Code)
+		}
+		if rec := api.do(http.MethodPost, "/refresh", nil, cookie(cookies, "refresh_token")); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("alice's %s refresh-token: got %d, want 401", name, rec.Code)
+		}
+	}
+	if rec := api.do(http.MethodGet, "/me", nil, bob...); rec.Code != http.StatusOK {
+		t.Fatalf("bob: got %d, want 200", rec.Code)
+	}
+	if rec := api.do(http.MethodPost, "/refresh", nil, cookie(bob, "refresh_token")); rec.Code != http.StatusOK {
+		t.Fatalf("bob's refresh-token: got %d, want 200", rec.Code)
+	}
+}
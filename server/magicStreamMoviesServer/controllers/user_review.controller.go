@@ -0,0 +1,401 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/jobs"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Page-size of the user-review listings
+const (
+	defaultUserReviewPageLimit int64 = 20
+	maxUserReviewPageLimit int64 = 100
+)
+
+// POST /admin/reviews/:review_id/hide body
+type reviewHideRequest struct{
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// "Jane D." - what's shown as the author of a review
+func reviewAuthorName(user *models.User)string{
+	initial,_:=utf8.DecodeRuneInString(user.LastName)
+	if initial==utf8.RuneError{
+		return user.FirstName
+	}
+	return user.FirstName+" "+string(initial)+"."
+}
+
+// Drops a stale (or failed) sentiment after the text was written, and queues the review for the AI-ranking
+func requeueSentiment(c context.Context,reviews repository.UserReviewRepository,movies repository.MovieRepository,sentiment *jobs.SentimentWorker,review *models.UserReview){
+	before,err:=reviews.ResetSentiment(c,review.ReviewID,review.Text)
+	if errors.Is(err,repository.ErrNotFound){
+		// Edited again in the meantime, that edit takes care of it
+		return
+	}
+	if err!=nil{
+		log.Printf("⚠️ ERROR resetting the sentiment of review %s --- %v",review.ReviewID,err)
+		return
+	}
+
+	after:=*before
+	after.Sentiment,after.SentimentStatus,after.SentimentProvider,after.SentimentError,after.SentimentAt = nil,"","","",nil
+	if review.Text!=""{
+		after.SentimentStatus = models.SentimentPending
+	}
+	utils.UpdateReviewAggregates(c,movies,review.ImdbID,before,&after)
+	review.Sentiment,review.SentimentStatus,review.SentimentProvider,review.SentimentError,review.SentimentAt = nil,after.SentimentStatus,"","",nil
+
+	if review.Text!=""{
+		sentiment.Enqueue(review.ReviewID)
+	}
+}
+
+// What the public listing shows of a review (no user_id, no moderation-details)
+func toPublicReviews(page []models.UserReview)[]models.PublicUserReview{
+	public:=make([]models.PublicUserReview,0,len(page))
+	for _,review:=range page{
+		public = append(public,models.PublicUserReview{
+			ReviewID: review.ReviewID,
+			ImdbID: review.ImdbID,
+			AuthorName: review.AuthorName,
+			Rating: review.Rating,
+			Text: review.Text,
+			Sentiment: review.Sentiment,
+			CreatedAt: review.CreatedAt,
+			UpdatedAt: review.UpdatedAt,
+		})
+	}
+	return public
+}
+
+// Writes a newest-first page of reviews: ?limit= & ?after= (next_page_token). public leaves out who wrote them
+func writeUserReviewPage(ctx *gin.Context,reviews repository.UserReviewRepository,query repository.UserReviewQuery,public bool){
+	limit:=defaultUserReviewPageLimit
+	if limitStr:=ctx.Query("limit"); limitStr!=""{
+		val,err:=strconv.ParseInt(limitStr,10,64)
+		if err!=nil || val<1{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ limit must be a positive number!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+		limit = min(val,maxUserReviewPageLimit)
+	}
+
+	if after:=ctx.Query("after"); after!=""{
+		pageCursor,err:=utils.DecodePageCursor(after)
+		if err==nil{
+			_,err=bson.ObjectIDFromHex(pageCursor.ID)
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ Invalid page token!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+		query.AfterID = pageCursor.ID
+	}
+
+	c,cancel:=context.WithTimeout(ctx,100*time.Second)
+	defer cancel()
+
+	total,err:=reviews.Count(c,query)
+	var page []models.UserReview
+	if err==nil{
+		// One extra, to know if there's a next page
+		query.Limit = limit+1
+		page,err=reviews.Find(c,query)
+	}
+	if err!=nil{
+		ctx.JSON(http.StatusInternalServerError,gin.H{
+			"error":"⚠️ Failed to fetch reviews!",
+			"status_code":http.StatusInternalServerError,
+		})
+		return
+	}
+
+	nextPageToken:=""
+	if int64(len(page))>limit{
+		page = page[:limit]
+		nextPageToken,err = utils.EncodePageCursor(utils.PageCursor{ID: page[len(page)-1].ID.Hex()})
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to create page token!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+	}
+
+	var body any = page
+	if public{
+		body = toPublicReviews(page)
+	}
+	ctx.JSON(http.StatusOK,gin.H{
+		"reviews":body,
+		"next_page_token":nextPageToken,
+		"total":total,
+	})
+}
+
+//! 1️⃣ GET the (visible) user-reviews of a movie, newest first & paginated
+func GetUserReviewsHandler(reviews repository.UserReviewRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		visible:=false
+		writeUserReviewPage(ctx,reviews,repository.UserReviewQuery{ImdbID: ctx.Param("imdb_id"),Hidden: &visible},true)
+	}
+}
+
+//! 2️⃣ GET the logged-in user's own review of a movie (also when a moderator hid it)
+func GetMyReviewHandler(reviews repository.UserReviewRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		userId,err:=utils.GetUserIdFromCtx(ctx)
+		if err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{"error":"User ID not found in context"})
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		review,err:=reviews.FindByUserAndMovie(c,userId,ctx.Param("imdb_id"))
+		if errors.Is(err,repository.ErrNotFound){
+			ctx.JSON(http.StatusNotFound,gin.H{
+				"error":"⚠️ You haven't reviewed this movie yet!",
+				"status_code":http.StatusNotFound,
+			})
+			return
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to fetch review!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+		ctx.JSON(http.StatusOK,review)
+	}
+}
+
+//! 3️⃣ PUT Rate (1-10) & review a movie, or edit one's review of it (one per user & movie). The text is AI-ranked in the background
+func PutMyReviewHandler(reviews repository.UserReviewRepository,movies repository.MovieRepository,users repository.UserRepository,sentiment *jobs.SentimentWorker)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		var req models.UserReviewInput
+		if !bindAccountRequest(ctx,&req){
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		user,ok:=currentUser(ctx,c,users)
+		if !ok{
+			return
+		}
+
+		movie,err:=movies.FindByImdbID(c,ctx.Param("imdb_id"))
+		if errors.Is(err,repository.ErrNotFound){
+			ctx.JSON(http.StatusNotFound,gin.H{
+				"error":"⚠️ Movie Not Found!",
+				"status_code":http.StatusNotFound,
+			})
+			return
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to fetch movie!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		now:=time.Now()
+		review:=models.UserReview{
+			ReviewID: bson.NewObjectID().Hex(),
+			ImdbID: movie.ImdbID,
+			UserID: user.UserID,
+			AuthorName: reviewAuthorName(user),
+			Rating: req.Rating,
+			Text: strings.TrimSpace(req.Text),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		before,err:=reviews.Upsert(c,&review)
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to save review!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		// A hidden review stays hidden (& uncounted) when edited
+		utils.UpdateReviewAggregates(c,movies,movie.ImdbID,before,&review)
+
+		// A new/changed text is (re-)ranked, so is one whose ranking failed
+		textChanged:=(before==nil && review.Text!="") || (before!=nil && before.Text!=review.Text)
+		if textChanged || (review.Text!="" && review.SentimentStatus==models.SentimentFailed){
+			requeueSentiment(c,reviews,movies,sentiment,&review)
+		}
+
+		status:=http.StatusOK
+		if before==nil{
+			status = http.StatusCreated
+		}
+		ctx.JSON(status,review)
+	}
+}
+
+//! 4️⃣ DELETE the logged-in user's own review of a movie
+func DeleteMyReviewHandler(reviews repository.UserReviewRepository,movies repository.MovieRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		userId,err:=utils.GetUserIdFromCtx(ctx)
+		if err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{"error":"User ID not found in context"})
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		review,err:=reviews.FindByUserAndMovie(c,userId,ctx.Param("imdb_id"))
+		if err==nil{
+			review,err=reviews.Delete(c,review.ReviewID)
+		}
+		if errors.Is(err,repository.ErrNotFound){
+			ctx.JSON(http.StatusNotFound,gin.H{
+				"error":"⚠️ You haven't reviewed this movie yet!",
+				"status_code":http.StatusNotFound,
+			})
+			return
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to delete review!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		utils.UpdateReviewAggregates(c,movies,review.ImdbID,review,nil)
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+//! 5️⃣ GET User-reviews for moderation (review:moderate), hidden ones included. ?imdb_id=, ?user_id= & ?hidden=true|false narrow them down
+func GetModerationReviewsHandler(reviews repository.UserReviewRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		query:=repository.UserReviewQuery{ImdbID: ctx.Query("imdb_id"),UserID: ctx.Query("user_id")}
+		if hiddenStr:=ctx.Query("hidden"); hiddenStr!=""{
+			hidden,err:=strconv.ParseBool(hiddenStr)
+			if err!=nil{
+				ctx.JSON(http.StatusBadRequest,gin.H{
+					"error":"⚠️ hidden must be true or false!",
+					"status_code":http.StatusBadRequest,
+				})
+				return
+			}
+			query.Hidden = &hidden
+		}
+		writeUserReviewPage(ctx,reviews,query,false)
+	}
+}
+
+// Hides/un-hides a review (review:moderate), and takes it out of/back into the movie's user_rating & audience_sentiment
+func setReviewHidden(reviews repository.UserReviewRepository,movies repository.MovieRepository,audit repository.AuditRepository,hidden bool)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		var req reviewHideRequest
+		// Only hiding takes an (optional) reason
+		if hidden && ctx.Request.ContentLength!=0 && !bindAccountRequest(ctx,&req){
+			return
+		}
+		reviewId:=ctx.Param("review_id")
+		moderatorId,_:=utils.GetUserIdFromCtx(ctx)
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		reason:=strings.TrimSpace(req.Reason)
+		before,err:=reviews.SetHidden(c,reviewId,hidden,moderatorId,reason,time.Now())
+		if errors.Is(err,repository.ErrNotFound){
+			// Missing, or already hidden/visible (nothing to do then)
+			review,err:=reviews.FindByReviewID(c,reviewId)
+			if errors.Is(err,repository.ErrNotFound){
+				ctx.JSON(http.StatusNotFound,gin.H{
+					"error":"⚠️ Review NOT FOUND!",
+					"status_code":http.StatusNotFound,
+				})
+				return
+			}
+			if err!=nil{
+				ctx.JSON(http.StatusInternalServerError,gin.H{
+					"error":"⚠️ Failed to fetch review!",
+					"status_code":http.StatusInternalServerError,
+				})
+				return
+			}
+			ctx.JSON(http.StatusOK,review)
+			return
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to update review!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		after:=*before
+		after.Hidden = hidden
+		utils.UpdateReviewAggregates(c,movies,before.ImdbID,before,&after)
+
+		eventType:=models.AuditReviewUnhidden
+		if hidden{
+			eventType = models.AuditReviewHidden
+		}
+
+		details:=map[string]string{"review_id":before.ReviewID,"imdb_id":before.ImdbID}
+		if reason!=""{
+			details["reason"]=reason
+		}
+		utils.RecordAudit(c,audit,&models.AuditEvent{
+			Type: eventType,
+			ActorID: moderatorId,
+			TargetUserID: before.UserID,
+			IPAddress: ctx.ClientIP(),
+			Details: details,
+		})
+
+		review,err:=reviews.FindByReviewID(c,reviewId)
+		if err!=nil{
+			ctx.JSON(http.StatusOK,gin.H{"message":"Review updated ✅"})
+			return
+		}
+		ctx.JSON(http.StatusOK,review)
+	}
+}
+
+//! 6️⃣ POST Hide an abusive review (review:moderate): no longer listed, nor counted in the movie's user_rating & audience_sentiment
+func HideUserReviewHandler(reviews repository.UserReviewRepository,movies repository.MovieRepository,audit repository.AuditRepository)gin.HandlerFunc{
+	return setReviewHidden(reviews,movies,audit,true)
+}
+
+//! 7️⃣ POST Un-hide a review (review:moderate)
+func UnhideUserReviewHandler(reviews repository.UserReviewRepository,movies repository.MovieRepository,audit repository.AuditRepository)gin.HandlerFunc{
+	return setReviewHidden(reviews,movies,audit,false)
+}
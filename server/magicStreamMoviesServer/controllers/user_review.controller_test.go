@@ -0,0 +1,42 @@
+package controllers_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPublicReviewListingLeavesOutTheReviewer(t *testing.T) {
+	api := newTestAPI(t)
+	api.seedCatalog()
+	alice := api.login("alice@example.com")
+
+	rec := api.do(http.MethodPut, "/movie/tt1/review", map[string]any{"rating": 8, "text": "Loved it"}, alice...)
+	if rec.Code != http.StatusOK && rec.Code != http.StatusCreated {
+		t.Fatalf("reviewing: %d %s", rec.Code, rec.Body)
+	}
+	if own := api.do(http.MethodGet, "/movie/tt1/review", nil, alice...); !strings.Contains(own.Body.String(), api.userID("alice@example.com")) {
+		t.Fatalf("own review without the user_id: %s", own.Body)
+	}
+
+	rec = api.do(http.MethodGet, "/movie/tt1/reviews", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /movie/tt1/reviews: %d %s", rec.Code, rec.Body)
+	}
+	page := decode[struct {
+		Reviews []map[string]any `json:"reviews"`
+	}](t, rec)
+	if len(page.Reviews) != 1 {
+		t.Fatalf("got %d reviews, want 1", len(page.Reviews))
+	}
+	review := page.Reviews[0]
+	if _, ok := review["user_id"]; ok {
+		t.Fatalf("public review has a user_id: %v", review)
+	}
+	if review["author_name"] != "Test U." || review["rating"] != float64(8) {
+		t.Fatalf("got %v, want Test U.'s 8", review)
+	}
+	if strings.Contains(rec.Body.String(), api.userID("alice@example.com")) {
+		t.Fatalf("reviewer's id leaked: %s", rec.Body)
+	}
+}
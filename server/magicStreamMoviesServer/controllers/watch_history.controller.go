@@ -0,0 +1,266 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/jobs"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Page-size of the watch-history, and how many "continue watching" come with its first page
+const (
+	defaultWatchHistoryPageLimit int64 = 20
+	maxWatchHistoryPageLimit int64 = 100
+	maxContinueWatching int64 = 10
+)
+
+// A single movie with the caller's on_watchlist flag, and where they left its trailer
+type movieDetail struct{
+	watchlistFlaggedMovie
+	Playback *models.WatchHistory `json:"playback"`
+}
+
+// Applies a held-back heartbeat, unless the entry got a newer event since
+func applyPending(entry *models.WatchHistory,pending map[string]repository.PlaybackUpdate){
+	if update,ok:=pending[entry.ImdbID];ok && update.At.After(entry.LastWatchedAt){
+		update.ApplyTo(entry)
+	}
+}
+
+// The logged-in caller's playback of a movie (nil when never played), with a held-back heartbeat applied
+func playbackOf(ctx *gin.Context,c context.Context,history repository.WatchHistoryRepository,recorder *jobs.PlaybackRecorder,imdbId string)*models.WatchHistory{
+	userId,err:=utils.GetUserIdFromCtx(ctx)
+	if err!=nil{
+		return nil
+	}
+	entry,err:=history.FindByUserAndMovie(c,userId,imdbId)
+	if err!=nil{
+		if !errors.Is(err,repository.ErrNotFound){
+			log.Printf("⚠️ ERROR looking up the watch-history of %s --- %v",userId,err)
+		}
+		return nil
+	}
+	applyPending(entry,recorder.Pending(userId))
+	return entry
+}
+
+// Adds the movies to watch-history entries. Deleted movies stay in it (available=false)
+func withHistoryMovies(c context.Context,movies repository.MovieRepository,entries []models.WatchHistory)([]models.WatchHistoryItem,error){
+	items:=make([]models.WatchHistoryItem,0,len(entries))
+	if len(entries)==0{
+		return items,nil
+	}
+
+	imdbIds:=make([]string,0,len(entries))
+	for _,entry:=range entries{
+		imdbIds = append(imdbIds,entry.ImdbID)
+	}
+	found,err:=movies.Find(c,repository.MovieQuery{ImdbIDs: imdbIds})
+	if err!=nil{
+		return nil,err
+	}
+	byImdbId:=map[string]*models.Movie{}
+	for i:=range found{
+		byImdbId[found[i].ImdbID]=&found[i]
+	}
+
+	for _,entry:=range entries{
+		movie:=byImdbId[entry.ImdbID]
+		items = append(items,models.WatchHistoryItem{WatchHistory: entry,Available: movie!=nil,Movie: movie})
+	}
+	return items,nil
+}
+
+//! 1️⃣ POST a playback-event of a movie's trailer (START, PROGRESS heartbeat, COMPLETE). Retries (same event_id) are
+// applied once, heartbeats are coalesced: 202 either way
+func RecordPlaybackHandler(movies repository.MovieRepository,recorder *jobs.PlaybackRecorder)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		userId,err:=utils.GetUserIdFromCtx(ctx)
+		if err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{"error":"User ID not found in context"})
+			return
+		}
+
+		var req models.PlaybackEvent
+		if !bindAccountRequest(ctx,&req){
+			return
+		}
+		imdbId:=ctx.Param("imdb_id")
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		// Heartbeats of a movie that's playing right now skip the look-up
+		if !recorder.Active(userId,imdbId){
+			_,err:=movies.FindByImdbID(c,imdbId)
+			if errors.Is(err,repository.ErrNotFound){
+				ctx.JSON(http.StatusNotFound,gin.H{
+					"error":"⚠️ Movie Not Found!",
+					"status_code":http.StatusNotFound,
+				})
+				return
+			}
+			if err!=nil{
+				ctx.JSON(http.StatusInternalServerError,gin.H{
+					"error":"⚠️ Failed to fetch movie!",
+					"status_code":http.StatusInternalServerError,
+				})
+				return
+			}
+		}
+
+		coalesced,err:=recorder.Record(c,userId,imdbId,req,time.Now())
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to record playback!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+		ctx.JSON(http.StatusAccepted,gin.H{
+			"event_id":req.EventID,
+			"coalesced":coalesced,
+		})
+	}
+}
+
+//! 2️⃣ GET the logged-in user's watch-history, most recently watched first & paginated (?limit= & ?after=).
+// The first page comes with "continue watching" (started, not finished)
+func GetWatchHistoryHandler(history repository.WatchHistoryRepository,movies repository.MovieRepository,recorder *jobs.PlaybackRecorder)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		userId,err:=utils.GetUserIdFromCtx(ctx)
+		if err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{"error":"User ID not found in context"})
+			return
+		}
+
+		limit:=defaultWatchHistoryPageLimit
+		if limitStr:=ctx.Query("limit"); limitStr!=""{
+			val,err:=strconv.ParseInt(limitStr,10,64)
+			if err!=nil || val<1{
+				ctx.JSON(http.StatusBadRequest,gin.H{
+					"error":"⚠️ limit must be a positive number!",
+					"status_code":http.StatusBadRequest,
+				})
+				return
+			}
+			limit = min(val,maxWatchHistoryPageLimit)
+		}
+
+		query:=repository.WatchHistoryQuery{UserID: userId,Limit: limit+1}
+		if after:=ctx.Query("after"); after!=""{
+			pageCursor,err:=utils.DecodePageCursor(after)
+			var lastWatchedAt time.Time
+			if err==nil{
+				_,err=bson.ObjectIDFromHex(pageCursor.ID)
+			}
+			if err==nil{
+				value,_:=pageCursor.Value.(string)
+				lastWatchedAt,err=time.Parse(time.RFC3339Nano,value)
+			}
+			if err!=nil{
+				ctx.JSON(http.StatusBadRequest,gin.H{
+					"error":"⚠️ Invalid page token!",
+					"status_code":http.StatusBadRequest,
+				})
+				return
+			}
+			query.After = &repository.WatchHistoryCursor{LastWatchedAt: lastWatchedAt,ID: pageCursor.ID}
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		page,err:=history.Find(c,query)
+		var inProgress []models.WatchHistory
+		if err==nil && query.After==nil{
+			inProgress,err=history.Find(c,repository.WatchHistoryQuery{UserID: userId,InProgress: true,Limit: maxContinueWatching})
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to fetch watch history!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		nextPageToken:=""
+		if int64(len(page))>limit{
+			page = page[:limit]
+			last:=page[len(page)-1]
+			nextPageToken,err = utils.EncodePageCursor(utils.PageCursor{Value: last.LastWatchedAt.Format(time.RFC3339Nano),ID: last.ID.Hex()})
+			if err!=nil{
+				ctx.JSON(http.StatusInternalServerError,gin.H{
+					"error":"⚠️ Failed to create page token!",
+					"status_code":http.StatusInternalServerError,
+				})
+				return
+			}
+		}
+
+		// The latest (held-back) heartbeats, a playback that got to the end isn't "continue watching" anymore
+		pending:=recorder.Pending(userId)
+		for i:=range page{
+			applyPending(&page[i],pending)
+		}
+		continueWatching:=[]models.WatchHistory{}
+		if query.After==nil{
+			// A movie playing right now may only be "continue watching" with its held-back heartbeat
+			listed:=map[string]bool{}
+			for _,entry:=range inProgress{
+				listed[entry.ImdbID]=true
+			}
+			for imdbId:=range pending{
+				if listed[imdbId]{
+					continue
+				}
+				if entry,err:=history.FindByUserAndMovie(c,userId,imdbId);err==nil{
+					inProgress = append(inProgress,*entry)
+				}
+			}
+
+			for _,entry:=range inProgress{
+				applyPending(&entry,pending)
+				if entry.InProgress{
+					continueWatching = append(continueWatching,entry)
+				}
+			}
+			slices.SortFunc(continueWatching,func(a,b models.WatchHistory)int{return b.LastWatchedAt.Compare(a.LastWatchedAt)})
+			if int64(len(continueWatching))>maxContinueWatching{
+				continueWatching = continueWatching[:maxContinueWatching]
+			}
+		}
+
+		items,err:=withHistoryMovies(c,movies,page)
+		var continueItems []models.WatchHistoryItem
+		if err==nil{
+			continueItems,err=withHistoryMovies(c,movies,continueWatching)
+		}
+		if err!=nil{
+			ctx.JSON(http.StatusInternalServerError,gin.H{
+				"error":"⚠️ Failed to fetch movies!",
+				"status_code":http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response:=gin.H{
+			"history":items,
+			"next_page_token":nextPageToken,
+		}
+		if query.After==nil{
+			response["continue_watching"]=continueItems
+		}
+		ctx.JSON(http.StatusOK,response)
+	}
+}
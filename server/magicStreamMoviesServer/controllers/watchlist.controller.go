@@ -0,0 +1,245 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
+)
+
+// A movie with the logged-in caller's on_watchlist flag
+type watchlistFlaggedMovie struct{
+	models.Movie
+	OnWatchlist bool `json:"on_watchlist"`
+}
+
+// Flags the movies for a logged-in caller. Anonymous callers (or a failed lookup) get them as they are
+func withWatchlistFlags(ctx *gin.Context,c context.Context,watchlist repository.WatchlistRepository,movies []models.Movie)any{
+	userId,err:=utils.GetUserIdFromCtx(ctx)
+	if err!=nil{
+		return movies
+	}
+
+	imdbIds:=make([]string,0,len(movies))
+	for _,movie:=range movies{
+		imdbIds = append(imdbIds,movie.ImdbID)
+	}
+	onWatchlist,err:=watchlist.Contains(c,userId,imdbIds)
+	if err!=nil{
+		log.Printf("⚠️ ERROR looking up the watchlist of %s --- %v",userId,err)
+		return movies
+	}
+
+	flagged:=make([]watchlistFlaggedMovie,0,len(movies))
+	for _,movie:=range movies{
+		flagged = append(flagged,watchlistFlaggedMovie{Movie: movie,OnWatchlist: onWatchlist[movie.ImdbID]})
+	}
+	return flagged
+}
+
+// The user's watchlist with its movies. Deleted movies stay on it (available=false), so the user sees what's gone
+func listWatchlist(c context.Context,watchlist repository.WatchlistRepository,movies repository.MovieRepository,userId string)([]models.WatchlistItem,error){
+	entries,err:=watchlist.List(c,userId)
+	if err!=nil || len(entries)==0{
+		return []models.WatchlistItem{},err
+	}
+
+	imdbIds:=make([]string,0,len(entries))
+	for _,entry:=range entries{
+		imdbIds = append(imdbIds,entry.ImdbID)
+	}
+	found,err:=movies.Find(c,repository.MovieQuery{ImdbIDs: imdbIds})
+	if err!=nil{
+		return nil,err
+	}
+	byImdbId:=map[string]*models.Movie{}
+	for i:=range found{
+		byImdbId[found[i].ImdbID]=&found[i]
+	}
+
+	items:=make([]models.WatchlistItem,0,len(entries))
+	for _,entry:=range entries{
+		movie:=byImdbId[entry.ImdbID]
+		items = append(items,models.WatchlistItem{
+			ImdbID: entry.ImdbID,
+			Position: entry.Position,
+			AddedAt: entry.AddedAt,
+			Available: movie!=nil,
+			Movie: movie,
+		})
+	}
+	return items,nil
+}
+
+func watchlistError(ctx *gin.Context,message string){
+	ctx.JSON(http.StatusInternalServerError,gin.H{
+		"error":"⚠️ "+message+"!",
+		"status_code":http.StatusInternalServerError,
+	})
+}
+
+//! 1️⃣ GET the logged-in user's watchlist, in their order
+func GetWatchlistHandler(watchlist repository.WatchlistRepository,movies repository.MovieRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		userId,err:=utils.GetUserIdFromCtx(ctx)
+		if err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{"error":"User ID not found in context"})
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		items,err:=listWatchlist(c,watchlist,movies,userId)
+		if err!=nil{
+			watchlistError(ctx,"Failed to fetch watchlist")
+			return
+		}
+		ctx.JSON(http.StatusOK,items)
+	}
+}
+
+//! 2️⃣ POST Add a movie to the end of the watchlist
+func AddToWatchlistHandler(watchlist repository.WatchlistRepository,movies repository.MovieRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		userId,err:=utils.GetUserIdFromCtx(ctx)
+		if err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{"error":"User ID not found in context"})
+			return
+		}
+
+		var req models.WatchlistAdd
+		if !bindAccountRequest(ctx,&req){
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		movie,err:=movies.FindByImdbID(c,req.ImdbID)
+		if errors.Is(err,repository.ErrNotFound){
+			ctx.JSON(http.StatusNotFound,gin.H{
+				"error":"⚠️ Movie Not Found!",
+				"status_code":http.StatusNotFound,
+			})
+			return
+		}
+		if err!=nil{
+			watchlistError(ctx,"Failed to fetch movie")
+			return
+		}
+
+		entry:=models.WatchlistEntry{UserID: userId,ImdbID: movie.ImdbID,AddedAt: time.Now()}
+		err=watchlist.Add(c,&entry)
+		if errors.Is(err,repository.ErrDuplicate){
+			ctx.JSON(http.StatusConflict,gin.H{
+				"error":"⚠️ Movie is already on the watchlist!",
+				"status_code":http.StatusConflict,
+			})
+			return
+		}
+		if err!=nil{
+			watchlistError(ctx,"Failed to add to watchlist")
+			return
+		}
+
+		ctx.JSON(http.StatusCreated,models.WatchlistItem{
+			ImdbID: entry.ImdbID,
+			Position: entry.Position,
+			AddedAt: entry.AddedAt,
+			Available: true,
+			Movie: movie,
+		})
+	}
+}
+
+//! 3️⃣ DELETE a movie from the watchlist
+func RemoveFromWatchlistHandler(watchlist repository.WatchlistRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		userId,err:=utils.GetUserIdFromCtx(ctx)
+		if err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{"error":"User ID not found in context"})
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		err=watchlist.Remove(c,userId,ctx.Param("imdb_id"))
+		if errors.Is(err,repository.ErrNotFound){
+			ctx.JSON(http.StatusNotFound,gin.H{
+				"error":"⚠️ Movie is not on the watchlist!",
+				"status_code":http.StatusNotFound,
+			})
+			return
+		}
+		if err!=nil{
+			watchlistError(ctx,"Failed to remove from watchlist")
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+//! 4️⃣ PUT Reorder the watchlist: imdb_ids lists every movie on it exactly once, in the new order
+func ReorderWatchlistHandler(watchlist repository.WatchlistRepository,movies repository.MovieRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context) {
+		userId,err:=utils.GetUserIdFromCtx(ctx)
+		if err!=nil{
+			ctx.JSON(http.StatusBadRequest,gin.H{"error":"User ID not found in context"})
+			return
+		}
+
+		var order models.WatchlistOrder
+		if !bindAccountRequest(ctx,&order){
+			return
+		}
+
+		c,cancel:=context.WithTimeout(ctx,100*time.Second)
+		defer cancel()
+
+		entries,err:=watchlist.List(c,userId)
+		if err!=nil{
+			watchlistError(ctx,"Failed to fetch watchlist")
+			return
+		}
+		existing:=map[string]bool{}
+		for _,entry:=range entries{
+			existing[entry.ImdbID]=true
+		}
+		valid:=len(order.ImdbIDs)==len(existing)
+		seen:=map[string]bool{}
+		for _,imdbId:=range order.ImdbIDs{
+			if !existing[imdbId] || seen[imdbId]{
+				valid = false
+				break
+			}
+			seen[imdbId]=true
+		}
+		if !valid{
+			ctx.JSON(http.StatusBadRequest,gin.H{
+				"error":"⚠️ imdb_ids must list every movie on the watchlist exactly once!",
+				"status_code":http.StatusBadRequest,
+			})
+			return
+		}
+
+		if err:=watchlist.Reorder(c,userId,order.ImdbIDs);err!=nil{
+			watchlistError(ctx,"Failed to reorder watchlist")
+			return
+		}
+
+		items,err:=listWatchlist(c,watchlist,movies,userId)
+		if err!=nil{
+			watchlistError(ctx,"Failed to fetch watchlist")
+			return
+		}
+		ctx.JSON(http.StatusOK,items)
+	}
+}
@@ -3,11 +3,14 @@ package database
 // Connecting to MongoDB
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
@@ -57,4 +60,250 @@ func OpenCollection(collectionName string,client *mongo.Client)*mongo.Collection
 	}
 
 	return collection
-}
\ No newline at end of file
+}
+// Text-index for GET /movies/search (title > genre > admin-review)
+func EnsureMovieSearchIndex(client *mongo.Client){
+	ctx,cancel:=context.WithTimeout(context.Background(),30*time.Second)
+	defer cancel()
+
+	index:=mongo.IndexModel{
+		Keys: bson.D{
+			{Key:"title",Value:"text"},
+			{Key:"genre.genre_name",Value:"text"},
+			{Key:"admin_review",Value:"text"},
+		},
+		Options: options.Index().
+			SetName("movie_text_search").
+			SetWeights(bson.D{
+				{Key:"title",Value:10},
+				{Key:"genre.genre_name",Value:5},
+				{Key:"admin_review",Value:2},
+			}),
+	}
+
+	_,err:=OpenCollection("movies",client).Indexes().CreateOne(ctx,index)
+	if err!=nil{
+		// Search falls back to the in-process index
+		log.Println("⚠️ WARNING: unable to create movie text-index ---",err)
+	}
+}
+
+// Sessions are looked up by session_id & user_id, and dropped by MongoDB a day after they expire
+func EnsureSessionIndexes(client *mongo.Client){
+	ctx,cancel:=context.WithTimeout(context.Background(),30*time.Second)
+	defer cancel()
+
+	indexes:=[]mongo.IndexModel{
+		{Keys: bson.D{{Key:"session_id",Value:1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key:"user_id",Value:1},{Key:"last_used_at",Value:-1}}},
+		{Keys: bson.D{{Key:"expires_at",Value:1}}, Options: options.Index().SetExpireAfterSeconds(24*60*60)},
+	}
+
+	_,err:=OpenCollection("sessions",client).Indexes().CreateMany(ctx,indexes)
+	if err!=nil{
+		log.Println("⚠️ WARNING: unable to create session-indexes ---",err)
+	}
+}
+
+// Revocations are only needed until the revoked tokens expire anyway
+func EnsureRevocationIndexes(client *mongo.Client){
+	ctx,cancel:=context.WithTimeout(context.Background(),30*time.Second)
+	defer cancel()
+
+	index:=mongo.IndexModel{
+		Keys: bson.D{{Key:"expires_at",Value:1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+
+	_,err:=OpenCollection("revocations",client).Indexes().CreateOne(ctx,index)
+	if err!=nil{
+		log.Println("⚠️ WARNING: unable to create revocation-index ---",err)
+	}
+}
+
+// API-keys are looked up by the hash of the presented key
+func EnsureAPIKeyIndexes(client *mongo.Client){
+	ctx,cancel:=context.WithTimeout(context.Background(),30*time.Second)
+	defer cancel()
+
+	indexes:=[]mongo.IndexModel{
+		{Keys: bson.D{{Key:"key_hash",Value:1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key:"key_id",Value:1}}, Options: options.Index().SetUnique(true)},
+	}
+
+	_,err:=OpenCollection("api_keys",client).Indexes().CreateMany(ctx,indexes)
+	if err!=nil{
+		log.Println("⚠️ WARNING: unable to create api-key indexes ---",err)
+	}
+}
+
+// Verification/reset tokens: consumed by jti, invalidated per user, gone a day after they expire
+func EnsureActionTokenIndexes(client *mongo.Client){
+	ctx,cancel:=context.WithTimeout(context.Background(),30*time.Second)
+	defer cancel()
+
+	indexes:=[]mongo.IndexModel{
+		{Keys: bson.D{{Key:"token_id",Value:1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key:"user_id",Value:1},{Key:"purpose",Value:1}}},
+		{Keys: bson.D{{Key:"expires_at",Value:1}}, Options: options.Index().SetExpireAfterSeconds(24*60*60)},
+	}
+
+	_,err:=OpenCollection("action_tokens",client).Indexes().CreateMany(ctx,indexes)
+	if err!=nil{
+		log.Println("⚠️ WARNING: unable to create action-token indexes ---",err)
+	}
+}
+
+// Failed-login counters are forgotten once they expire, the locked ones are listed for the admins
+func EnsureLoginAttemptIndexes(client *mongo.Client){
+	ctx,cancel:=context.WithTimeout(context.Background(),30*time.Second)
+	defer cancel()
+
+	indexes:=[]mongo.IndexModel{
+		{Keys: bson.D{{Key:"expires_at",Value:1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		{Keys: bson.D{{Key:"locked_until",Value:-1}}, Options: options.Index().SetSparse(true)},
+	}
+
+	_,err:=OpenCollection("login_attempts",client).Indexes().CreateMany(ctx,indexes)
+	if err!=nil{
+		log.Println("⚠️ WARNING: unable to create login-attempt indexes ---",err)
+	}
+}
+
+// Audit-events are listed newest first, by type or user
+func EnsureAuditIndexes(client *mongo.Client){
+	ctx,cancel:=context.WithTimeout(context.Background(),30*time.Second)
+	defer cancel()
+
+	indexes:=[]mongo.IndexModel{
+		{Keys: bson.D{{Key:"created_at",Value:-1}}},
+		{Keys: bson.D{{Key:"type",Value:1},{Key:"created_at",Value:-1}}},
+		{Keys: bson.D{{Key:"target_user_id",Value:1},{Key:"created_at",Value:-1}}},
+		{Keys: bson.D{{Key:"actor_id",Value:1},{Key:"created_at",Value:-1}}},
+	}
+
+	_,err:=OpenCollection("audit_events",client).Indexes().CreateMany(ctx,indexes)
+	if err!=nil{
+		log.Println("⚠️ WARNING: unable to create audit-event indexes ---",err)
+	}
+}
+
+// One entry per user & movie, listed in the user's order
+func EnsureWatchlistIndexes(client *mongo.Client){
+	ctx,cancel:=context.WithTimeout(context.Background(),30*time.Second)
+	defer cancel()
+
+	indexes:=[]mongo.IndexModel{
+		{Keys: bson.D{{Key:"user_id",Value:1},{Key:"imdb_id",Value:1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key:"user_id",Value:1},{Key:"position",Value:1}}},
+	}
+
+	_,err:=OpenCollection("watchlist",client).Indexes().CreateMany(ctx,indexes)
+	if err!=nil{
+		log.Println("⚠️ WARNING: unable to create watchlist indexes ---",err)
+	}
+}
+
+// One review per user & movie, listed per movie (visible ones, newest first) & per user
+func EnsureUserReviewIndexes(client *mongo.Client){
+	ctx,cancel:=context.WithTimeout(context.Background(),30*time.Second)
+	defer cancel()
+
+	indexes:=[]mongo.IndexModel{
+		{Keys: bson.D{{Key:"imdb_id",Value:1},{Key:"user_id",Value:1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key:"review_id",Value:1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key:"imdb_id",Value:1},{Key:"hidden",Value:1},{Key:"_id",Value:-1}}},
+		{Keys: bson.D{{Key:"user_id",Value:1},{Key:"_id",Value:-1}}},
+		{Keys: bson.D{{Key:"sentiment_status",Value:1}}, Options: options.Index().SetSparse(true)},
+	}
+
+	_,err:=OpenCollection("user_reviews",client).Indexes().CreateMany(ctx,indexes)
+	if err!=nil{
+		log.Println("⚠️ WARNING: unable to create user-review indexes ---",err)
+	}
+}
+
+// One entry per user & movie, listed per user (most recently watched first, all or only "continue watching")
+func EnsureWatchHistoryIndexes(client *mongo.Client){
+	ctx,cancel:=context.WithTimeout(context.Background(),30*time.Second)
+	defer cancel()
+
+	indexes:=[]mongo.IndexModel{
+		{Keys: bson.D{{Key:"user_id",Value:1},{Key:"imdb_id",Value:1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key:"user_id",Value:1},{Key:"last_watched_at",Value:-1},{Key:"_id",Value:-1}}},
+		{Keys: bson.D{{Key:"user_id",Value:1},{Key:"in_progress",Value:1},{Key:"last_watched_at",Value:-1}}},
+	}
+
+	_,err:=OpenCollection("watch_history",client).Indexes().CreateMany(ctx,indexes)
+	if err!=nil{
+		log.Println("⚠️ WARNING: unable to create watch-history indexes ---",err)
+	}
+}
+
+// Admin-review history, listed per movie (newest first) & looked up by history_id (revert)
+func EnsureReviewHistoryIndexes(client *mongo.Client){
+	ctx,cancel:=context.WithTimeout(context.Background(),30*time.Second)
+	defer cancel()
+
+	indexes:=[]mongo.IndexModel{
+		{Keys: bson.D{{Key:"history_id",Value:1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key:"imdb_id",Value:1},{Key:"created_at",Value:-1},{Key:"_id",Value:-1}}},
+	}
+
+	_,err:=OpenCollection("review_history",client).Indexes().CreateMany(ctx,indexes)
+	if err!=nil{
+		log.Println("⚠️ WARNING: unable to create review-history indexes ---",err)
+	}
+}
+
+// Re-rank jobs, looked up by job_id, listed newest first & by status (resuming the interrupted ones)
+func EnsureJobIndexes(client *mongo.Client){
+	ctx,cancel:=context.WithTimeout(context.Background(),30*time.Second)
+	defer cancel()
+
+	indexes:=[]mongo.IndexModel{
+		{Keys: bson.D{{Key:"job_id",Value:1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key:"created_at",Value:-1}}},
+		{Keys: bson.D{{Key:"status",Value:1},{Key:"created_at",Value:1}}},
+	}
+
+	_,err:=OpenCollection("rerank_jobs",client).Indexes().CreateMany(ctx,indexes)
+	if err!=nil{
+		log.Println("⚠️ WARNING: unable to create re-rank job indexes ---",err)
+	}
+}
+
+// Names are compared case-insensitively (as the catalog-repositories do)
+var caseInsensitive = &options.Collation{Locale: "en", Strength: 2}
+
+// genre_id & genre_name are unique, the repository's check-then-insert alone races
+func EnsureGenreIndexes(client *mongo.Client){
+	ctx,cancel:=context.WithTimeout(context.Background(),30*time.Second)
+	defer cancel()
+
+	indexes:=[]mongo.IndexModel{
+		{Keys: bson.D{{Key:"genre_id",Value:1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key:"genre_name",Value:1}}, Options: options.Index().SetUnique(true).SetCollation(caseInsensitive)},
+	}
+
+	_,err:=OpenCollection("genres",client).Indexes().CreateMany(ctx,indexes)
+	if err!=nil{
+		log.Println("⚠️ WARNING: unable to create genre indexes ---",err)
+	}
+}
+
+// ranking_value & ranking_name are unique, same as the genres
+func EnsureRankingIndexes(client *mongo.Client){
+	ctx,cancel:=context.WithTimeout(context.Background(),30*time.Second)
+	defer cancel()
+
+	indexes:=[]mongo.IndexModel{
+		{Keys: bson.D{{Key:"ranking_value",Value:1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key:"ranking_name",Value:1}}, Options: options.Index().SetUnique(true).SetCollation(caseInsensitive)},
+	}
+
+	_,err:=OpenCollection("rankings",client).Indexes().CreateMany(ctx,indexes)
+	if err!=nil{
+		log.Println("⚠️ WARNING: unable to create ranking indexes ---",err)
+	}
+}
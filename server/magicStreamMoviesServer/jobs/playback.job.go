@@ -0,0 +1,160 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+)
+
+const (
+	// A user & movie's heartbeats are written at most once per interval, the latest one wins
+	playbackFlushInterval = 15 * time.Second
+	// From here on a playback counts as "continue watching"
+	minResumeSeconds = 5
+	// A heartbeat this close to the end completes the playback (players don't always send COMPLETE)
+	completeFraction = 0.95
+)
+
+type playbackKey struct {
+	userID string
+	imdbID string
+}
+
+// PlaybackRecorder writes the playback-events into the watch-history. START & COMPLETE go straight through,
+// PROGRESS heartbeats are coalesced in memory & flushed in the background, so they don't hit the store every few seconds.
+// A crash loses at most one interval of progress.
+type PlaybackRecorder struct {
+	history repository.WatchHistoryRepository
+
+	mu      sync.Mutex
+	pending map[playbackKey]repository.PlaybackUpdate
+	written map[playbackKey]time.Time // last write per user & movie
+}
+
+func NewPlaybackRecorder(history repository.WatchHistoryRepository) *PlaybackRecorder {
+	return &PlaybackRecorder{
+		history: history,
+		pending: map[playbackKey]repository.PlaybackUpdate{},
+		written: map[playbackKey]time.Time{},
+	}
+}
+
+// Start flushes the coalesced heartbeats until ctx is done
+func (r *PlaybackRecorder) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(playbackFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.flush(ctx)
+			}
+		}
+	}()
+}
+
+// Active reports whether the user played the movie within the last interval (its events need no look-up then)
+func (r *PlaybackRecorder) Active(userID, imdbID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := playbackKey{userID, imdbID}
+	_, pending := r.pending[key]
+	return pending || time.Since(r.written[key]) < playbackFlushInterval
+}
+
+// Record applies a playback-event the server got at `at`. coalesced: it's held back for the next flush
+func (r *PlaybackRecorder) Record(ctx context.Context, userID, imdbID string, event models.PlaybackEvent, at time.Time) (coalesced bool, err error) {
+	update := repository.PlaybackUpdate{
+		UserID:   userID,
+		ImdbID:   imdbID,
+		EventID:  event.EventID,
+		Started:  event.Type == models.PlaybackStart,
+		Position: event.PositionSeconds,
+		Duration: event.DurationSeconds,
+		At:       at,
+	}
+	update.Completed = event.Type == models.PlaybackComplete ||
+		(event.DurationSeconds > 0 && event.PositionSeconds >= event.DurationSeconds*completeFraction)
+	update.InProgress = !update.Completed && event.PositionSeconds >= minResumeSeconds
+	if update.Completed && update.Duration > 0 {
+		update.Position = update.Duration
+	}
+
+	key := playbackKey{userID, imdbID}
+	r.mu.Lock()
+	if event.Type == models.PlaybackProgress && !update.Completed && time.Since(r.written[key]) < playbackFlushInterval {
+		r.pending[key] = update
+		r.mu.Unlock()
+		return true, nil
+	}
+	// A held-back heartbeat stays: if this one is a retry it's still the latest, otherwise the store skips it as older
+	r.written[key] = time.Now()
+	r.mu.Unlock()
+
+	return false, r.history.Record(ctx, update)
+}
+
+// Pending returns the held-back heartbeats of a user (by imdb_id), so reads can show the latest position.
+// Only the ones newer than the stored entry's last_watched_at apply
+func (r *PlaybackRecorder) Pending(userID string) map[string]repository.PlaybackUpdate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending := map[string]repository.PlaybackUpdate{}
+	for key, update := range r.pending {
+		if key.userID == userID {
+			pending[key.imdbID] = update
+		}
+	}
+	return pending
+}
+
+// Forget drops a user's held-back heartbeats (their account is deleted)
+func (r *PlaybackRecorder) Forget(userID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key := range r.pending {
+		if key.userID == userID {
+			delete(r.pending, key)
+		}
+	}
+	for key := range r.written {
+		if key.userID == userID {
+			delete(r.written, key)
+		}
+	}
+}
+
+func (r *PlaybackRecorder) flush(ctx context.Context) {
+	now := time.Now()
+
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = map[playbackKey]repository.PlaybackUpdate{}
+	for key := range pending {
+		r.written[key] = now
+	}
+	for key, at := range r.written {
+		if now.Sub(at) >= playbackFlushInterval {
+			delete(r.written, key)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, update := range pending {
+		c, cancel := context.WithTimeout(ctx, storeTimeout)
+		// Out-of-order writes are safe, the store skips updates older than the entry's last one
+		if err := r.history.Record(c, update); err != nil {
+			log.Printf("⚠️ ERROR writing the playback of %s by %s --- %v", update.ImdbID, update.UserID, err)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,338 @@
+// Package jobs runs the long-running admin jobs in the background ⏳
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/ai"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/search"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	DefaultConcurrency = 4
+	MaxConcurrency     = 16
+	// Movies per batch = concurrency * batchFactor, the checkpoint moves after every batch
+	batchFactor = 4
+	// Only the first errors are kept on the job
+	maxJobErrors = 100
+	// Only the first diffs are kept on the job (it's one document), Changed still counts them all
+	maxJobDiffs  = 1000
+	storeTimeout = 30 * time.Second
+)
+
+var ErrJobAlreadyRunning = errors.New("a re-rank job is already running")
+
+// RerankManager re-runs the AI ranking over every movie with an admin-review.
+// Progress is checkpointed per batch (by imdb_id), so interrupted jobs resume where they stopped.
+type RerankManager struct {
+	jobs     repository.JobRepository
+	movies   repository.MovieRepository
+	rankings repository.RankingRepository
+	history  repository.ReviewHistoryRepository
+	ranker   ai.SentimentRanker
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func NewRerankManager(jobs repository.JobRepository, movies repository.MovieRepository, rankings repository.RankingRepository, history repository.ReviewHistoryRepository, ranker ai.SentimentRanker) *RerankManager {
+	return &RerankManager{
+		jobs:     jobs,
+		movies:   movies,
+		rankings: rankings,
+		history:  history,
+		ranker:   ranker,
+		cancels:  map[string]context.CancelFunc{},
+	}
+}
+
+// Start creates a job and runs it in the background
+func (m *RerankManager) Start(ctx context.Context, startedBy string, dryRun bool, concurrency int) (*models.RerankJob, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	concurrency = min(concurrency, MaxConcurrency)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.cancels) > 0 {
+		return nil, ErrJobAlreadyRunning
+	}
+
+	total, err := m.movies.Count(ctx, repository.MovieQuery{HasAdminReview: true})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job := &models.RerankJob{
+		JobID:       bson.NewObjectID().Hex(),
+		Status:      models.JobStatusPending,
+		DryRun:      dryRun,
+		Concurrency: concurrency,
+		StartedBy:   startedBy,
+		Total:       total,
+		Diffs:       []models.RankingDiff{},
+		Errors:      []models.JobError{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := m.jobs.Insert(ctx, job); err != nil {
+		return nil, err
+	}
+
+	m.launch(*job)
+	return job, nil
+}
+
+// Cancel stops a running job (the current batch is abandoned, the checkpoint stays)
+func (m *RerankManager) Cancel(ctx context.Context, jobID string) (*models.RerankJob, error) {
+	job, err := m.jobs.FindByJobID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != models.JobStatusPending && job.Status != models.JobStatusRunning {
+		return job, nil
+	}
+
+	if err := m.jobs.RequestCancel(ctx, jobID); err != nil {
+		return nil, err
+	}
+	job.CancelRequested = true
+
+	m.mu.Lock()
+	cancel, running := m.cancels[jobID]
+	m.mu.Unlock()
+
+	if running {
+		cancel()
+		return job, nil
+	}
+
+	// Not running in this process (e.g. left over from a crash)
+	now := time.Now()
+	job.Status = models.JobStatusCancelled
+	job.UpdatedAt = now
+	job.FinishedAt = &now
+	return job, m.jobs.Save(ctx, *job)
+}
+
+// ResumeInterrupted picks up the jobs that were pending/running when the server went down
+func (m *RerankManager) ResumeInterrupted(ctx context.Context) error {
+	interrupted, err := m.jobs.FindByStatus(ctx, models.JobStatusPending, models.JobStatusRunning)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, job := range interrupted {
+		if job.CancelRequested || len(m.cancels) > 0 {
+			now := time.Now()
+			job.Status = models.JobStatusCancelled
+			job.UpdatedAt = now
+			job.FinishedAt = &now
+			if err := m.jobs.Save(ctx, job); err != nil {
+				return err
+			}
+			continue
+		}
+		log.Printf("🔁 Resuming re-rank job %s after %s (%d/%d done)", job.JobID, job.Checkpoint, job.Processed, job.Total)
+		m.launch(job)
+	}
+	return nil
+}
+
+// launch runs the job in a goroutine (m.mu must be held)
+func (m *RerankManager) launch(job models.RerankJob) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancels[job.JobID] = cancel
+
+	go func() {
+		defer func() {
+			cancel()
+			m.mu.Lock()
+			delete(m.cancels, job.JobID)
+			m.mu.Unlock()
+		}()
+		m.run(ctx, job)
+	}()
+}
+
+func (m *RerankManager) save(job *models.RerankJob) {
+	job.UpdatedAt = time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	if err := m.jobs.Save(ctx, *job); err != nil {
+		log.Printf("⚠️ ERROR saving re-rank job %s --- %v", job.JobID, err)
+	}
+}
+
+func (m *RerankManager) finish(job *models.RerankJob, status string, err error) {
+	now := time.Now()
+	job.Status = status
+	job.FinishedAt = &now
+	if err != nil {
+		job.Error = err.Error()
+	}
+	m.save(job)
+	log.Printf("🔁 Re-rank job %s %s (%d processed, %d changed, %d failed)", job.JobID, status, job.Processed, job.Changed, job.Failed)
+}
+
+// Outcome of one movie
+type rerankOutcome struct {
+	diff *models.RankingDiff
+	err  *models.JobError
+}
+
+func (m *RerankManager) run(ctx context.Context, job models.RerankJob) {
+	job.Status = models.JobStatusRunning
+	m.save(&job)
+
+	// The rankings are loaded once, so the whole job ranks against the same labels
+	rankings, err := m.rankings.List(ctx)
+	if err != nil {
+		m.finish(&job, models.JobStatusFailed, err)
+		return
+	}
+
+	batchSize := int64(job.Concurrency * batchFactor)
+	for {
+		if ctx.Err() != nil {
+			m.finish(&job, models.JobStatusCancelled, nil)
+			return
+		}
+
+		batch, err := m.movies.Find(ctx, repository.MovieQuery{
+			HasAdminReview: true,
+			ImdbIDAfter:    job.Checkpoint,
+			Sort:           repository.SortByImdbID,
+			Limit:          batchSize,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				m.finish(&job, models.JobStatusCancelled, nil)
+				return
+			}
+			m.finish(&job, models.JobStatusFailed, err)
+			return
+		}
+		if len(batch) == 0 {
+			m.finish(&job, models.JobStatusCompleted, nil)
+			return
+		}
+
+		outcomes := m.rerankBatch(ctx, job, batch, rankings)
+
+		// Cancelled halfway: the batch doesn't count, resuming would redo it
+		if ctx.Err() != nil {
+			m.finish(&job, models.JobStatusCancelled, nil)
+			return
+		}
+
+		changed := false
+		for _, outcome := range outcomes {
+			job.Processed++
+			switch {
+			case outcome.err != nil:
+				job.Failed++
+				if len(job.Errors) < maxJobErrors {
+					job.Errors = append(job.Errors, *outcome.err)
+				}
+			case outcome.diff != nil:
+				job.Changed++
+				if len(job.Diffs) < maxJobDiffs {
+					job.Diffs = append(job.Diffs, *outcome.diff)
+				}
+				changed = true
+			}
+		}
+		job.Checkpoint = batch[len(batch)-1].ImdbID
+		m.save(&job)
+
+		if changed && !job.DryRun {
+			search.Invalidate()
+		}
+	}
+}
+
+// rerankBatch ranks the movies with at most job.Concurrency workers
+func (m *RerankManager) rerankBatch(ctx context.Context, job models.RerankJob, batch []models.Movie, rankings []models.Ranking) []rerankOutcome {
+	outcomes := make([]rerankOutcome, len(batch))
+	sem := make(chan struct{}, job.Concurrency)
+	var wg sync.WaitGroup
+
+	for i, movie := range batch {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			outcomes[i] = m.rerankMovie(ctx, job, movie, rankings)
+		}()
+	}
+	wg.Wait()
+	return outcomes
+}
+
+func (m *RerankManager) rerankMovie(ctx context.Context, job models.RerankJob, movie models.Movie, rankings []models.Ranking) rerankOutcome {
+	classification, err := ai.Classify(ctx, m.ranker, movie.AdminReview, rankings)
+	if err != nil {
+		return rerankOutcome{err: &models.JobError{ImdbID: movie.ImdbID, Error: err.Error()}}
+	}
+
+	after := classification.Ranking
+	if after == movie.Ranking {
+		return rerankOutcome{}
+	}
+
+	if !job.DryRun {
+		err := m.movies.UpdateRanking(ctx, movie.ImdbID, movie.AdminReview, after)
+		if errors.Is(err, repository.ErrNotFound) {
+			// Review edited (or movie deleted) in the meantime, the new review got its own ranking
+			return rerankOutcome{}
+		}
+		if err != nil {
+			return rerankOutcome{err: &models.JobError{ImdbID: movie.ImdbID, Error: err.Error()}}
+		}
+
+		entry := &models.ReviewHistory{
+			HistoryID:     bson.NewObjectID().Hex(),
+			ImdbID:        movie.ImdbID,
+			Action:        models.ReviewActionRerank,
+			AdminUserID:   job.StartedBy,
+			ReviewBefore:  movie.AdminReview,
+			ReviewAfter:   movie.AdminReview,
+			RankingBefore: movie.Ranking,
+			RankingAfter:  after,
+			RawResponse:   classification.Result.Response,
+			Provider:      classification.Result.Provider,
+			PromptVersion: ai.PromptVersion(),
+			JobID:         job.JobID,
+			CreatedAt:     time.Now(),
+		}
+		if err := m.history.Append(ctx, entry); err != nil {
+			log.Printf("⚠️ ERROR recording review-history for %s --- %v", movie.ImdbID, err)
+		}
+	}
+
+	return rerankOutcome{diff: &models.RankingDiff{
+		ImdbID: movie.ImdbID,
+		Title:  movie.Title,
+		Before: movie.Ranking,
+		After:  after,
+	}}
+}
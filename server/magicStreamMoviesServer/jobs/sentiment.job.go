@@ -0,0 +1,214 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/ai"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
+)
+
+const (
+	sentimentWorkers   = 2
+	sentimentQueueSize = 1000
+	// How often dropped reviews (queue was full) are looked for again
+	sentimentSweepInterval = time.Minute
+	sentimentTimeout       = 2 * time.Minute
+	// Quiet time after an edit before its text is ranked, a burst of edits gets ranked once
+	sentimentDebounce = 30 * time.Second
+	// A review is ranked at most once per interval (every ranking can be a paid LLM call)
+	sentimentMinInterval = 5 * time.Minute
+	// How often the due reviews are handed to the workers
+	sentimentScheduleTick = 5 * time.Second
+)
+
+// SentimentWorker AI-ranks the texts of the user-reviews in the background, with the same pipeline (& rankings) as the
+// admin-review, and keeps the movies' audience_sentiment in step. A review stays PENDING until it's ranked (or failed),
+// so nothing is lost when the queue is full or the server goes down.
+// Edits are throttled per review: ranked after a quiet time, at most once per sentimentMinInterval, and always the
+// text the review has by then (the ones in between are never ranked).
+type SentimentWorker struct {
+	reviews  repository.UserReviewRepository
+	movies   repository.MovieRepository
+	rankings repository.RankingRepository
+	ranker   ai.SentimentRanker
+
+	queue   chan string
+	dropped atomic.Bool
+
+	mu         sync.Mutex
+	due        map[string]time.Time // review_id -> when it gets ranked
+	lastRanked map[string]time.Time // review_id -> its last ranking (kept for sentimentMinInterval)
+}
+
+func NewSentimentWorker(reviews repository.UserReviewRepository, movies repository.MovieRepository, rankings repository.RankingRepository, ranker ai.SentimentRanker) *SentimentWorker {
+	return &SentimentWorker{
+		reviews:    reviews,
+		movies:     movies,
+		rankings:   rankings,
+		ranker:     ranker,
+		queue:      make(chan string, sentimentQueueSize),
+		due:        map[string]time.Time{},
+		lastRanked: map[string]time.Time{},
+	}
+}
+
+// Start runs the workers until ctx is done, and queues the reviews left PENDING (e.g. by a restart)
+func (w *SentimentWorker) Start(ctx context.Context) {
+	for range sentimentWorkers {
+		go w.work(ctx)
+	}
+
+	go func() {
+		w.requeuePending(ctx)
+
+		sweep := time.NewTicker(sentimentSweepInterval)
+		defer sweep.Stop()
+		schedule := time.NewTicker(sentimentScheduleTick)
+		defer schedule.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-schedule.C:
+				w.dispatchDue(now)
+			case <-sweep.C:
+				if w.dropped.Swap(false) {
+					w.requeuePending(ctx)
+				}
+			}
+		}
+	}()
+}
+
+// Enqueue asks for a (PENDING) review to be ranked once it's due, without blocking.
+// Already scheduled: nothing changes, the text it has by then is the one that gets ranked
+func (w *SentimentWorker) Enqueue(reviewID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.due[reviewID]; ok {
+		return
+	}
+	at := time.Now().Add(sentimentDebounce)
+	if last, ok := w.lastRanked[reviewID]; ok && last.Add(sentimentMinInterval).After(at) {
+		at = last.Add(sentimentMinInterval)
+	}
+	w.due[reviewID] = at
+}
+
+// Hands the due reviews to the workers, the ones that don't fit into the queue wait for the next tick
+func (w *SentimentWorker) dispatchDue(now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for reviewID, last := range w.lastRanked {
+		if !last.Add(sentimentMinInterval).After(now) {
+			delete(w.lastRanked, reviewID)
+		}
+	}
+	for reviewID, at := range w.due {
+		if at.After(now) {
+			continue
+		}
+		select {
+		case w.queue <- reviewID:
+			delete(w.due, reviewID)
+		default:
+			return
+		}
+	}
+}
+
+func (w *SentimentWorker) markRanked(reviewID string, at time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lastRanked[reviewID] = at
+}
+
+func (w *SentimentWorker) requeuePending(ctx context.Context) {
+	c, cancel := context.WithTimeout(ctx, storeTimeout)
+	pending, err := w.reviews.Find(c, repository.UserReviewQuery{SentimentStatus: models.SentimentPending})
+	cancel()
+	if err != nil {
+		log.Printf("⚠️ ERROR finding the pending review-sentiments --- %v", err)
+		w.dropped.Store(true)
+		return
+	}
+	if len(pending) > 0 {
+		log.Printf("🔁 Queueing %d pending review-sentiment(s)", len(pending))
+	}
+
+	for _, review := range pending {
+		w.Enqueue(review.ReviewID)
+	}
+}
+
+func (w *SentimentWorker) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case reviewID := <-w.queue:
+			w.rank(ctx, reviewID)
+		}
+	}
+}
+
+func (w *SentimentWorker) rank(ctx context.Context, reviewID string) {
+	c, cancel := context.WithTimeout(ctx, sentimentTimeout)
+	defer cancel()
+
+	review, err := w.reviews.FindByReviewID(c, reviewID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		log.Printf("⚠️ ERROR fetching review %s for its sentiment --- %v", reviewID, err)
+		w.dropped.Store(true)
+		return
+	}
+	// Ranked already (queued twice), or edited & ranked since
+	if review.SentimentStatus != models.SentimentPending {
+		return
+	}
+
+	rankings, err := w.rankings.List(c)
+	var classification ai.Classification
+	if err == nil {
+		w.markRanked(reviewID, time.Now())
+		classification, err = ai.Classify(c, w.ranker, review.Text, rankings)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			// Shutting down, it stays PENDING
+			return
+		}
+		log.Printf("⚠️ ERROR ranking the sentiment of review %s --- %v", reviewID, err)
+		if err := w.reviews.FailSentiment(c, reviewID, review.Text, err.Error(), time.Now()); err != nil && !errors.Is(err, repository.ErrNotFound) {
+			log.Printf("⚠️ ERROR marking the sentiment of review %s as failed --- %v", reviewID, err)
+		}
+		return
+	}
+
+	before, err := w.reviews.SetSentiment(c, reviewID, review.Text, classification.Ranking, classification.Result.Provider, time.Now())
+	if errors.Is(err, repository.ErrNotFound) {
+		// Edited (or deleted) in the meantime, the new text got queued on its own
+		return
+	}
+	if err != nil {
+		log.Printf("⚠️ ERROR storing the sentiment of review %s --- %v", reviewID, err)
+		w.dropped.Store(true)
+		return
+	}
+
+	after := *before
+	after.Sentiment, after.SentimentStatus = &classification.Ranking, models.SentimentRanked
+	utils.UpdateReviewAggregates(c, w.movies, before.ImdbID, before, &after)
+}
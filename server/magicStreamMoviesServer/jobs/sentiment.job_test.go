@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSentimentEditsAreRankedOnceAfterTheQuietTime(t *testing.T) {
+	w := NewSentimentWorker(nil, nil, nil, nil)
+	now := time.Now()
+
+	w.Enqueue("r1")
+	w.Enqueue("r1") // edited again before it was ranked
+	w.dispatchDue(now)
+	if len(w.queue) != 0 {
+		t.Fatalf("queued %d before the quiet time, want 0", len(w.queue))
+	}
+
+	w.dispatchDue(now.Add(sentimentDebounce + time.Second))
+	if len(w.queue) != 1 {
+		t.Fatalf("queued %d, want 1", len(w.queue))
+	}
+	if id := <-w.queue; id != "r1" {
+		t.Fatalf("queued %q, want r1", id)
+	}
+}
+
+func TestSentimentReRankingIsThrottledPerReview(t *testing.T) {
+	w := NewSentimentWorker(nil, nil, nil, nil)
+	ranked := time.Now()
+	w.markRanked("r1", ranked)
+
+	w.Enqueue("r1")
+	w.Enqueue("r2")
+	w.dispatchDue(ranked.Add(sentimentDebounce + time.Second))
+	if len(w.queue) != 1 || <-w.queue != "r2" {
+		t.Fatal("want only r2 queued, r1 was ranked just now")
+	}
+
+	w.dispatchDue(ranked.Add(sentimentMinInterval))
+	if len(w.queue) != 1 || <-w.queue != "r1" {
+		t.Fatal("want r1 queued once its interval passed")
+	}
+}
@@ -0,0 +1,321 @@
+package keystore
+
+// Asymmetric JWT signing keys 🔏
+// Private keys live as PEM files (<kid>.pem) in a local directory. The newest active key signs, every key that may
+// still have live tokens verifies, and RotateIfDue (run on a schedule) adds the next key before the current one is old.
+// A new key is published (JWKS, verification) publishAhead before it signs, so every instance has loaded it by then.
+//
+// Env:
+//   JWT_KEYSTORE_DIR          key directory (default "jwt-keys", created when missing)
+//   JWT_SIGNING_ALG           RS256 (default) or EdDSA
+//   JWT_KEY_ROTATION_HOURS    age after which a new signing key is generated (default 720 = 30 days)
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+
+	rsaKeyBits = 2048
+	kidTimeFmt = "20060102T150405Z"
+
+	// How long a new key is only published before it signs: longer than the instances' reload interval (Run, hourly)
+	publishAhead = 2 * time.Hour
+)
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// Key is one signing/verification key-pair
+type Key struct {
+	ID        string
+	Alg       string
+	CreatedAt time.Time
+	Private   crypto.Signer
+}
+
+func (k *Key) Method() jwt.SigningMethod {
+	if k.Alg == AlgEdDSA {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
+
+func (k *Key) Public() crypto.PublicKey {
+	return k.Private.Public()
+}
+
+type Store struct {
+	dir         string
+	alg         string
+	rotateEvery time.Duration
+	// How long a retired key keeps verifying: the longest token lifetime
+	retainFor time.Duration
+	// How long a new key is published before it signs (at most half the rotation interval)
+	publishAhead time.Duration
+
+	mu   sync.RWMutex
+	keys []*Key // oldest first, the last one signs
+}
+
+// Open loads (and if needed creates) the keystore. retainFor is the longest lifetime of a token signed with it
+func Open(dir, alg string, rotateEvery, retainFor time.Duration) (*Store, error) {
+	if alg != AlgRS256 && alg != AlgEdDSA {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	s := &Store{dir: dir, alg: alg, rotateEvery: rotateEvery, retainFor: retainFor, publishAhead: min(publishAhead, rotateEvery/2)}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	if _, err := s.RotateIfDue(time.Now()); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// OpenFromEnv opens the keystore the JWT_* env-vars describe
+func OpenFromEnv(retainFor time.Duration) (*Store, error) {
+	dir := os.Getenv("JWT_KEYSTORE_DIR")
+	if dir == "" {
+		dir = "jwt-keys"
+	}
+	alg := os.Getenv("JWT_SIGNING_ALG")
+	if alg == "" {
+		alg = AlgRS256
+	}
+	rotateEvery := 720 * time.Hour
+	if val, err := strconv.Atoi(os.Getenv("JWT_KEY_ROTATION_HOURS")); err == nil && val > 0 {
+		rotateEvery = time.Duration(val) * time.Hour
+	}
+	return Open(dir, alg, rotateEvery, retainFor)
+}
+
+func (s *Store) load() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+
+	keys := []*Key{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".pem" {
+			continue
+		}
+		key, err := readKey(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			// One broken file shouldn't take the API down, its tokens just won't verify
+			log.Printf("⚠️ WARNING: skipping key %s --- %v", entry.Name(), err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	return nil
+}
+
+func readKey(path string) (*Key, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("no PEM block")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	kid := strings.TrimSuffix(filepath.Base(path), ".pem")
+	key := &Key{ID: kid}
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Alg, key.Private = AlgRS256, private
+	case ed25519.PrivateKey:
+		key.Alg, key.Private = AlgEdDSA, private
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+
+	// kid = <creation-time>-<random>, the file's mod-time for keys named otherwise
+	if createdAt, err := time.Parse(kidTimeFmt, strings.SplitN(kid, "-", 2)[0]); err == nil {
+		key.CreatedAt = createdAt
+	} else if info, err := os.Stat(path); err == nil {
+		key.CreatedAt = info.ModTime()
+	}
+	return key, nil
+}
+
+func (s *Store) generate(now time.Time) (*Key, error) {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	key := &Key{
+		ID:        now.UTC().Format(kidTimeFmt) + "-" + hex.EncodeToString(suffix),
+		Alg:       s.alg,
+		CreatedAt: now.UTC().Truncate(time.Second),
+	}
+
+	var err error
+	if s.alg == AlgEdDSA {
+		_, key.Private, err = ed25519.GenerateKey(rand.Reader)
+	} else {
+		key.Private, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key.Private)
+	if err != nil {
+		return nil, err
+	}
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(filepath.Join(s.dir, key.ID+".pem"), pemBytes, 0o600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// activeAt is when the key starts signing
+func (s *Store) activeAt(key *Key) time.Time {
+	return key.CreatedAt.Add(s.publishAhead)
+}
+
+// signingKey is the newest active key of the configured algorithm, or (a new keystore/algorithm) the newest
+// key of it, so there's always one to sign with. nil if there's none
+func (s *Store) signingKey(now time.Time) *Key {
+	var newest *Key
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		key := s.keys[i]
+		if key.Alg != s.alg {
+			continue
+		}
+		if !s.activeAt(key).After(now) {
+			return key
+		}
+		if newest == nil {
+			newest = key
+		}
+	}
+	return newest
+}
+
+// RotateIfDue generates the next signing key publishAhead before the current one gets older than the rotation
+// interval, and forgets (deletes) the keys nothing signed with them can still be valid for
+func (s *Store) RotateIfDue(now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rotated := false
+	current := s.signingKey(now)
+	due := current == nil || now.Sub(current.CreatedAt) >= s.rotateEvery-s.publishAhead
+	if due && current != nil && current != s.keys[len(s.keys)-1] && s.keys[len(s.keys)-1].Alg == s.alg {
+		// The next one is published already
+		due = false
+	}
+	if due {
+		key, err := s.generate(now)
+		if err != nil {
+			return false, err
+		}
+		s.keys = append(s.keys, key)
+		rotated = true
+		log.Printf("🔏 New JWT signing key %s (%s)", key.ID, key.Alg)
+	}
+
+	// A key stopped signing when its successor became active, its last tokens expire retainFor later
+	kept := []*Key{}
+	for i, key := range s.keys {
+		if i < len(s.keys)-1 && now.Sub(s.activeAt(s.keys[i+1])) > s.retainFor {
+			if err := os.Remove(filepath.Join(s.dir, key.ID+".pem")); err != nil && !os.IsNotExist(err) {
+				log.Printf("⚠️ WARNING: unable to delete retired key %s --- %v", key.ID, err)
+			}
+			continue
+		}
+		kept = append(kept, key)
+	}
+	s.keys = kept
+	return rotated, nil
+}
+
+// Run checks for a due rotation every interval, until ctx is done. It also picks up keys other instances added
+func (s *Store) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.load(); err != nil {
+				log.Println("⚠️ ERROR reloading the keystore ---", err)
+				continue
+			}
+			if _, err := s.RotateIfDue(time.Now()); err != nil {
+				log.Println("⚠️ ERROR rotating the JWT signing key ---", err)
+			}
+		}
+	}
+}
+
+// SigningKey is the key new tokens are signed with
+func (s *Store) SigningKey() (*Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key := s.signingKey(time.Now())
+	if key == nil {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// VerificationKey looks a key up by the kid of a token
+func (s *Store) VerificationKey(kid string) (*Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, key := range s.keys {
+		if key.ID == kid {
+			return key, nil
+		}
+	}
+	return nil, ErrUnknownKey
+}
+
+// Keys returns every key that still verifies (and the next one, published ahead), oldest first
+func (s *Store) Keys() []*Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]*Key{}, s.keys...)
+}
@@ -0,0 +1,144 @@
+package keystore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	testRotateEvery = 24 * time.Hour
+	testRetainFor   = 48 * time.Hour
+)
+
+func openTestStore(t *testing.T, dir, alg string) *Store {
+	t.Helper()
+	s, err := Open(dir, alg, testRotateEvery, testRetainFor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestANewKeystoreSignsRightAway(t *testing.T) {
+	s := openTestStore(t, t.TempDir(), AlgEdDSA)
+
+	key, err := s.SigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Alg != AlgEdDSA || len(s.Keys()) != 1 {
+		t.Fatalf("got %s of %d key(s), want the only (EdDSA) key", key.Alg, len(s.Keys()))
+	}
+
+	if _, err := Open(t.TempDir(), "HS256", testRotateEvery, testRetainFor); err == nil {
+		t.Fatal("opened with HS256, want an error")
+	}
+}
+
+func TestRotationPublishesTheNextKeyBeforeItSigns(t *testing.T) {
+	dir := t.TempDir()
+	s := openTestStore(t, dir, AlgEdDSA)
+	other := openTestStore(t, dir, AlgEdDSA) // another instance on the same keys
+	first := s.Keys()[0]
+	created := first.CreatedAt
+
+	if rotated, err := s.RotateIfDue(created.Add(testRotateEvery - publishAhead - time.Minute)); err != nil || rotated {
+		t.Fatalf("rotated %v (%v) before it was due", rotated, err)
+	}
+	rotatedAt := created.Add(testRotateEvery - publishAhead)
+	if rotated, err := s.RotateIfDue(rotatedAt); err != nil || !rotated {
+		t.Fatalf("rotated %v (%v), want the next key", rotated, err)
+	}
+	if rotated, _ := s.RotateIfDue(rotatedAt.Add(time.Hour)); rotated {
+		t.Fatal("rotated again while the next key was pending")
+	}
+	next := s.Keys()[1]
+
+	// Published (verifies, in the JWKS), but the current key still signs until the next one is due
+	if got := s.signingKey(rotatedAt.Add(publishAhead - time.Second)); got != first {
+		t.Fatalf("signing with %s before the next key is active, want %s", got.ID, first.ID)
+	}
+	if _, err := s.VerificationKey(next.ID); err != nil {
+		t.Fatalf("next key doesn't verify: %v", err)
+	}
+	if jwks := s.JWKS(); len(jwks.Keys) != 2 || jwks.Keys[1].Kid != next.ID {
+		t.Fatalf("JWKS %+v, want both keys", jwks.Keys)
+	}
+
+	// The other instance picks it up on its (hourly) reload, well before it signs
+	if err := other.load(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.VerificationKey(next.ID); err != nil {
+		t.Fatalf("other instance doesn't know the next key: %v", err)
+	}
+
+	if got := s.signingKey(rotatedAt.Add(publishAhead)); got.ID != next.ID {
+		t.Fatalf("signing with %s once the next key is active, want %s", got.ID, next.ID)
+	}
+}
+
+func TestARetiredKeyVerifiesUntilItsLastTokensExpired(t *testing.T) {
+	dir := t.TempDir()
+	s := openTestStore(t, dir, AlgEdDSA)
+	first := s.Keys()[0]
+
+	rotatedAt := first.CreatedAt.Add(testRotateEvery - publishAhead)
+	if _, err := s.RotateIfDue(rotatedAt); err != nil {
+		t.Fatal(err)
+	}
+	// The first key's last token is signed right before its successor signs
+	retired := rotatedAt.Add(publishAhead)
+
+	if _, err := s.RotateIfDue(retired.Add(testRetainFor)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.VerificationKey(first.ID); err != nil {
+		t.Fatalf("retired key dropped during its grace period: %v", err)
+	}
+
+	if _, err := s.RotateIfDue(retired.Add(testRetainFor + time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.VerificationKey(first.ID); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("got %v after the grace period, want ErrUnknownKey", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, first.ID+".pem")); !os.IsNotExist(err) {
+		t.Fatalf("retired key file still there: %v", err)
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	dir := t.TempDir()
+	rsaKey := openTestStore(t, dir, AlgRS256).Keys()[0]
+	edKey, err := openTestStore(t, dir, AlgEdDSA).SigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwks := openTestStore(t, dir, AlgEdDSA).JWKS()
+	byKid := map[string]JWK{}
+	for _, jwk := range jwks.Keys {
+		byKid[jwk.Kid] = jwk
+	}
+	if len(byKid) != 2 {
+		t.Fatalf("got %+v, want 2 keys", jwks.Keys)
+	}
+	rsa, ed := byKid[rsaKey.ID], byKid[edKey.ID]
+
+	if rsa.Kid != rsaKey.ID || rsa.Kty != "RSA" || rsa.Alg != AlgRS256 || rsa.Use != "sig" || rsa.E != "AQAB" || len(rsa.N) != 342 {
+		t.Fatalf("RSA key %+v, want kid %s with a 2048-bit modulus & exponent 65537", rsa, rsaKey.ID)
+	}
+	if rsa.Crv != "" || rsa.X != "" {
+		t.Fatalf("RSA key %+v carries OKP fields", rsa)
+	}
+	if ed.Kid != edKey.ID || ed.Kty != "OKP" || ed.Crv != "Ed25519" || ed.Alg != AlgEdDSA || ed.Use != "sig" || len(ed.X) != 43 {
+		t.Fatalf("Ed25519 key %+v, want kid %s with a 32-byte x", ed, edKey.ID)
+	}
+	if ed.N != "" || ed.E != "" {
+		t.Fatalf("Ed25519 key %+v carries RSA fields", ed)
+	}
+}
@@ -10,8 +10,14 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/ai"
 	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/database"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/jobs"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/keystore"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/mailer"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
 	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/routes"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
 	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
@@ -51,31 +57,116 @@ func main() {
 	config.AllowOrigins = origins
 	config.AllowMethods = []string{"GET", "POST", "PATCH", "PUT", "DELETE", "OPTIONS"}
 	//config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Authorization"}
+	config.AllowHeaders = []string{"Origin", "Content-Type", "Authorization", "X-API-Key"}
 	config.ExposeHeaders = []string{"Content-Length"}
 	config.AllowCredentials = true
 	config.MaxAge = 12 * time.Hour
 
+	// ctx.ClientIP() (login-guard, sessions) only believes X-Forwarded-For from these (TRUSTED_PROXIES, comma-separated IPs/CIDRs, default none)
+	var trustedProxies []string
+	for _,proxy:=range strings.Split(os.Getenv("TRUSTED_PROXIES"),","){
+		if proxy=strings.TrimSpace(proxy); proxy!=""{
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+	if err:=router.SetTrustedProxies(trustedProxies);err!=nil{
+		log.Fatalf("⚠️ Invalid TRUSTED_PROXIES: %v",err)
+	}
+
 	router.Use(cors.New(config))
 	router.Use(gin.Logger())
 
-	var client *mongo.Client = database.DBConnect() // Client obj.
+	var store *repository.Store
+
+	// DATA_STORE=memory runs the whole API without MongoDB (dev/CI), optionally seeded from MEMORY_SEED_FILE
+	if os.Getenv("DATA_STORE")=="memory"{
+		log.Println("⚠️ Using the IN-MEMORY data-store, nothing will be persisted!")
+		store = repository.NewMemoryStore()
+		if seedFile:=os.Getenv("MEMORY_SEED_FILE"); seedFile!=""{
+			store,err = repository.NewSeededMemoryStore(seedFile)
+			if err!=nil{
+				log.Fatalf("⚠️ Failed to seed the in-memory store: %v",err)
+			}
+		}
+	}else{
+		var client *mongo.Client = database.DBConnect() // Client obj.
+
+		//Ping the db.
+		if err:=client.Ping(context.Background(),nil);err!=nil{
+			log.Fatalf("⚠️ Failed to reach server: %v",err)
+		}
 
-	//Ping the db.
-	if err:=client.Ping(context.Background(),nil);err!=nil{
-		log.Fatalf("⚠️ Failed to reach server: %v",err)
+		defer func ()  {
+			err:=client.Disconnect(context.Background())
+			if err!=nil{
+				log.Fatalf("⚠️ Failed to disconnect from MongoDB: %v",err)
+			}
+		}()
+
+		database.EnsureMovieSearchIndex(client)
+		database.EnsureSessionIndexes(client)
+		database.EnsureRevocationIndexes(client)
+		database.EnsureAPIKeyIndexes(client)
+		database.EnsureActionTokenIndexes(client)
+		database.EnsureLoginAttemptIndexes(client)
+		database.EnsureAuditIndexes(client)
+		database.EnsureWatchlistIndexes(client)
+		database.EnsureUserReviewIndexes(client)
+		database.EnsureWatchHistoryIndexes(client)
+		database.EnsureReviewHistoryIndexes(client)
+		database.EnsureJobIndexes(client)
+		database.EnsureGenreIndexes(client)
+		database.EnsureRankingIndexes(client)
+		store = repository.NewMongoStore(client)
 	}
 
-	defer func ()  {
-		err:=client.Disconnect(context.Background())
-		if err!=nil{
-			log.Fatalf("⚠️ Failed to disconnect from MongoDB: %v",err)
-		}
-	}()
+	// Sentiment-ranking provider(s) for the admin- & user-reviews 🤖
+	ranker,err:=ai.NewRankerFromEnv()
+	if err!=nil{
+		log.Fatalf("⚠️ Failed to set up the sentiment-ranker: %v",err)
+	}
+	log.Println("Sentiment-provider(s):",ranker.Name())
+
+	// Bulk re-ranking, picks up the jobs a crash/restart interrupted
+	rerankManager:=jobs.NewRerankManager(store.Jobs,store.Movies,store.Rankings,store.History,ranker)
+	if err:=rerankManager.ResumeInterrupted(context.Background());err!=nil{
+		log.Println("⚠️ ERROR resuming re-rank jobs ---",err)
+	}
+
+	// User-review texts are ranked in the background (audience-sentiment), the pending ones get queued again
+	sentimentWorker:=jobs.NewSentimentWorker(store.UserReviews,store.Movies,store.Rankings,ranker)
+	sentimentWorker.Start(context.Background())
+
+	// Trailer playback-events, the heartbeats are coalesced & flushed in the background
+	recorder:=jobs.NewPlaybackRecorder(store.WatchHistory)
+	recorder.Start(context.Background())
+
+	// JWT signing keys (RS256/EdDSA), rotated on schedule. Retired keys verify as long as a refresh-token can live
+	keys,err:=keystore.OpenFromEnv(utils.RefreshTokenLifetime)
+	if err!=nil{
+		log.Fatalf("⚠️ Failed to open the JWT keystore: %v",err)
+	}
+	utils.SetKeystore(keys)
+	go keys.Run(context.Background(),time.Hour)
+
+	// Access-token denylist (logout, force sign-out), cached in memory
+	revoker:=utils.NewRevoker(store.Revocations)
+	if err:=revoker.Sync(context.Background());err!=nil{
+		log.Println("⚠️ ERROR loading token-revocations ---",err)
+	}
+
+	// Verification & password-reset mails (MAILER=smtp|file|log)
+	mail,err:=mailer.NewMailerFromEnv()
+	if err!=nil{
+		log.Fatalf("⚠️ Failed to set up the mailer: %v",err)
+	}
+
+	// Failed log-ins per account & IP: backoff, lockout
+	guard:=utils.NewLoginGuard(store.LoginAttempts,store.Audit)
 
 	//! routes 🛜
-	routes.SetUpUnProtectedRoutes(router,client)
-	routes.SetUpProtectedRoutes(router,client)
+	routes.SetUpUnProtectedRoutes(router,store,revoker,keys,mail,guard)
+	routes.SetUpProtectedRoutes(router,store,ranker,rerankManager,sentimentWorker,recorder,revoker,guard,mail)
 
 	err=router.Run()
 	if err!=nil{
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
+)
+
+// last_used_at is only written this often per key, not on every request
+const apiKeyTouchInterval = time.Minute
+
+// An API-key acts as its user, restricted to its scopes (see RequirePermission). Aborts (401) on a bad key
+func authenticateAPIKey(ctx *gin.Context,key string,apiKeys repository.APIKeyRepository,users repository.UserRepository)bool{
+	c,cancel:=context.WithTimeout(ctx,10*time.Second)
+	defer cancel()
+
+	now:=time.Now()
+	apiKey,err:=apiKeys.FindByHash(c,utils.HashAPIKey(key))
+	if err!=nil || !apiKey.Active(now){
+		ctx.JSON(http.StatusUnauthorized,gin.H{
+			"error":"⚠️ Invalid API-key!",
+			"status_code":http.StatusUnauthorized,
+		})
+		ctx.Abort()
+		return false
+	}
+
+	// The role is the owner's current one, not the one at minting time
+	owner,err:=users.FindByUserID(c,apiKey.UserID)
+	if err==nil && owner.Disabled{
+		ctx.JSON(http.StatusUnauthorized,gin.H{
+			"error":"⚠️ Owner of the API-key is disabled!",
+			"status_code":http.StatusUnauthorized,
+		})
+		ctx.Abort()
+		return false
+	}
+	if err!=nil{
+		ctx.JSON(http.StatusUnauthorized,gin.H{
+			"error":"⚠️ Owner of the API-key NOT FOUND!",
+			"status_code":http.StatusUnauthorized,
+		})
+		ctx.Abort()
+		return false
+	}
+
+	if apiKey.LastUsedAt==nil || now.Sub(*apiKey.LastUsedAt)>apiKeyTouchInterval{
+		if err:=apiKeys.Touch(c,apiKey.KeyID,now);err!=nil{
+			log.Printf("⚠️ ERROR updating last_used_at of API-key %s --- %v",apiKey.KeyID,err)
+		}
+	}
+
+	ctx.Set("userId",owner.UserID)
+	ctx.Set("role",owner.Role)
+	ctx.Set("scopes",apiKey.Scopes)
+	ctx.Set("apiKeyId",apiKey.KeyID)
+	ctx.Set("authMethod",utils.AuthMethodAPIKey)
+	return true
+}
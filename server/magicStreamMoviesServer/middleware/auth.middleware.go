@@ -4,13 +4,15 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
 	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
 )
 
 // gin-gonic handler fx, but used in a different way
-func AuthMiddleware()gin.HandlerFunc{
+// Accepts the "token" cookie, an Authorization: Bearer JWT, or an API-key (Bearer msk_... / X-API-Key)
+func AuthMiddleware(revoker *utils.Revoker,apiKeys repository.APIKeyRepository,users repository.UserRepository)gin.HandlerFunc{
 	return func(ctx *gin.Context){
-		token,err:=utils.GetAccessToken(ctx)
+		method,token,err:=utils.GetCredential(ctx)
 		if err!=nil{
 			ctx.JSON(http.StatusUnauthorized,gin.H{
 				"status_code:":http.StatusUnauthorized,
@@ -26,6 +28,14 @@ func AuthMiddleware()gin.HandlerFunc{
 			ctx.Abort() //ctx.Abort() from MW's
 			return 
 		}
+
+		if method==utils.AuthMethodAPIKey{
+			if authenticateAPIKey(ctx,token,apiKeys,users){
+				ctx.Next()
+			}
+			return
+		}
+
 		claims,err:=utils.ValidateToken(token)
 		if err!=nil{
 			ctx.JSON(http.StatusUnauthorized,gin.H{
@@ -36,9 +46,49 @@ func AuthMiddleware()gin.HandlerFunc{
 			return 
 		}
 
-		ctx.Set("userId",claims.UserId)
-		ctx.Set("role",claims.Role)
+		// Signed & not expired, but maybe logged-out/signed-out since
+		if revoker.IsRevoked(ctx,claims){
+			ctx.JSON(http.StatusUnauthorized,gin.H{
+				"error":"⚠️ Token has been revoked!",
+				"status_code:":http.StatusUnauthorized,
+			})
+			ctx.Abort()
+			return 
+		}
+
+		setTokenClaims(ctx,claims,method)
 
 		ctx.Next() // Opposite of ctx.Abort()
 	}
+}
+
+func setTokenClaims(ctx *gin.Context,claims *utils.SignedDetails,method string){
+	ctx.Set("userId",claims.UserId)
+	ctx.Set("role",claims.Role)
+	ctx.Set("sessionId",claims.SessionId)
+	ctx.Set("authMethod",method)
+}
+
+// For public routes that show a bit more to a logged-in user (e.g. on_watchlist flags).
+// No/expired/revoked token: carries on anonymously. A bad API-key is still a 401, whoever sends one expects it to work
+func OptionalAuthMiddleware(revoker *utils.Revoker,apiKeys repository.APIKeyRepository,users repository.UserRepository)gin.HandlerFunc{
+	return func(ctx *gin.Context){
+		method,token,err:=utils.GetCredential(ctx)
+		if err!=nil || token==""{
+			ctx.Next()
+			return
+		}
+
+		if method==utils.AuthMethodAPIKey{
+			if authenticateAPIKey(ctx,token,apiKeys,users){
+				ctx.Next()
+			}
+			return
+		}
+
+		if claims,err:=utils.ValidateToken(token);err==nil && !revoker.IsRevoked(ctx,claims){
+			setTokenClaims(ctx,claims,method)
+		}
+		ctx.Next()
+	}
 }
\ No newline at end of file
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
+)
+
+// Role set by AuthMiddleware, aborts (400) when it's missing
+func roleFromCtx(ctx *gin.Context)(string,bool){
+	role,err:=utils.GetRoleFromCtx(ctx)
+	if err!=nil{
+		ctx.JSON(http.StatusBadRequest,gin.H{
+			"error":"⚠️ Member-ROLE not found in context!",
+			"status_code":http.StatusBadRequest,
+		})
+		ctx.Abort()
+		return "",false
+	}
+	return role,true
+}
+
+// RequireRole lets only the given roles through (after AuthMiddleware)
+func RequireRole(roles ...string)gin.HandlerFunc{
+	return func(ctx *gin.Context){
+		role,ok:=roleFromCtx(ctx)
+		if !ok{
+			return
+		}
+
+		if !slices.Contains(roles,role){
+			ctx.JSON(http.StatusForbidden,gin.H{
+				"error":"⚠️ Your role is not allowed to do this!",
+				"status_code":http.StatusForbidden,
+			})
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// RequirePermission lets only roles through that have every one of the permissions (models.RolePermissions).
+// An API-key additionally needs each of them in its scopes
+func RequirePermission(permissions ...string)gin.HandlerFunc{
+	return func(ctx *gin.Context){
+		role,ok:=roleFromCtx(ctx)
+		if !ok{
+			return
+		}
+		scopes,scoped:=utils.GetScopesFromCtx(ctx)
+
+		for _,permission:=range permissions{
+			if !models.HasPermission(role,permission) || (scoped && !slices.Contains(scopes,permission)){
+				ctx.JSON(http.StatusForbidden,gin.H{
+					"error":"⚠️ Missing permission: "+permission+"!",
+					"status_code":http.StatusForbidden,
+				})
+				ctx.Abort()
+				return
+			}
+		}
+		ctx.Next()
+	}
+}
+
+// RejectAPIKey keeps API-keys out of the routes that need no permission (the caller's own account, sessions,
+// watchlist, reviews...). A key only gets what its scopes grant, and those are permissions
+func RejectAPIKey()gin.HandlerFunc{
+	return func(ctx *gin.Context){
+		if utils.GetAuthMethodFromCtx(ctx)==utils.AuthMethodAPIKey{
+			ctx.JSON(http.StatusForbidden,gin.H{
+				"error":"⚠️ Not available with an API-key!",
+				"status_code":http.StatusForbidden,
+			})
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// What an action-token may be used for
+const (
+	ActionVerifyEmail   = "verify_email"
+	ActionResetPassword = "reset_password"
+	ActionMFALogin      = "mfa_login" // second step of a log-in, issued once the password checked out
+)
+
+//! ✉️ ActionToken model (bookkeeping of the signed, e-mailed tokens, so each one works only once)
+type ActionToken struct{
+	ID bson.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	TokenID string `bson:"token_id" json:"token_id"` // jti of the signed token
+	UserID string `bson:"user_id" json:"user_id"`
+	Purpose string `bson:"purpose" json:"purpose"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
+	UsedAt *time.Time `bson:"used_at,omitempty" json:"used_at,omitempty"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Prefix of every API-key, so they're recognisable (and told apart from JWTs)
+const APIKeyPrefix = "msk_"
+
+//! 🔑 APIKey model (long-lived credential for scripts & services, only its hash is stored)
+type APIKey struct{
+	ID bson.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	KeyID string `bson:"key_id" json:"key_id"`
+	Name string `bson:"name" json:"name"`
+	Prefix string `bson:"prefix" json:"prefix"` // first characters of the key, to recognise it in the list
+	KeyHash string `bson:"key_hash" json:"-"`
+	UserID string `bson:"user_id" json:"user_id"` // the key acts as this user...
+	Scopes []string `bson:"scopes" json:"scopes"` // ... but only with these of the user's permissions
+	CreatedBy string `bson:"created_by" json:"created_by"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	LastUsedAt *time.Time `bson:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+	ExpiresAt *time.Time `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	RevokedAt *time.Time `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+}
+
+// Active: not revoked & not expired
+func (k APIKey) Active(now time.Time)bool{
+	return k.RevokedAt==nil && (k.ExpiresAt==nil || k.ExpiresAt.After(now))
+}
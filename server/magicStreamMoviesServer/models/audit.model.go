@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Audit-event types
+const (
+	AuditLoginLockout        = "LOGIN_LOCKOUT" // too many failed log-ins, account/IP locked
+	AuditLoginUnlock         = "LOGIN_UNLOCK"  // lock lifted by an admin
+	AuditPasswordChanged     = "PASSWORD_CHANGED"
+	AuditAccountDeleted      = "ACCOUNT_DELETED"
+	AuditRoleChanged         = "ROLE_CHANGED"
+	AuditAccountDisabled     = "ACCOUNT_DISABLED"
+	AuditAccountEnabled      = "ACCOUNT_ENABLED"
+	AuditPasswordResetForced = "PASSWORD_RESET_FORCED" // admin made the user pick a new password
+	AuditForcedSignOut       = "FORCED_SIGN_OUT"
+	AuditReviewHidden        = "REVIEW_HIDDEN" // a user-review, by a moderator
+	AuditReviewUnhidden      = "REVIEW_UNHIDDEN"
+)
+
+//! 🧾 AuditEvent model (append-only security trail)
+type AuditEvent struct{
+	ID bson.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	Type string `bson:"type" json:"type"`
+	ActorID string `bson:"actor_id,omitempty" json:"actor_id,omitempty"` // user who did it, empty for the system
+	TargetUserID string `bson:"target_user_id,omitempty" json:"target_user_id,omitempty"`
+	IPAddress string `bson:"ip_address,omitempty" json:"ip_address,omitempty"`
+	Details map[string]string `bson:"details,omitempty" json:"details,omitempty"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Re-rank job statuses
+const (
+	JobStatusPending   = "PENDING"
+	JobStatusRunning   = "RUNNING"
+	JobStatusCompleted = "COMPLETED"
+	JobStatusFailed    = "FAILED"
+	JobStatusCancelled = "CANCELLED"
+)
+
+//! 🔁 RerankJob model (bulk AI re-ranking of the catalog)
+type RerankJob struct{
+	ID bson.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	JobID string `bson:"job_id" json:"job_id"`
+	Status string `bson:"status" json:"status"`
+	DryRun bool `bson:"dry_run" json:"dry_run"`
+	Concurrency int `bson:"concurrency" json:"concurrency"`
+	StartedBy string `bson:"started_by" json:"started_by"`
+	Total int64 `bson:"total" json:"total"`
+	Processed int64 `bson:"processed" json:"processed"`
+	Changed int64 `bson:"changed" json:"changed"`
+	Failed int64 `bson:"failed" json:"failed"`
+	Checkpoint string `bson:"checkpoint" json:"checkpoint"` // last imdb_id of the last finished batch (resume point)
+	Diffs []RankingDiff `bson:"diffs" json:"diffs"` // the first ones only, Changed counts them all
+	Errors []JobError `bson:"errors" json:"errors"`
+	Error string `bson:"error,omitempty" json:"error,omitempty"`
+	CancelRequested bool `bson:"cancel_requested" json:"cancel_requested"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+	FinishedAt *time.Time `bson:"finished_at,omitempty" json:"finished_at,omitempty"`
+}
+
+// Ranking before/after for one movie
+type RankingDiff struct{
+	ImdbID string `bson:"imdb_id" json:"imdb_id"`
+	Title string `bson:"title" json:"title"`
+	Before Ranking `bson:"before" json:"before"`
+	After Ranking `bson:"after" json:"after"`
+}
+
+type JobError struct{
+	ImdbID string `bson:"imdb_id" json:"imdb_id"`
+	Error string `bson:"error" json:"error"`
+}
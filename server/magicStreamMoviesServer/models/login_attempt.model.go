@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// What a failed-login counter is kept for
+const (
+	LoginAttemptAccount = "ACCOUNT" // per e-mail address (whether or not an account has it)
+	LoginAttemptIP      = "IP"
+)
+
+//! 🚪 LoginAttempt model (failed log-ins of one account/IP since the last success)
+type LoginAttempt struct{
+	Key string `bson:"_id" json:"key"` // <kind>:<e-mail/ip>
+	Kind string `bson:"kind" json:"kind"`
+	Subject string `bson:"subject" json:"subject"` // the e-mail or IP
+	Failures int `bson:"failures" json:"failures"`
+	FirstFailureAt time.Time `bson:"first_failure_at" json:"first_failure_at"`
+	LastFailureAt time.Time `bson:"last_failure_at" json:"last_failure_at"`
+	LockedUntil *time.Time `bson:"locked_until,omitempty" json:"locked_until,omitempty"`
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"` // the counter is forgotten after a quiet period
+}
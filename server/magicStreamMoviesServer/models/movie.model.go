@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
@@ -14,6 +16,19 @@ type Ranking struct{
 	RankingName string `bson:"ranking_name" json:"ranking_name" validate:"required"`
 }
 
+// Catalog entries (genres/rankings collections), the movies & users only embed the Genre/Ranking part
+type GenreEntry struct{
+	Genre `bson:",inline"`
+	SortOrder int `bson:"sort_order" json:"sort_order"`
+	Retired bool `bson:"retired" json:"retired"`
+}
+
+type RankingEntry struct{
+	Ranking `bson:",inline"`
+	SortOrder int `bson:"sort_order" json:"sort_order"`
+	Retired bool `bson:"retired" json:"retired"`
+}
+
 //! 🎥 Movie model
 type Movie struct{
 	ID bson.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
@@ -24,4 +39,46 @@ type Movie struct{
 	Genre []Genre `bson:"genre" json:"genre" validate:"required,dive"`
 	AdminReview string `bson:"admin_review" json:"admin_review"`
 	Ranking Ranking `bson:"ranking" json:"ranking" validate:"required"`
+	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"` // tombstone (soft-delete)
+	UserRating RatingSummary `bson:"user_rating" json:"user_rating"` // audience ratings, only changed by the user-reviews
+	AudienceSentiment RatingSummary `bson:"audience_sentiment" json:"audience_sentiment"` // AI-ranked user-review texts, by ranking_value (next to the admin Ranking)
+}
+
+//! ⭐ RatingSummary (aggregate of the visible user-reviews of a movie, kept up to date incrementally)
+type RatingSummary struct{
+	Average float64 `bson:"average" json:"average"` // derived from sum/count, rounded to 2 decimals
+	Count int `bson:"count" json:"count"`
+	Sum int `bson:"sum" json:"-"`
+	Histogram map[string]int `bson:"histogram,omitempty" json:"histogram"` // rating ("1".."10") or ranking_value -> number of reviews
+}
+
+//! 🎭 SentimentShare (one ranking's part of a movie's audience-sentiment)
+type SentimentShare struct{
+	Ranking
+	Count int `json:"count"`
+	Share float64 `json:"share"` // of the ranked reviews, 0..1
+}
+
+//! ⚖️ MovieSentiment model (the admin Ranking next to the audience-sentiment, and whether they disagree)
+type MovieSentiment struct{
+	ImdbID string `json:"imdb_id"`
+	Title string `json:"title"`
+	AdminRanking Ranking `json:"admin_ranking"`
+	AudienceCount int `json:"audience_count"`
+	AudienceAverage float64 `json:"audience_average"` // mean ranking_value of the ranked reviews
+	AudienceRanking *Ranking `json:"audience_ranking"` // the most common one (nil without ranked reviews)
+	Distribution []SentimentShare `json:"distribution"`
+	Gap float64 `json:"gap"` // |audience_average - admin ranking_value|, 0 when either side isn't ranked
+	Disagreement bool `json:"disagreement"`
+}
+
+//! ✏️ MovieUpdate model (PATCH body, only the passed-in fields are changed)
+type MovieUpdate struct{
+	ImdbID *string `json:"imdb_id"`
+	Title *string `json:"title"`
+	PosterPath *string `json:"poster_path"`
+	YouTubeID *string `json:"youtube_id"`
+	Genre *[]Genre `json:"genre"`
+	AdminReview *string `json:"admin_review"` // rejected, see PATCH /update-review
+	Ranking *Ranking `json:"ranking"` // rejected, see PATCH /update-review
 }
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// What changed the admin-review/ranking
+const (
+	ReviewActionUpdate = "UPDATE" // PATCH /update-review (AI ranked)
+	ReviewActionEdit   = "EDIT"   // PUT/PATCH /movie (set by hand, older entries only: those don't change the review anymore)
+	ReviewActionRevert = "REVERT"
+	ReviewActionRerank = "RERANK" // bulk re-rank job
+)
+
+//! 📜 ReviewHistory model (append-only audit trail of the admin-reviews)
+type ReviewHistory struct{
+	ID bson.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	HistoryID string `bson:"history_id" json:"history_id"`
+	ImdbID string `bson:"imdb_id" json:"imdb_id"`
+	Action string `bson:"action" json:"action"`
+	AdminUserID string `bson:"admin_user_id" json:"admin_user_id"`
+	ReviewBefore string `bson:"review_before" json:"review_before"`
+	ReviewAfter string `bson:"review_after" json:"review_after"`
+	RankingBefore Ranking `bson:"ranking_before" json:"ranking_before"`
+	RankingAfter Ranking `bson:"ranking_after" json:"ranking_after"`
+	RawResponse string `bson:"raw_response,omitempty" json:"raw_response,omitempty"` // what the model said
+	Provider string `bson:"provider,omitempty" json:"provider,omitempty"`
+	PromptVersion string `bson:"prompt_version,omitempty" json:"prompt_version,omitempty"`
+	RevertedFrom string `bson:"reverted_from,omitempty" json:"reverted_from,omitempty"` // history_id
+	JobID string `bson:"job_id,omitempty" json:"job_id,omitempty"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Revocation kinds
+const (
+	RevocationToken   = "TOKEN"   // one access-token, by jti
+	RevocationUser    = "USER"    // every access-token of a user issued before IssuedBefore
+	RevocationSession = "SESSION" // every access-token of one session (device), by session_id
+)
+
+//! 🚫 Revocation model (denylist for access-tokens that haven't expired yet)
+type Revocation struct{
+	ID bson.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	Kind string `bson:"kind" json:"kind"`
+	TokenID string `bson:"token_id,omitempty" json:"token_id,omitempty"`
+	SessionID string `bson:"session_id,omitempty" json:"session_id,omitempty"`
+	UserID string `bson:"user_id" json:"user_id"`
+	IssuedBefore time.Time `bson:"issued_before,omitempty" json:"issued_before,omitempty"`
+	Reason string `bson:"reason" json:"reason"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"` // once every affected token has expired, the entry can go
+}
@@ -0,0 +1,57 @@
+package models
+
+// Roles
+const (
+	RoleAdmin     = "ADMIN"
+	RoleEditor    = "EDITOR"
+	RoleModerator = "MODERATOR"
+	RoleUser      = "USER"
+)
+
+// Permissions (resource:action) the protected routes require
+const (
+	PermMovieCreate    = "movie:create"
+	PermMovieUpdate    = "movie:update"
+	PermMovieDelete    = "movie:delete"
+	PermReviewWrite    = "review:write"   // admin-review (AI-ranked) & reverting it
+	PermReviewHistory  = "review:history" // reading the review-history
+	PermReviewModerate = "review:moderate"
+	PermCatalogManage  = "catalog:manage" // genres & rankings
+	PermRerankRun      = "rerank:run"
+	PermUserManage     = "user:manage"
+	PermAPIKeyManage   = "apikey:manage"
+	PermAuditRead      = "audit:read"
+)
+
+//! 🛂 Role -> permissions. ADMIN has all of them, USER none (only what every logged-in user may do)
+var RolePermissions = map[string][]string{
+	RoleAdmin: {
+		PermMovieCreate, PermMovieUpdate, PermMovieDelete,
+		PermReviewWrite, PermReviewHistory, PermReviewModerate,
+		PermCatalogManage, PermRerankRun, PermUserManage,
+		PermAPIKeyManage, PermAuditRead,
+	},
+	RoleEditor: {
+		PermMovieCreate, PermMovieUpdate,
+		PermReviewWrite, PermReviewHistory,
+		PermCatalogManage,
+	},
+	RoleModerator: {
+		PermReviewHistory, PermReviewModerate,
+	},
+	RoleUser: {},
+}
+
+func HasPermission(role,permission string)bool{
+	for _,granted:=range RolePermissions[role]{
+		if granted==permission{
+			return true
+		}
+	}
+	return false
+}
+
+// IsPermission reports whether p is a known permission (ADMIN has every one)
+func IsPermission(p string)bool{
+	return HasPermission(RoleAdmin,p)
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Why a session was revoked
+const (
+	SessionRevokedLogout          = "LOGOUT"
+	SessionRevokedByUser          = "REVOKED_BY_USER"
+	SessionRevokedReuse           = "REFRESH_TOKEN_REUSE"
+	SessionRevokedByAdmin         = "REVOKED_BY_ADMIN"
+	SessionRevokedPasswordReset   = "PASSWORD_RESET"
+	SessionRevokedPasswordChange  = "PASSWORD_CHANGE"
+	SessionRevokedAccountDeleted  = "ACCOUNT_DELETED"
+	SessionRevokedAccountDisabled = "ACCOUNT_DISABLED"
+	SessionRevokedRoleChange      = "ROLE_CHANGE" // access-tokens only, the next refresh carries the new role
+)
+
+//! 📱 Session model (one per device/login, the refresh-token family)
+type Session struct{
+	ID bson.ObjectID `bson:"_id,omitempty" json:"-"`
+	SessionID string `bson:"session_id" json:"session_id"` // token-family ID, carried by every refresh-token of the family
+	UserID string `bson:"user_id" json:"user_id"`
+	RefreshTokenID string `bson:"refresh_token_id" json:"-"` // jti of the only refresh-token that may still be used
+	UserAgent string `bson:"user_agent" json:"user_agent"`
+	IPAddress string `bson:"ip_address" json:"ip_address"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	LastUsedAt time.Time `bson:"last_used_at" json:"last_used_at"`
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
+	RevokedAt *time.Time `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	RevokedReason string `bson:"revoked_reason,omitempty" json:"revoked_reason,omitempty"`
+}
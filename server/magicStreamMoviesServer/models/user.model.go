@@ -14,12 +14,38 @@ type User struct{
 	LastName string `bson:"last_name" json:"last_name" validate:"required,min=2,max=100"`
 	Email string `bson:"email" json:"email" validate:"required,email"`
 	Password string `bson:"password" json:"password" validate:"required,min=6"`
-	Role string `bson:"role" json:"role" validate:"oneof=ADMIN USER"`
+	Role string `bson:"role" json:"role" validate:"oneof=ADMIN EDITOR MODERATOR USER"`
 	CreatedAt time.Time `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
 	Token string `bson:"token" json:"token"`
 	RefreshToken string `bson:"refresh_token" json:"refresh_token"`
 	FavouriteGenres []Genre `bson:"favourite_genres" json:"favourite_genres" validate:"required,dive"`
+	EmailVerified bool `bson:"email_verified" json:"email_verified"`
+	EmailVerifiedAt *time.Time `bson:"email_verified_at,omitempty" json:"email_verified_at,omitempty"`
+	MFA UserMFA `bson:"mfa" json:"-"`
+	Disabled bool `bson:"disabled" json:"disabled"` // by an admin: no log-in, no tokens, no API-keys
+	DisabledAt *time.Time `bson:"disabled_at,omitempty" json:"disabled_at,omitempty"`
+	DisabledReason string `bson:"disabled_reason,omitempty" json:"disabled_reason,omitempty"`
+	PasswordResetRequired bool `bson:"password_reset_required" json:"password_reset_required"` // forced by an admin, cleared by the next password-change
+}
+
+//! 📲 UserMFA (TOTP two-factor auth of a user)
+type UserMFA struct{
+	Enabled bool `bson:"enabled"`
+	Secret string `bson:"secret,omitempty"` // base32 TOTP secret, once activated
+	PendingSecret string `bson:"pending_secret,omitempty"` // enrolled, but not confirmed with a code yet
+	RecoveryCodes []string `bson:"recovery_codes,omitempty"` // SHA-256 hashes of the unused recovery-codes
+	LastUsedStep int64 `bson:"last_used_step"` // a TOTP code works only once
+	EnrolledAt *time.Time `bson:"enrolled_at,omitempty"`
+}
+
+//! 📝 UserRegister model (POST /register body). No role: every sign-up is a USER
+type UserRegister struct{
+	FirstName string `json:"first_name" validate:"required,min=2,max=100"`
+	LastName string `json:"last_name" validate:"required,min=2,max=100"`
+	Email string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=6"`
+	FavouriteGenres []Genre `json:"favourite_genres" validate:"required,dive"`
 }
 
 //! 🔐 UserLogin Model
@@ -38,4 +64,41 @@ type UserResponse struct {
 	Token string `json:"token"`
 	RefreshToken string `json:"refresh_token"`
 	FavouriteGenres []Genre `json:"favourite_genres"`
+	RecoveryCodes []string `json:"recovery_codes,omitempty"` // only right after enrolling MFA during the log-in
+	EmailVerified bool `json:"email_verified"`
+}
+
+//! ✏️ UserProfileUpdate model (PATCH /me body, only the passed-in fields are changed)
+type UserProfileUpdate struct{
+	FirstName *string `json:"first_name" validate:"omitempty,min=2,max=100"`
+	LastName *string `json:"last_name" validate:"omitempty,min=2,max=100"`
+	FavouriteGenres *[]Genre `json:"favourite_genres"` // only the genre_id's count, the names come from the catalog
+}
+
+//! 🔑 PasswordChange model
+type PasswordChange struct{
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=6"`
+}
+
+//! 🗂️ AdminUserResponse model (a user as the admin user-management sees it, without any secrets)
+type AdminUserResponse struct{
+	UserID string `json:"user_id"`
+	FirstName string `json:"first_name"`
+	LastName string `json:"last_name"`
+	Email string `json:"email"`
+	Role string `json:"role"`
+	EmailVerified bool `json:"email_verified"`
+	MFAEnabled bool `json:"mfa_enabled"`
+	Disabled bool `json:"disabled"`
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+	DisabledReason string `json:"disabled_reason,omitempty"`
+	PasswordResetRequired bool `json:"password_reset_required"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+//! 🎭 RoleChange model (PUT /admin/users/:user_id/role body)
+type RoleChange struct{
+	Role string `json:"role" validate:"required,oneof=ADMIN EDITOR MODERATOR USER"`
 }
\ No newline at end of file
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Ratings users can give
+const (
+	MinUserRating = 1
+	MaxUserRating = 10
+)
+
+// Sentiment-status of a user-review's text (none when there's no text)
+const (
+	SentimentPending = "PENDING"
+	SentimentRanked  = "RANKED"
+	SentimentFailed  = "FAILED"
+)
+
+//! 💬 UserReview model (a user's rating & optional text for a movie, one per user & movie)
+type UserReview struct{
+	ID bson.ObjectID `bson:"_id,omitempty" json:"-"`
+	ReviewID string `bson:"review_id" json:"review_id"`
+	ImdbID string `bson:"imdb_id" json:"imdb_id"`
+	UserID string `bson:"user_id" json:"user_id"`
+	AuthorName string `bson:"author_name" json:"author_name"` // first name & last initial, as shown with the review
+	Rating int `bson:"rating" json:"rating"`
+	Text string `bson:"text,omitempty" json:"text,omitempty"`
+	Hidden bool `bson:"hidden" json:"hidden"` // by a moderator: not listed & not counted in the movie's user_rating
+	HiddenBy string `bson:"hidden_by,omitempty" json:"hidden_by,omitempty"`
+	HiddenReason string `bson:"hidden_reason,omitempty" json:"hidden_reason,omitempty"`
+	HiddenAt *time.Time `bson:"hidden_at,omitempty" json:"hidden_at,omitempty"`
+	Sentiment *Ranking `bson:"sentiment,omitempty" json:"sentiment,omitempty"` // the text, AI-ranked against the rankings (in the background)
+	SentimentStatus string `bson:"sentiment_status,omitempty" json:"sentiment_status,omitempty"`
+	SentimentProvider string `bson:"sentiment_provider,omitempty" json:"-"`
+	SentimentError string `bson:"sentiment_error,omitempty" json:"-"`
+	SentimentAt *time.Time `bson:"sentiment_at,omitempty" json:"-"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+//! 🗣️ PublicUserReview (subset/DTO) - a review as everyone sees it, the author only by author_name
+type PublicUserReview struct{
+	ReviewID string `json:"review_id"`
+	ImdbID string `json:"imdb_id"`
+	AuthorName string `json:"author_name"`
+	Rating int `json:"rating"`
+	Text string `json:"text,omitempty"`
+	Sentiment *Ranking `json:"sentiment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+//! ✍️ UserReviewInput model (PUT /movie/:imdb_id/review body)
+type UserReviewInput struct{
+	Rating int `json:"rating" validate:"required,min=1,max=10"`
+	Text string `json:"text" validate:"max=5000"`
+}
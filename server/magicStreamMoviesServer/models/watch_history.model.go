@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Playback-event types (the trailer player reports them)
+const (
+	PlaybackStart    = "START"
+	PlaybackProgress = "PROGRESS" // heartbeat while playing
+	PlaybackComplete = "COMPLETE"
+)
+
+//! 📼 WatchHistory model (a user's viewing of a movie's trailer, one per user & movie)
+type WatchHistory struct{
+	ID bson.ObjectID `bson:"_id,omitempty" json:"-"`
+	UserID string `bson:"user_id" json:"user_id"`
+	ImdbID string `bson:"imdb_id" json:"imdb_id"`
+	PositionSeconds float64 `bson:"position_seconds" json:"position_seconds"` // where to resume
+	DurationSeconds float64 `bson:"duration_seconds" json:"duration_seconds"` // as the player reported it (0 = unknown)
+	InProgress bool `bson:"in_progress" json:"in_progress"` // started & not finished: "continue watching"
+	Completed bool `bson:"completed" json:"completed"` // the last playback reached the end
+	PlayCount int `bson:"play_count" json:"play_count"`
+	FirstWatchedAt time.Time `bson:"first_watched_at" json:"first_watched_at"`
+	LastWatchedAt time.Time `bson:"last_watched_at" json:"last_watched_at"`
+	CompletedAt *time.Time `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	EventIDs []string `bson:"event_ids,omitempty" json:"-"` // the latest ones, so a retried event isn't applied twice
+}
+
+//! 🎞️ WatchHistoryItem model (an entry with its movie, nil once the movie was deleted)
+type WatchHistoryItem struct{
+	WatchHistory
+	Available bool `json:"available"`
+	Movie *Movie `json:"movie"`
+}
+
+//! 📡 PlaybackEvent model (POST /movie/:imdb_id/playback body)
+type PlaybackEvent struct{
+	EventID string `json:"event_id" validate:"required,max=64"` // generated by the player, a retry sends the same one
+	Type string `json:"type" validate:"required,oneof=START PROGRESS COMPLETE"`
+	PositionSeconds float64 `json:"position_seconds" validate:"min=0,max=86400"`
+	DurationSeconds float64 `json:"duration_seconds" validate:"min=0,max=86400"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+//! 📌 WatchlistEntry model (one movie on a user's watchlist, unique per user & imdb_id)
+type WatchlistEntry struct{
+	ID bson.ObjectID `bson:"_id,omitempty" json:"-"`
+	UserID string `bson:"user_id" json:"user_id"`
+	ImdbID string `bson:"imdb_id" json:"imdb_id"`
+	Position int `bson:"position" json:"position"` // 1-based, ascending
+	AddedAt time.Time `bson:"added_at" json:"added_at"`
+}
+
+//! 🎞️ WatchlistItem model (an entry with its movie, nil once the movie was deleted)
+type WatchlistItem struct{
+	ImdbID string `json:"imdb_id"`
+	Position int `json:"position"`
+	AddedAt time.Time `json:"added_at"`
+	Available bool `json:"available"`
+	Movie *Movie `json:"movie"`
+}
+
+//! ➕ WatchlistAdd model (POST /me/watchlist body)
+type WatchlistAdd struct{
+	ImdbID string `json:"imdb_id" validate:"required"`
+}
+
+//! 🔀 WatchlistOrder model (PUT /me/watchlist/order body, every imdb_id of the watchlist exactly once)
+type WatchlistOrder struct{
+	ImdbIDs []string `json:"imdb_ids" validate:"required,min=1"`
+}
@@ -0,0 +1,298 @@
+// Package recommend picks the movies to recommend to a user 🎯
+// Genre-affinity (favourite genres, and what the user watched, put on the watchlist or rated) is combined with the
+// admin Ranking & the audience rating. Seen movies are left out, and the picks are spread across genres (MMR).
+package recommend
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/ai"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+// Affinity a signal adds to each genre of its movie
+const (
+	favouriteGenreWeight = 3.0
+	completedWeight      = 1.0
+	startedWeight        = 0.5
+	watchlistWeight      = 0.75
+	// A rating of 10 adds this much, a rating of 1 takes it away
+	ratingWeight = 2.0
+)
+
+// Score = genre-affinity, admin Ranking & audience rating, weighted
+const (
+	genreScoreWeight    = 0.6
+	rankingScoreWeight  = 0.3
+	audienceScoreWeight = 0.1
+	// The audience rating only counts from this many ratings on
+	minAudienceRatings = 3
+	// MMR: 1 = by score only, lower = more spread across genres
+	diversityLambda = 0.7
+)
+
+// Where a genre's affinity comes from (the explanation)
+const (
+	sourceFavourite = "favourite"
+	sourceLiked     = "liked"
+	sourceWatched   = "watched"
+	sourceWatchlist = "watchlist"
+)
+
+// Profile is what's known about the user
+type Profile struct {
+	FavouriteGenres []models.Genre
+	History         []models.WatchHistory
+	Watchlist       []models.WatchlistEntry
+	Reviews         []models.UserReview
+	// Seen are the imdb_ids of every movie the user watched or reviewed, History & Reviews may only be the latest ones
+	Seen []string
+}
+
+type Recommendation struct {
+	Movie       models.Movie
+	Score       float64
+	Explanation string
+}
+
+type genreAffinity struct {
+	name    string
+	total   float64
+	sources map[string]float64
+}
+
+type affinities map[int]*genreAffinity
+
+func (a affinities) add(genres []models.Genre, source string, weight float64) {
+	for _, genre := range genres {
+		affinity, ok := a[genre.GenreID]
+		if !ok {
+			affinity = &genreAffinity{name: genre.GenreName, sources: map[string]float64{}}
+			a[genre.GenreID] = affinity
+		}
+		affinity.total += weight
+		affinity.sources[source] += weight
+	}
+}
+
+// reason tells why the user likes the genre, after its strongest (positive) source
+func (affinity *genreAffinity) reason() string {
+	source, best := "", 0.0
+	for _, candidate := range []string{sourceLiked, sourceFavourite, sourceWatched, sourceWatchlist} {
+		if affinity.sources[candidate] > best {
+			source, best = candidate, affinity.sources[candidate]
+		}
+	}
+	switch source {
+	case sourceLiked:
+		return fmt.Sprintf("because you liked %s", affinity.name)
+	case sourceFavourite:
+		return fmt.Sprintf("because %s is one of your favourite genres", affinity.name)
+	case sourceWatched:
+		return fmt.Sprintf("because you watched %s", affinity.name)
+	case sourceWatchlist:
+		return fmt.Sprintf("because you have %s on your watchlist", affinity.name)
+	}
+	return ""
+}
+
+type candidate struct {
+	Recommendation
+	genres map[int]bool
+}
+
+// ImdbIDs are the movies the profile refers to (their genres make up the genre-affinity)
+func (profile Profile) ImdbIDs() []string {
+	var imdbIDs []string
+	for _, entry := range profile.History {
+		imdbIDs = append(imdbIDs, entry.ImdbID)
+	}
+	for _, entry := range profile.Watchlist {
+		imdbIDs = append(imdbIDs, entry.ImdbID)
+	}
+	for _, review := range profile.Reviews {
+		imdbIDs = append(imdbIDs, review.ImdbID)
+	}
+	slices.Sort(imdbIDs)
+	return slices.Compact(imdbIDs)
+}
+
+// LikedGenres returns the names of the genres the user has a positive affinity for, the strongest first.
+// movies must hold (at least) the profile's movies
+func LikedGenres(profile Profile, movies []models.Movie) []string {
+	genres, _ := profileAffinities(profile, movies)
+
+	var liked []*genreAffinity
+	for _, affinity := range genres {
+		if affinity.total > 0 {
+			liked = append(liked, affinity)
+		}
+	}
+	slices.SortFunc(liked, func(a, b *genreAffinity) int {
+		return cmp.Or(cmp.Compare(b.total, a.total), cmp.Compare(a.name, b.name))
+	})
+
+	names := make([]string, 0, len(liked))
+	for _, affinity := range liked {
+		names = append(names, affinity.name)
+	}
+	return names
+}
+
+// Recommend returns up to limit unseen movies of the catalog for the profile, best first.
+// The catalog must hold the profile's movies (for the genre-affinity) besides the candidates
+func Recommend(profile Profile, catalog []models.Movie, limit int) []Recommendation {
+	genres, seen := profileAffinities(profile, catalog)
+	return rank(genres, seen, catalog, limit)
+}
+
+// profileAffinities builds the genre-affinity out of the explicit & implicit signals, and tells the seen movies
+func profileAffinities(profile Profile, movies []models.Movie) (affinities, map[string]bool) {
+	byImdbID := map[string]models.Movie{}
+	for _, movie := range movies {
+		byImdbID[movie.ImdbID] = movie
+	}
+
+	genres := affinities{}
+	genres.add(profile.FavouriteGenres, sourceFavourite, favouriteGenreWeight)
+	seen := map[string]bool{}
+	for _, imdbID := range profile.Seen {
+		seen[imdbID] = true
+	}
+	for _, entry := range profile.History {
+		seen[entry.ImdbID] = true
+		weight := startedWeight
+		if entry.Completed {
+			weight = completedWeight
+		}
+		genres.add(byImdbID[entry.ImdbID].Genre, sourceWatched, weight)
+	}
+	for _, entry := range profile.Watchlist {
+		genres.add(byImdbID[entry.ImdbID].Genre, sourceWatchlist, watchlistWeight)
+	}
+	for _, review := range profile.Reviews {
+		seen[review.ImdbID] = true
+		// 1..10 -> -1..1
+		liking := (float64(review.Rating) - 5.5) / 4.5
+		genres.add(byImdbID[review.ImdbID].Genre, sourceLiked, liking*ratingWeight)
+	}
+	return genres, seen
+}
+
+func rank(genres affinities, seen map[string]bool, catalog []models.Movie, limit int) []Recommendation {
+	maxAffinity := 0.0
+	for _, affinity := range genres {
+		maxAffinity = max(maxAffinity, math.Abs(affinity.total))
+	}
+
+	// Ranking-values spread onto 1 (best) .. 0, "not ranked" is in the middle
+	minValue, maxValue := math.MaxInt, 0
+	for _, movie := range catalog {
+		if value := movie.Ranking.RankingValue; value > 0 && value != ai.NotRankedValue {
+			minValue, maxValue = min(minValue, value), max(maxValue, value)
+		}
+	}
+	rankingScore := func(ranking models.Ranking) float64 {
+		value := ranking.RankingValue
+		if value <= 0 || value == ai.NotRankedValue || maxValue <= minValue {
+			return 0.5
+		}
+		return float64(maxValue-value) / float64(maxValue-minValue)
+	}
+
+	var candidates []candidate
+	for _, movie := range catalog {
+		if seen[movie.ImdbID] {
+			continue
+		}
+
+		// The best-liked of the movie's genres carries it
+		var best *genreAffinity
+		genreScore := 0.0
+		movieGenres := map[int]bool{}
+		for _, genre := range movie.Genre {
+			movieGenres[genre.GenreID] = true
+			affinity, ok := genres[genre.GenreID]
+			if !ok || maxAffinity == 0 {
+				continue
+			}
+			if score := affinity.total / maxAffinity; best == nil || score > genreScore {
+				best, genreScore = affinity, score
+			}
+		}
+
+		audienceScore := 0.0
+		if movie.UserRating.Count >= minAudienceRatings {
+			audienceScore = movie.UserRating.Average / models.MaxUserRating
+		}
+
+		score := genreScoreWeight*genreScore + rankingScoreWeight*rankingScore(movie.Ranking) + audienceScoreWeight*audienceScore
+
+		explanation := ""
+		if best != nil && genreScore > 0 {
+			explanation = best.reason()
+		}
+		if explanation == "" {
+			switch {
+			case audienceScore > 0 && audienceScore >= rankingScore(movie.Ranking):
+				explanation = fmt.Sprintf("because viewers rate it %.1f/10", movie.UserRating.Average)
+			case movie.Ranking.RankingValue != ai.NotRankedValue && movie.Ranking.RankingName != "":
+				explanation = fmt.Sprintf("because it's ranked %s", movie.Ranking.RankingName)
+			default:
+				explanation = "because it's new to you"
+			}
+		}
+
+		candidates = append(candidates, candidate{
+			Recommendation: Recommendation{Movie: movie, Score: math.Round(score*1000) / 1000, Explanation: explanation},
+			genres:         movieGenres,
+		})
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Movie.ImdbID, b.Movie.ImdbID))
+	})
+
+	return diversify(candidates, limit)
+}
+
+// diversify picks by maximal marginal relevance: the score, minus how much the movie resembles (genre-wise)
+// the ones picked already
+func diversify(candidates []candidate, limit int) []Recommendation {
+	picked := []Recommendation{}
+	var pickedGenres []map[int]bool
+
+	for len(picked) < limit && len(candidates) > 0 {
+		bestIndex, bestMMR := 0, math.Inf(-1)
+		for i, c := range candidates {
+			similarity := 0.0
+			for _, genres := range pickedGenres {
+				similarity = max(similarity, jaccard(c.genres, genres))
+			}
+			// Sorted by score already, so on a tie the better-scored one wins
+			if mmr := diversityLambda*c.Score - (1-diversityLambda)*similarity; mmr > bestMMR {
+				bestIndex, bestMMR = i, mmr
+			}
+		}
+
+		picked = append(picked, candidates[bestIndex].Recommendation)
+		pickedGenres = append(pickedGenres, candidates[bestIndex].genres)
+		candidates = slices.Delete(candidates, bestIndex, bestIndex+1)
+	}
+	return picked
+}
+
+func jaccard(a, b map[int]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for id := range a {
+		if b[id] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type MemoryActionTokenRepository struct {
+	mu     sync.Mutex
+	tokens []models.ActionToken
+}
+
+func NewMemoryActionTokenRepository() *MemoryActionTokenRepository {
+	return &MemoryActionTokenRepository{}
+}
+
+func (r *MemoryActionTokenRepository) Insert(ctx context.Context, token *models.ActionToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if token.ID.IsZero() {
+		token.ID = bson.NewObjectID()
+	}
+	r.tokens = append(r.tokens, *token)
+	return nil
+}
+
+func (r *MemoryActionTokenRepository) Consume(ctx context.Context, tokenID, purpose string, at time.Time) (*models.ActionToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.tokens {
+		t := &r.tokens[i]
+		if t.TokenID == tokenID && t.Purpose == purpose && t.UsedAt == nil && t.ExpiresAt.After(at) {
+			t.UsedAt = &at
+			token := *t
+			return &token, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryActionTokenRepository) InvalidateAll(ctx context.Context, userID, purpose string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.tokens {
+		if r.tokens[i].UserID == userID && r.tokens[i].Purpose == purpose && r.tokens[i].UsedAt == nil {
+			r.tokens[i].UsedAt = &at
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/database"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type MongoActionTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoActionTokenRepository(client *mongo.Client) *MongoActionTokenRepository {
+	return &MongoActionTokenRepository{collection: database.OpenCollection("action_tokens", client)}
+}
+
+func (r *MongoActionTokenRepository) Insert(ctx context.Context, token *models.ActionToken) error {
+	result, err := r.collection.InsertOne(ctx, token)
+	if err != nil {
+		return err
+	}
+	if id, ok := result.InsertedID.(bson.ObjectID); ok {
+		token.ID = id
+	}
+	return nil
+}
+
+func (r *MongoActionTokenRepository) Consume(ctx context.Context, tokenID, purpose string, at time.Time) (*models.ActionToken, error) {
+	filter := bson.M{
+		"token_id":   tokenID,
+		"purpose":    purpose,
+		"used_at":    bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": at},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var token models.ActionToken
+	err := r.collection.FindOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{"used_at": at}}, opts).Decode(&token)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *MongoActionTokenRepository) InvalidateAll(ctx context.Context, userID, purpose string, at time.Time) error {
+	filter := bson.M{"user_id": userID, "purpose": purpose, "used_at": bson.M{"$exists": false}}
+	_, err := r.collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"used_at": at}})
+	return err
+}
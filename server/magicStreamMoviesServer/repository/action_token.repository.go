@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+type ActionTokenRepository interface {
+	Insert(ctx context.Context, token *models.ActionToken) error
+	// Consume marks the token used, ErrNotFound when it's unknown, used already or expired
+	Consume(ctx context.Context, tokenID, purpose string, at time.Time) (*models.ActionToken, error)
+	// InvalidateAll uses up every open token of a user for a purpose (a newer one was issued, or the action happened)
+	InvalidateAll(ctx context.Context, userID, purpose string, at time.Time) error
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type MemoryAPIKeyRepository struct {
+	mu   sync.RWMutex
+	keys []models.APIKey
+}
+
+func NewMemoryAPIKeyRepository() *MemoryAPIKeyRepository {
+	return &MemoryAPIKeyRepository{}
+}
+
+func (r *MemoryAPIKeyRepository) Insert(ctx context.Context, key *models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if slices.ContainsFunc(r.keys, func(k models.APIKey) bool { return k.KeyID == key.KeyID || k.KeyHash == key.KeyHash }) {
+		return ErrDuplicate
+	}
+	if key.ID.IsZero() {
+		key.ID = bson.NewObjectID()
+	}
+	r.keys = append(r.keys, *key)
+	return nil
+}
+
+func (r *MemoryAPIKeyRepository) find(match func(models.APIKey) bool) (*models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := slices.IndexFunc(r.keys, match)
+	if i == -1 {
+		return nil, ErrNotFound
+	}
+	key := r.keys[i]
+	key.Scopes = slices.Clone(key.Scopes)
+	return &key, nil
+}
+
+func (r *MemoryAPIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	return r.find(func(k models.APIKey) bool { return k.KeyHash == keyHash })
+}
+
+func (r *MemoryAPIKeyRepository) FindByKeyID(ctx context.Context, keyID string) (*models.APIKey, error) {
+	return r.find(func(k models.APIKey) bool { return k.KeyID == keyID })
+}
+
+func (r *MemoryAPIKeyRepository) List(ctx context.Context) ([]models.APIKey, error) {
+	return r.list(func(models.APIKey) bool { return true }), nil
+}
+
+func (r *MemoryAPIKeyRepository) ListByUser(ctx context.Context, userID string) ([]models.APIKey, error) {
+	return r.list(func(k models.APIKey) bool { return k.UserID == userID }), nil
+}
+
+func (r *MemoryAPIKeyRepository) list(match func(models.APIKey) bool) []models.APIKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := []models.APIKey{}
+	for i := len(r.keys) - 1; i >= 0; i-- {
+		if match(r.keys[i]) {
+			keys = append(keys, r.keys[i])
+		}
+	}
+	return keys
+}
+
+func (r *MemoryAPIKeyRepository) update(keyID string, fn func(*models.APIKey) bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.keys, func(k models.APIKey) bool { return k.KeyID == keyID })
+	if i == -1 || !fn(&r.keys[i]) {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MemoryAPIKeyRepository) UpdateScopes(ctx context.Context, keyID string, scopes []string) error {
+	return r.update(keyID, func(k *models.APIKey) bool {
+		k.Scopes = slices.Clone(scopes)
+		return true
+	})
+}
+
+func (r *MemoryAPIKeyRepository) Revoke(ctx context.Context, keyID string, at time.Time) error {
+	return r.update(keyID, func(k *models.APIKey) bool {
+		if k.RevokedAt != nil {
+			return false
+		}
+		k.RevokedAt = &at
+		return true
+	})
+}
+
+func (r *MemoryAPIKeyRepository) Touch(ctx context.Context, keyID string, usedAt time.Time) error {
+	return r.update(keyID, func(k *models.APIKey) bool {
+		k.LastUsedAt = &usedAt
+		return true
+	})
+}
+
+func (r *MemoryAPIKeyRepository) DeleteAllForUser(ctx context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before := len(r.keys)
+	r.keys = slices.DeleteFunc(r.keys, func(k models.APIKey) bool { return k.UserID == userID })
+	return int64(before - len(r.keys)), nil
+}
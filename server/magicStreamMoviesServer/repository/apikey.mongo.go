@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/database"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type MongoAPIKeyRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoAPIKeyRepository(client *mongo.Client) *MongoAPIKeyRepository {
+	return &MongoAPIKeyRepository{collection: database.OpenCollection("api_keys", client)}
+}
+
+func (r *MongoAPIKeyRepository) Insert(ctx context.Context, key *models.APIKey) error {
+	result, err := r.collection.InsertOne(ctx, key)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	if id, ok := result.InsertedID.(bson.ObjectID); ok {
+		key.ID = id
+	}
+	return nil
+}
+
+func (r *MongoAPIKeyRepository) findOne(ctx context.Context, filter bson.M) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.collection.FindOne(ctx, filter).Decode(&key)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *MongoAPIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	return r.findOne(ctx, bson.M{"key_hash": keyHash})
+}
+
+func (r *MongoAPIKeyRepository) FindByKeyID(ctx context.Context, keyID string) (*models.APIKey, error) {
+	return r.findOne(ctx, bson.M{"key_id": keyID})
+}
+
+func (r *MongoAPIKeyRepository) List(ctx context.Context) ([]models.APIKey, error) {
+	return r.list(ctx, bson.M{})
+}
+
+func (r *MongoAPIKeyRepository) ListByUser(ctx context.Context, userID string) ([]models.APIKey, error) {
+	return r.list(ctx, bson.M{"user_id": userID})
+}
+
+func (r *MongoAPIKeyRepository) list(ctx context.Context, filter bson.M) ([]models.APIKey, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	keys := []models.APIKey{}
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *MongoAPIKeyRepository) update(ctx context.Context, filter bson.M, set bson.M) error {
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoAPIKeyRepository) UpdateScopes(ctx context.Context, keyID string, scopes []string) error {
+	return r.update(ctx, bson.M{"key_id": keyID}, bson.M{"scopes": scopes})
+}
+
+func (r *MongoAPIKeyRepository) Revoke(ctx context.Context, keyID string, at time.Time) error {
+	filter := bson.M{"key_id": keyID, "revoked_at": bson.M{"$exists": false}}
+	return r.update(ctx, filter, bson.M{"revoked_at": at})
+}
+
+func (r *MongoAPIKeyRepository) Touch(ctx context.Context, keyID string, usedAt time.Time) error {
+	return r.update(ctx, bson.M{"key_id": keyID}, bson.M{"last_used_at": usedAt})
+}
+
+func (r *MongoAPIKeyRepository) DeleteAllForUser(ctx context.Context, userID string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
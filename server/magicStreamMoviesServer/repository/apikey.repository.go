@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+type APIKeyRepository interface {
+	Insert(ctx context.Context, key *models.APIKey) error
+	// FindByHash also returns revoked & expired keys, check APIKey.Active
+	FindByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	FindByKeyID(ctx context.Context, keyID string) (*models.APIKey, error)
+	// List returns every key, newest first
+	List(ctx context.Context) ([]models.APIKey, error)
+	// ListByUser returns the keys acting as the user, newest first
+	ListByUser(ctx context.Context, userID string) ([]models.APIKey, error)
+	UpdateScopes(ctx context.Context, keyID string, scopes []string) error
+	// Revoke fails with ErrNotFound when the key doesn't exist or is already revoked
+	Revoke(ctx context.Context, keyID string, at time.Time) error
+	Touch(ctx context.Context, keyID string, usedAt time.Time) error
+	DeleteAllForUser(ctx context.Context, userID string) (int64, error)
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type MemoryAuditRepository struct {
+	mu     sync.RWMutex
+	events []models.AuditEvent
+}
+
+func NewMemoryAuditRepository() *MemoryAuditRepository {
+	return &MemoryAuditRepository{}
+}
+
+func (r *MemoryAuditRepository) Append(ctx context.Context, event *models.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if event.ID.IsZero() {
+		event.ID = bson.NewObjectID()
+	}
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *MemoryAuditRepository) Redact(ctx context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var redacted int64
+	for i := range r.events {
+		event := &r.events[i]
+		if event.ActorID != userID && event.TargetUserID != userID {
+			continue
+		}
+		if event.IPAddress != "" || event.Details != nil {
+			event.IPAddress, event.Details = "", nil
+			redacted++
+		}
+	}
+	return redacted, nil
+}
+
+func (r *MemoryAuditRepository) List(ctx context.Context, filter AuditFilter, limit int64) ([]models.AuditEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events := []models.AuditEvent{}
+	for i := len(r.events) - 1; i >= 0 && int64(len(events)) < limit; i-- {
+		event := r.events[i]
+		if filter.Type != "" && event.Type != filter.Type {
+			continue
+		}
+		if filter.UserID != "" && event.ActorID != filter.UserID && event.TargetUserID != filter.UserID {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
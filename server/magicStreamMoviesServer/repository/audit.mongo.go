@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/database"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type MongoAuditRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoAuditRepository(client *mongo.Client) *MongoAuditRepository {
+	return &MongoAuditRepository{collection: database.OpenCollection("audit_events", client)}
+}
+
+func (r *MongoAuditRepository) Append(ctx context.Context, event *models.AuditEvent) error {
+	result, err := r.collection.InsertOne(ctx, event)
+	if err != nil {
+		return err
+	}
+	if id, ok := result.InsertedID.(bson.ObjectID); ok {
+		event.ID = id
+	}
+	return nil
+}
+
+func (r *MongoAuditRepository) Redact(ctx context.Context, userID string) (int64, error) {
+	filter := bson.M{"$and": bson.A{
+		bson.M{"$or": bson.A{bson.M{"actor_id": userID}, bson.M{"target_user_id": userID}}},
+		bson.M{"$or": bson.A{bson.M{"ip_address": bson.M{"$exists": true}}, bson.M{"details": bson.M{"$exists": true}}}},
+	}}
+	result, err := r.collection.UpdateMany(ctx, filter, bson.M{"$unset": bson.M{"ip_address": "", "details": ""}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *MongoAuditRepository) List(ctx context.Context, filter AuditFilter, limit int64) ([]models.AuditEvent, error) {
+	query := bson.M{}
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+	if filter.UserID != "" {
+		query["$or"] = bson.A{bson.M{"actor_id": filter.UserID}, bson.M{"target_user_id": filter.UserID}}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	events := []models.AuditEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
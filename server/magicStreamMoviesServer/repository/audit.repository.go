@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+// AuditFilter narrows List, empty fields match everything
+type AuditFilter struct {
+	Type   string
+	UserID string // actor or target
+}
+
+// AuditRepository is append-only, like the review-history (but for Redact)
+type AuditRepository interface {
+	Append(ctx context.Context, event *models.AuditEvent) error
+	// List returns the newest events first
+	List(ctx context.Context, filter AuditFilter, limit int64) ([]models.AuditEvent, error)
+	// Redact strips the ip_address & details of every event the user did or was the target of (account deletion),
+	// only the type, the (pseudonymous) IDs & the time are kept. Returns how many events were changed
+	Redact(ctx context.Context, userID string) (int64, error)
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+type MemoryGenreRepository struct {
+	mu      sync.RWMutex
+	entries []models.GenreEntry
+}
+
+func NewMemoryGenreRepository() *MemoryGenreRepository {
+	return &MemoryGenreRepository{}
+}
+
+func (r *MemoryGenreRepository) seed(entries []models.GenreEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entries...)
+}
+
+func (r *MemoryGenreRepository) List(ctx context.Context) ([]models.Genre, error) {
+	entries, _ := r.ListEntries(ctx, false)
+	return activeGenres(entries), nil
+}
+
+func (r *MemoryGenreRepository) ListEntries(ctx context.Context, includeRetired bool) ([]models.GenreEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := []models.GenreEntry{}
+	for _, entry := range r.entries {
+		if includeRetired || !entry.Retired {
+			entries = append(entries, entry)
+		}
+	}
+	slices.SortFunc(entries, func(a, b models.GenreEntry) int {
+		return cmp.Or(cmp.Compare(a.SortOrder, b.SortOrder), cmp.Compare(a.GenreID, b.GenreID))
+	})
+	return entries, nil
+}
+
+func (r *MemoryGenreRepository) indexOf(genreID int) int {
+	return slices.IndexFunc(r.entries, func(e models.GenreEntry) bool { return e.GenreID == genreID })
+}
+
+func (r *MemoryGenreRepository) nameTaken(name string, exceptID int) bool {
+	return slices.ContainsFunc(r.entries, func(e models.GenreEntry) bool {
+		return e.GenreID != exceptID && strings.EqualFold(e.GenreName, name)
+	})
+}
+
+func (r *MemoryGenreRepository) FindByID(ctx context.Context, genreID int) (*models.GenreEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.indexOf(genreID)
+	if i == -1 {
+		return nil, ErrNotFound
+	}
+	entry := r.entries[i]
+	return &entry, nil
+}
+
+func (r *MemoryGenreRepository) Insert(ctx context.Context, entry *models.GenreEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.indexOf(entry.GenreID) != -1 || r.nameTaken(entry.GenreName, entry.GenreID) {
+		return ErrDuplicate
+	}
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *MemoryGenreRepository) Rename(ctx context.Context, genreID int, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(genreID)
+	if i == -1 {
+		return ErrNotFound
+	}
+	if r.nameTaken(name, genreID) {
+		return ErrDuplicate
+	}
+	r.entries[i].GenreName = name
+	return nil
+}
+
+func (r *MemoryGenreRepository) Reorder(ctx context.Context, genreIDs []int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for position, genreID := range genreIDs {
+		if i := r.indexOf(genreID); i != -1 {
+			r.entries[i].SortOrder = position + 1
+		}
+	}
+	return nil
+}
+
+func (r *MemoryGenreRepository) SetRetired(ctx context.Context, genreID int, retired bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(genreID)
+	if i == -1 {
+		return ErrNotFound
+	}
+	r.entries[i].Retired = retired
+	return nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/database"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type MongoGenreRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoGenreRepository(client *mongo.Client) *MongoGenreRepository {
+	return &MongoGenreRepository{collection: database.OpenCollection("genres", client)}
+}
+
+// Case-insensitive exact match on a name
+func nameFilter(field, name string) bson.M {
+	return bson.M{field: bson.M{"$regex": "^" + regexp.QuoteMeta(name) + "$", "$options": "i"}}
+}
+
+func (r *MongoGenreRepository) List(ctx context.Context) ([]models.Genre, error) {
+	entries, err := r.ListEntries(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	return activeGenres(entries), nil
+}
+
+func (r *MongoGenreRepository) ListEntries(ctx context.Context, includeRetired bool) ([]models.GenreEntry, error) {
+	filter := bson.M{}
+	if !includeRetired {
+		filter["retired"] = bson.M{"$ne": true}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "sort_order", Value: 1}, {Key: "genre_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []models.GenreEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *MongoGenreRepository) FindByID(ctx context.Context, genreID int) (*models.GenreEntry, error) {
+	var entry models.GenreEntry
+	err := r.collection.FindOne(ctx, bson.M{"genre_id": genreID}).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *MongoGenreRepository) Insert(ctx context.Context, entry *models.GenreEntry) error {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"$or": bson.A{
+		bson.M{"genre_id": entry.GenreID},
+		nameFilter("genre_name", entry.GenreName),
+	}})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrDuplicate
+	}
+
+	_, err = r.collection.InsertOne(ctx, entry)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *MongoGenreRepository) Rename(ctx context.Context, genreID int, name string) error {
+	filter := nameFilter("genre_name", name)
+	filter["genre_id"] = bson.M{"$ne": genreID}
+	count, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrDuplicate
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"genre_id": genreID}, bson.M{"$set": bson.M{"genre_name": name}})
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoGenreRepository) Reorder(ctx context.Context, genreIDs []int) error {
+	writes := make([]mongo.WriteModel, 0, len(genreIDs))
+	for i, genreID := range genreIDs {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"genre_id": genreID}).
+			SetUpdate(bson.M{"$set": bson.M{"sort_order": i + 1}}))
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+	_, err := r.collection.BulkWrite(ctx, writes)
+	return err
+}
+
+func (r *MongoGenreRepository) SetRetired(ctx context.Context, genreID int, retired bool) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"genre_id": genreID}, bson.M{"$set": bson.M{"retired": retired}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
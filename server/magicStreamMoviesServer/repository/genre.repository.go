@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+type GenreRepository interface {
+	// List returns the active genres, ordered by sort_order (then genre_id)
+	List(ctx context.Context) ([]models.Genre, error)
+	ListEntries(ctx context.Context, includeRetired bool) ([]models.GenreEntry, error)
+	FindByID(ctx context.Context, genreID int) (*models.GenreEntry, error)
+	// Insert fails with ErrDuplicate on a taken genre_id or genre_name
+	Insert(ctx context.Context, entry *models.GenreEntry) error
+	Rename(ctx context.Context, genreID int, name string) error
+	// Reorder sets sort_order to the position in genreIDs
+	Reorder(ctx context.Context, genreIDs []int) error
+	SetRetired(ctx context.Context, genreID int, retired bool) error
+}
+
+func activeGenres(entries []models.GenreEntry) []models.Genre {
+	genres := []models.Genre{}
+	for _, entry := range entries {
+		if !entry.Retired {
+			genres = append(genres, entry.Genre)
+		}
+	}
+	return genres
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type MemoryJobRepository struct {
+	mu   sync.RWMutex
+	jobs []models.RerankJob
+}
+
+func NewMemoryJobRepository() *MemoryJobRepository {
+	return &MemoryJobRepository{}
+}
+
+func (r *MemoryJobRepository) Insert(ctx context.Context, job *models.RerankJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if job.ID.IsZero() {
+		job.ID = bson.NewObjectID()
+	}
+	r.jobs = append(r.jobs, *job)
+	return nil
+}
+
+func (r *MemoryJobRepository) indexOf(jobID string) int {
+	return slices.IndexFunc(r.jobs, func(j models.RerankJob) bool { return j.JobID == jobID })
+}
+
+func (r *MemoryJobRepository) FindByJobID(ctx context.Context, jobID string) (*models.RerankJob, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.indexOf(jobID)
+	if i == -1 {
+		return nil, ErrNotFound
+	}
+	job := r.jobs[i]
+	return &job, nil
+}
+
+func (r *MemoryJobRepository) List(ctx context.Context, limit int64) ([]models.RerankJob, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	jobs := []models.RerankJob{}
+	for i := len(r.jobs) - 1; i >= 0 && int64(len(jobs)) < limit; i-- {
+		jobs = append(jobs, r.jobs[i])
+	}
+	return jobs, nil
+}
+
+func (r *MemoryJobRepository) FindByStatus(ctx context.Context, statuses ...string) ([]models.RerankJob, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	jobs := []models.RerankJob{}
+	for _, job := range r.jobs {
+		if slices.Contains(statuses, job.Status) {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+func (r *MemoryJobRepository) Save(ctx context.Context, job models.RerankJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(job.JobID)
+	if i == -1 {
+		return ErrNotFound
+	}
+	job.ID = r.jobs[i].ID
+	job.CancelRequested = r.jobs[i].CancelRequested
+	job.Diffs = slices.Clone(job.Diffs)
+	job.Errors = slices.Clone(job.Errors)
+	r.jobs[i] = job
+	return nil
+}
+
+func (r *MemoryJobRepository) RequestCancel(ctx context.Context, jobID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(jobID)
+	if i == -1 {
+		return ErrNotFound
+	}
+	r.jobs[i].CancelRequested = true
+	return nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/database"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type MongoJobRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoJobRepository(client *mongo.Client) *MongoJobRepository {
+	return &MongoJobRepository{collection: database.OpenCollection("rerank_jobs", client)}
+}
+
+func (r *MongoJobRepository) Insert(ctx context.Context, job *models.RerankJob) error {
+	result, err := r.collection.InsertOne(ctx, job)
+	if err != nil {
+		return err
+	}
+	if id, ok := result.InsertedID.(bson.ObjectID); ok {
+		job.ID = id
+	}
+	return nil
+}
+
+func (r *MongoJobRepository) FindByJobID(ctx context.Context, jobID string) (*models.RerankJob, error) {
+	var job models.RerankJob
+	err := r.collection.FindOne(ctx, bson.M{"job_id": jobID}).Decode(&job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *MongoJobRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptionsBuilder) ([]models.RerankJob, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	jobs := []models.RerankJob{}
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *MongoJobRepository) List(ctx context.Context, limit int64) ([]models.RerankJob, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	return r.find(ctx, bson.M{}, opts)
+}
+
+func (r *MongoJobRepository) FindByStatus(ctx context.Context, statuses ...string) ([]models.RerankJob, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	return r.find(ctx, bson.M{"status": bson.M{"$in": statuses}}, opts)
+}
+
+func (r *MongoJobRepository) Save(ctx context.Context, job models.RerankJob) error {
+	update := bson.M{
+		"$set": bson.M{
+			"status":      job.Status,
+			"total":       job.Total,
+			"processed":   job.Processed,
+			"changed":     job.Changed,
+			"failed":      job.Failed,
+			"checkpoint":  job.Checkpoint,
+			"diffs":       job.Diffs,
+			"errors":      job.Errors,
+			"error":       job.Error,
+			"updated_at":  job.UpdatedAt,
+			"finished_at": job.FinishedAt,
+		},
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"job_id": job.JobID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoJobRepository) RequestCancel(ctx context.Context, jobID string) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"job_id": jobID}, bson.M{"$set": bson.M{"cancel_requested": true}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
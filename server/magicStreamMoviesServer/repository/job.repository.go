@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+type JobRepository interface {
+	Insert(ctx context.Context, job *models.RerankJob) error
+	FindByJobID(ctx context.Context, jobID string) (*models.RerankJob, error)
+	// List returns the newest jobs first
+	List(ctx context.Context, limit int64) ([]models.RerankJob, error)
+	FindByStatus(ctx context.Context, statuses ...string) ([]models.RerankJob, error)
+	// Save stores the progress of a job (everything but cancel_requested)
+	Save(ctx context.Context, job models.RerankJob) error
+	RequestCancel(ctx context.Context, jobID string) error
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+type MemoryLoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]models.LoginAttempt
+}
+
+func NewMemoryLoginAttemptRepository() *MemoryLoginAttemptRepository {
+	return &MemoryLoginAttemptRepository{attempts: map[string]models.LoginAttempt{}}
+}
+
+func (r *MemoryLoginAttemptRepository) Find(ctx context.Context, key string, now time.Time) (*models.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok || !attempt.ExpiresAt.After(now) {
+		return nil, ErrNotFound
+	}
+	return &attempt, nil
+}
+
+func (r *MemoryLoginAttemptRepository) RecordFailure(ctx context.Context, kind, subject string, seen int, at, expiresAt time.Time) (*models.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := LoginAttemptKey(kind, subject)
+	attempt, ok := r.attempts[key]
+	if !ok || !attempt.ExpiresAt.After(at) {
+		attempt = models.LoginAttempt{Key: key, Kind: kind, Subject: subject, FirstFailureAt: at}
+	}
+	if seen >= 0 && attempt.Failures != seen {
+		return nil, ErrConflict
+	}
+	attempt.Failures++
+	attempt.LastFailureAt = at
+	attempt.ExpiresAt = expiresAt
+	r.attempts[key] = attempt
+	return &attempt, nil
+}
+
+func (r *MemoryLoginAttemptRepository) Release(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if attempt, ok := r.attempts[key]; ok && attempt.Failures > 0 {
+		attempt.Failures--
+		r.attempts[key] = attempt
+	}
+	return nil
+}
+
+func (r *MemoryLoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if attempt, ok := r.attempts[key]; ok {
+		attempt.LockedUntil = &until
+		r.attempts[key] = attempt
+	}
+	return nil
+}
+
+func (r *MemoryLoginAttemptRepository) Clear(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+	return nil
+}
+
+func (r *MemoryLoginAttemptRepository) ListLocked(ctx context.Context, now time.Time) ([]models.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempts := []models.LoginAttempt{}
+	for _, attempt := range r.attempts {
+		if attempt.LockedUntil != nil && attempt.LockedUntil.After(now) {
+			attempts = append(attempts, attempt)
+		}
+	}
+	sort.Slice(attempts, func(i, j int) bool { return attempts[i].LockedUntil.After(*attempts[j].LockedUntil) })
+	return attempts, nil
+}
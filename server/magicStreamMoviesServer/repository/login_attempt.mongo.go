@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/database"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type MongoLoginAttemptRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoLoginAttemptRepository(client *mongo.Client) *MongoLoginAttemptRepository {
+	return &MongoLoginAttemptRepository{collection: database.OpenCollection("login_attempts", client)}
+}
+
+func (r *MongoLoginAttemptRepository) Find(ctx context.Context, key string, now time.Time) (*models.LoginAttempt, error) {
+	var attempt models.LoginAttempt
+	// The TTL-monitor only runs once a minute
+	err := r.collection.FindOne(ctx, bson.M{"_id": key, "expires_at": bson.M{"$gt": now}}).Decode(&attempt)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (r *MongoLoginAttemptRepository) RecordFailure(ctx context.Context, kind, subject string, seen int, at, expiresAt time.Time) (*models.LoginAttempt, error) {
+	key := LoginAttemptKey(kind, subject)
+	expired := bson.D{{Key: "$lte", Value: bson.A{bson.D{{Key: "$ifNull", Value: bson.A{"$expires_at", at}}}, at}}}
+
+	// One atomic pipeline-update: an expired (or missing) counter starts over at 1
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{
+			{Key: "kind", Value: kind},
+			{Key: "subject", Value: subject},
+			{Key: "failures", Value: bson.D{{Key: "$cond", Value: bson.A{expired, 1, bson.D{{Key: "$add", Value: bson.A{"$failures", 1}}}}}}},
+			{Key: "first_failure_at", Value: bson.D{{Key: "$cond", Value: bson.A{expired, at, "$first_failure_at"}}}},
+			{Key: "locked_until", Value: bson.D{{Key: "$cond", Value: bson.A{expired, "$$REMOVE", "$locked_until"}}}},
+			{Key: "last_failure_at", Value: at},
+			{Key: "expires_at", Value: expiresAt},
+		}}},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	// Compare-and-swap: no failures yet is a missing or expired counter (upserted, a concurrent insert is a duplicate key),
+	// otherwise the live counter has to be at exactly seen
+	filter := bson.M{"_id": key}
+	switch {
+	case seen == 0:
+		filter["expires_at"] = bson.M{"$lte": at}
+	case seen > 0:
+		filter["failures"] = seen
+		filter["expires_at"] = bson.M{"$gt": at}
+		opts.SetUpsert(false)
+	}
+
+	var attempt models.LoginAttempt
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&attempt)
+	if seen >= 0 && (errors.Is(err, mongo.ErrNoDocuments) || mongo.IsDuplicateKeyError(err)) {
+		return nil, ErrConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (r *MongoLoginAttemptRepository) Release(ctx context.Context, key string) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": key, "failures": bson.M{"$gt": 0}}, bson.M{"$inc": bson.M{"failures": -1}})
+	return err
+}
+
+func (r *MongoLoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": key}, bson.M{"$set": bson.M{"locked_until": until}})
+	return err
+}
+
+func (r *MongoLoginAttemptRepository) Clear(ctx context.Context, key string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
+
+func (r *MongoLoginAttemptRepository) ListLocked(ctx context.Context, now time.Time) ([]models.LoginAttempt, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "locked_until", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"locked_until": bson.M{"$gt": now}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	attempts := []models.LoginAttempt{}
+	if err := cursor.All(ctx, &attempts); err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+// LoginAttemptRepository keeps the failed-login counters (key = <kind>:<subject>)
+type LoginAttemptRepository interface {
+	// Find returns ErrNotFound when there are no (unexpired) failures
+	Find(ctx context.Context, key string, now time.Time) (*models.LoginAttempt, error)
+	// RecordFailure counts a failure (starting over once the counter expired) and returns the new state.
+	// With seen >= 0 it only counts while the counter is still at seen failures (compare-and-swap), ErrConflict otherwise
+	RecordFailure(ctx context.Context, kind, subject string, seen int, at, expiresAt time.Time) (*models.LoginAttempt, error)
+	// Release takes back one failure (an attempt counted in advance that didn't fail)
+	Release(ctx context.Context, key string) error
+	Lock(ctx context.Context, key string, until time.Time) error
+	Clear(ctx context.Context, key string) error
+	// ListLocked returns the currently locked accounts/IPs
+	ListLocked(ctx context.Context, now time.Time) ([]models.LoginAttempt, error)
+}
+
+func LoginAttemptKey(kind, subject string) string {
+	return kind + ":" + subject
+}
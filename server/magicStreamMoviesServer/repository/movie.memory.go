@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type MemoryMovieRepository struct {
+	mu     sync.RWMutex
+	movies []models.Movie
+}
+
+func NewMemoryMovieRepository() *MemoryMovieRepository {
+	return &MemoryMovieRepository{}
+}
+
+func (r *MemoryMovieRepository) seed(movies []models.Movie) {
+	for _, movie := range movies {
+		_ = r.Insert(context.Background(), &movie)
+	}
+}
+
+func (r *MemoryMovieRepository) matches(movie models.Movie, query MovieQuery) bool {
+	if movie.DeletedAt != nil {
+		return false
+	}
+	if len(query.Genres) > 0 && !slices.ContainsFunc(movie.Genre, func(g models.Genre) bool {
+		return slices.Contains(query.Genres, g.GenreName)
+	}) {
+		return false
+	}
+	if query.MinRanking != nil && movie.Ranking.RankingValue < *query.MinRanking {
+		return false
+	}
+	if query.MaxRanking != nil && movie.Ranking.RankingValue > *query.MaxRanking {
+		return false
+	}
+	if query.TitlePrefix != "" && !strings.HasPrefix(strings.ToLower(movie.Title), strings.ToLower(query.TitlePrefix)) {
+		return false
+	}
+	if len(query.TitleWordPrefixes) > 0 && !hasWordPrefix(movie.Title, query.TitleWordPrefixes) {
+		return false
+	}
+	if query.HasAdminReview && movie.AdminReview == "" {
+		return false
+	}
+	if query.ImdbIDAfter != "" && movie.ImdbID <= query.ImdbIDAfter {
+		return false
+	}
+	if len(query.ImdbIDs) > 0 && !slices.Contains(query.ImdbIDs, movie.ImdbID) {
+		return false
+	}
+	if slices.Contains(query.ExcludeImdbIDs, movie.ImdbID) {
+		return false
+	}
+	if query.MinAudienceSentiment > 0 && movie.AudienceSentiment.Count < query.MinAudienceSentiment {
+		return false
+	}
+	return true
+}
+
+func hasWordPrefix(title string, prefixes []string) bool {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return slices.ContainsFunc(words, func(word string) bool {
+		return slices.ContainsFunc(prefixes, func(prefix string) bool {
+			return strings.HasPrefix(word, strings.ToLower(prefix))
+		})
+	})
+}
+
+// Compares a movie against a sort-value (+ _id tie-breaker), same order as Mongo
+func compareMovie(movie models.Movie, sortBy string, value any, id string) int {
+	var c int
+	switch v := value.(type) {
+	case int:
+		c = cmp.Compare(movie.Ranking.RankingValue, v)
+	case string:
+		c = cmp.Compare(SortValue(movie, sortBy).(string), v)
+	}
+	if c != 0 {
+		return c
+	}
+	return cmp.Compare(movie.ID.Hex(), id)
+}
+
+func (r *MemoryMovieRepository) Find(ctx context.Context, query MovieQuery) ([]models.Movie, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sortBy := query.Sort
+	if _, ok := mongoMovieSortFields[sortBy]; !ok {
+		sortBy = SortByTitle
+	}
+	dir := 1
+	if query.Descending {
+		dir = -1
+	}
+
+	movies := []models.Movie{}
+	for _, movie := range r.movies {
+		if !r.matches(movie, query) {
+			continue
+		}
+		if query.After != nil && dir*compareMovie(movie, sortBy, query.After.Value, query.After.ID) <= 0 {
+			continue
+		}
+		movies = append(movies, movie)
+	}
+
+	slices.SortFunc(movies, func(a, b models.Movie) int {
+		return dir * compareMovie(a, sortBy, SortValue(b, sortBy), b.ID.Hex())
+	})
+
+	if query.Limit > 0 && int64(len(movies)) > query.Limit {
+		movies = movies[:query.Limit]
+	}
+	return movies, nil
+}
+
+func (r *MemoryMovieRepository) Count(ctx context.Context, query MovieQuery) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, movie := range r.movies {
+		if r.matches(movie, query) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryMovieRepository) indexOf(imdbID string, includeDeleted bool) int {
+	return slices.IndexFunc(r.movies, func(m models.Movie) bool {
+		return m.ImdbID == imdbID && (includeDeleted || m.DeletedAt == nil)
+	})
+}
+
+func (r *MemoryMovieRepository) FindByImdbID(ctx context.Context, imdbID string) (*models.Movie, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.indexOf(imdbID, false)
+	if i == -1 {
+		return nil, ErrNotFound
+	}
+	movie := r.movies[i]
+	return &movie, nil
+}
+
+func (r *MemoryMovieRepository) ImdbIDExists(ctx context.Context, imdbID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.indexOf(imdbID, true) != -1, nil
+}
+
+func (r *MemoryMovieRepository) Insert(ctx context.Context, movie *models.Movie) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if movie.ID.IsZero() {
+		movie.ID = bson.NewObjectID()
+	}
+	movie.UserRating, movie.AudienceSentiment = models.RatingSummary{}, models.RatingSummary{}
+	r.movies = append(r.movies, *movie)
+	return nil
+}
+
+func (r *MemoryMovieRepository) Replace(ctx context.Context, movie models.Movie) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(movie.ImdbID, false)
+	if i == -1 {
+		return ErrNotFound
+	}
+	movie.ID = r.movies[i].ID
+	movie.DeletedAt = nil
+	movie.UserRating, movie.AudienceSentiment = r.movies[i].UserRating, r.movies[i].AudienceSentiment
+	r.movies[i] = movie
+	return nil
+}
+
+func (r *MemoryMovieRepository) UpdateReview(ctx context.Context, imdbID, adminReview string, ranking models.Ranking) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(imdbID, false)
+	if i == -1 {
+		return ErrNotFound
+	}
+	r.movies[i].AdminReview = adminReview
+	r.movies[i].Ranking = ranking
+	return nil
+}
+
+func (r *MemoryMovieRepository) UpdateRanking(ctx context.Context, imdbID, adminReview string, ranking models.Ranking) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(imdbID, false)
+	if i == -1 || r.movies[i].AdminReview != adminReview {
+		return ErrNotFound
+	}
+	r.movies[i].Ranking = ranking
+	return nil
+}
+
+func (r *MemoryMovieRepository) SoftDelete(ctx context.Context, imdbID string, deletedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(imdbID, false)
+	if i == -1 {
+		return ErrNotFound
+	}
+	r.movies[i].DeletedAt = &deletedAt
+	return nil
+}
+
+// No text-index in memory, callers fall back to the in-process search index
+func (r *MemoryMovieRepository) TextSearch(ctx context.Context, query string, limit int) ([]ScoredMovie, error) {
+	return nil, ErrTextIndexMissing
+}
+
+func (r *MemoryMovieRepository) RenameGenre(ctx context.Context, genreID int, name string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var modified int64
+	for i := range r.movies {
+		if renameGenre(r.movies[i].Genre, genreID, name) {
+			modified++
+		}
+	}
+	return modified, nil
+}
+
+func (r *MemoryMovieRepository) RenameRanking(ctx context.Context, rankingValue int, name string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var modified int64
+	for i := range r.movies {
+		if r.movies[i].Ranking.RankingValue == rankingValue && r.movies[i].Ranking.RankingName != name {
+			r.movies[i].Ranking.RankingName = name
+			modified++
+		}
+	}
+	return modified, nil
+}
+
+// renameGenre renames genreID in place, reporting whether anything changed
+func renameGenre(genres []models.Genre, genreID int, name string) bool {
+	changed := false
+	for i := range genres {
+		if genres[i].GenreID == genreID && genres[i].GenreName != name {
+			genres[i].GenreName = name
+			changed = true
+		}
+	}
+	return changed
+}
+
+func (r *MemoryMovieRepository) UpdateUserRating(ctx context.Context, imdbID string, change RatingChange) error {
+	return r.updateSummary(imdbID, change, func(movie *models.Movie) *models.RatingSummary { return &movie.UserRating })
+}
+
+func (r *MemoryMovieRepository) UpdateAudienceSentiment(ctx context.Context, imdbID string, change RatingChange) error {
+	return r.updateSummary(imdbID, change, func(movie *models.Movie) *models.RatingSummary { return &movie.AudienceSentiment })
+}
+
+// updateSummary applies the change to the RatingSummary field picked by summaryOf
+func (r *MemoryMovieRepository) updateSummary(imdbID string, change RatingChange, summaryOf func(*models.Movie) *models.RatingSummary) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(imdbID, true)
+	if i == -1 {
+		return ErrNotFound
+	}
+
+	count, sum, histogram := change.deltas()
+	summary := summaryOf(&r.movies[i])
+	summary.Count += count
+	summary.Sum += sum
+	// A fresh map, earlier copies of the movie share the old one
+	summary.Histogram = maps.Clone(summary.Histogram)
+	if summary.Histogram == nil {
+		summary.Histogram = map[string]int{}
+	}
+	for value, n := range histogram {
+		summary.Histogram[strconv.Itoa(value)] += n
+	}
+	summary.Average = 0
+	if summary.Count > 0 {
+		summary.Average = math.Round(float64(summary.Sum)/float64(summary.Count)*100) / 100
+	}
+	return nil
+}
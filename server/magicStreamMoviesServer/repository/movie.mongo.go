@@ -0,0 +1,327 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/database"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const indexNotFoundCode = 27 // MongoDB "IndexNotFound"
+
+var mongoMovieSortFields = map[string]string{
+	SortByTitle:   "title",
+	SortByRanking: "ranking.ranking_value",
+	SortByImdbID:  "imdb_id",
+}
+
+type MongoMovieRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoMovieRepository(client *mongo.Client) *MongoMovieRepository {
+	return &MongoMovieRepository{collection: database.OpenCollection("movies", client)}
+}
+
+// Soft-deleted movies keep a tombstone (deleted_at) and are skipped everywhere
+func notDeleted(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": false}
+	return filter
+}
+
+func movieFilter(query MovieQuery) bson.M {
+	filter := notDeleted(bson.M{})
+
+	if len(query.Genres) > 0 {
+		filter["genre.genre_name"] = bson.M{"$in": query.Genres}
+	}
+
+	rankingRange := bson.M{}
+	if query.MinRanking != nil {
+		rankingRange["$gte"] = *query.MinRanking
+	}
+	if query.MaxRanking != nil {
+		rankingRange["$lte"] = *query.MaxRanking
+	}
+	if len(rankingRange) > 0 {
+		filter["ranking.ranking_value"] = rankingRange
+	}
+
+	if query.TitlePrefix != "" {
+		filter["title"] = bson.M{"$regex": "^" + regexp.QuoteMeta(query.TitlePrefix), "$options": "i"}
+	}
+	if len(query.TitleWordPrefixes) > 0 {
+		prefixes := make([]string, len(query.TitleWordPrefixes))
+		for i, prefix := range query.TitleWordPrefixes {
+			prefixes[i] = regexp.QuoteMeta(prefix)
+		}
+		filter["$and"] = bson.A{
+			bson.M{"title": bson.M{"$regex": `(^|[^\p{L}\p{N}])(` + strings.Join(prefixes, "|") + ")", "$options": "i"}},
+		}
+	}
+
+	if query.HasAdminReview {
+		filter["admin_review"] = bson.M{"$nin": bson.A{"", nil}}
+	}
+	imdbIDs := bson.M{}
+	if query.ImdbIDAfter != "" {
+		imdbIDs["$gt"] = query.ImdbIDAfter
+	}
+	if len(query.ImdbIDs) > 0 {
+		imdbIDs["$in"] = query.ImdbIDs
+	}
+	if len(query.ExcludeImdbIDs) > 0 {
+		imdbIDs["$nin"] = query.ExcludeImdbIDs
+	}
+	if len(imdbIDs) > 0 {
+		filter["imdb_id"] = imdbIDs
+	}
+	if query.MinAudienceSentiment > 0 {
+		filter["audience_sentiment.count"] = bson.M{"$gte": query.MinAudienceSentiment}
+	}
+	return filter
+}
+
+func (r *MongoMovieRepository) Find(ctx context.Context, query MovieQuery) ([]models.Movie, error) {
+	filter := movieFilter(query)
+
+	sortField, ok := mongoMovieSortFields[query.Sort]
+	if !ok {
+		sortField = mongoMovieSortFields[SortByTitle]
+	}
+	sortDir := 1
+	if query.Descending {
+		sortDir = -1
+	}
+
+	if query.After != nil {
+		lastID, err := bson.ObjectIDFromHex(query.After.ID)
+		if err != nil {
+			return nil, err
+		}
+		op := "$gt"
+		if query.Descending {
+			op = "$lt"
+		}
+		filter = bson.M{"$and": bson.A{
+			filter,
+			bson.M{"$or": bson.A{
+				bson.M{sortField: bson.M{op: query.After.Value}},
+				bson.M{sortField: query.After.Value, "_id": bson.M{op: lastID}},
+			}},
+		}}
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: sortField, Value: sortDir}, {Key: "_id", Value: sortDir}})
+	if query.Limit > 0 {
+		findOptions.SetLimit(query.Limit)
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	movies := []models.Movie{}
+	if err := cursor.All(ctx, &movies); err != nil {
+		return nil, err
+	}
+	return movies, nil
+}
+
+func (r *MongoMovieRepository) Count(ctx context.Context, query MovieQuery) (int64, error) {
+	return r.collection.CountDocuments(ctx, movieFilter(query))
+}
+
+func (r *MongoMovieRepository) FindByImdbID(ctx context.Context, imdbID string) (*models.Movie, error) {
+	var movie models.Movie
+	err := r.collection.FindOne(ctx, notDeleted(bson.M{"imdb_id": imdbID})).Decode(&movie)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &movie, nil
+}
+
+func (r *MongoMovieRepository) ImdbIDExists(ctx context.Context, imdbID string) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"imdb_id": imdbID})
+	return count > 0, err
+}
+
+func (r *MongoMovieRepository) Insert(ctx context.Context, movie *models.Movie) error {
+	movie.UserRating, movie.AudienceSentiment = models.RatingSummary{}, models.RatingSummary{}
+	result, err := r.collection.InsertOne(ctx, movie)
+	if err != nil {
+		return err
+	}
+	if id, ok := result.InsertedID.(bson.ObjectID); ok {
+		movie.ID = id
+	}
+	return nil
+}
+
+func (r *MongoMovieRepository) Replace(ctx context.Context, movie models.Movie) error {
+	movie.DeletedAt = nil
+
+	// Every field but _id, user_rating & audience_sentiment (the reviews keep those up to date, concurrently)
+	fields := bson.M{}
+	raw, err := bson.Marshal(movie)
+	if err == nil {
+		err = bson.Unmarshal(raw, &fields)
+	}
+	if err != nil {
+		return err
+	}
+	delete(fields, "_id")
+	delete(fields, "user_rating")
+	delete(fields, "audience_sentiment")
+
+	result, err := r.collection.UpdateOne(ctx, notDeleted(bson.M{"imdb_id": movie.ImdbID}), bson.M{"$set": fields})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoMovieRepository) UpdateReview(ctx context.Context, imdbID, adminReview string, ranking models.Ranking) error {
+	update := bson.M{
+		"$set": bson.M{
+			"admin_review": adminReview,
+			"ranking":      ranking,
+		},
+	}
+	result, err := r.collection.UpdateOne(ctx, notDeleted(bson.M{"imdb_id": imdbID}), update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoMovieRepository) UpdateRanking(ctx context.Context, imdbID, adminReview string, ranking models.Ranking) error {
+	filter := notDeleted(bson.M{"imdb_id": imdbID, "admin_review": adminReview})
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"ranking": ranking}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoMovieRepository) SoftDelete(ctx context.Context, imdbID string, deletedAt time.Time) error {
+	update := bson.M{"$set": bson.M{"deleted_at": deletedAt}}
+	result, err := r.collection.UpdateOne(ctx, notDeleted(bson.M{"imdb_id": imdbID}), update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoMovieRepository) TextSearch(ctx context.Context, query string, limit int) ([]ScoredMovie, error) {
+	filter := notDeleted(bson.M{"$text": bson.M{"$search": query}})
+	findOptions := options.Find().
+		SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		var serverErr mongo.ServerError
+		if errors.As(err, &serverErr) && serverErr.HasErrorCode(indexNotFoundCode) {
+			return nil, ErrTextIndexMissing
+		}
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		models.Movie `bson:",inline"`
+		Score        float64 `bson:"score"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	results := make([]ScoredMovie, 0, len(docs))
+	for _, doc := range docs {
+		results = append(results, ScoredMovie{Movie: doc.Movie, Score: doc.Score})
+	}
+	return results, nil
+}
+
+func (r *MongoMovieRepository) RenameGenre(ctx context.Context, genreID int, name string) (int64, error) {
+	opts := options.UpdateMany().SetArrayFilters([]any{bson.M{"g.genre_id": genreID}})
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"genre.genre_id": genreID},
+		bson.M{"$set": bson.M{"genre.$[g].genre_name": name}},
+		opts)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *MongoMovieRepository) RenameRanking(ctx context.Context, rankingValue int, name string) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"ranking.ranking_value": rankingValue},
+		bson.M{"$set": bson.M{"ranking.ranking_name": name}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *MongoMovieRepository) UpdateUserRating(ctx context.Context, imdbID string, change RatingChange) error {
+	return r.updateSummary(ctx, imdbID, "user_rating", change)
+}
+
+func (r *MongoMovieRepository) UpdateAudienceSentiment(ctx context.Context, imdbID string, change RatingChange) error {
+	return r.updateSummary(ctx, imdbID, "audience_sentiment", change)
+}
+
+// updateSummary applies the change to a RatingSummary field
+func (r *MongoMovieRepository) updateSummary(ctx context.Context, imdbID, field string, change RatingChange) error {
+	count, sum, histogram := change.deltas()
+	inc := bson.M{field + ".count": count, field + ".sum": sum}
+	for value, n := range histogram {
+		inc[fmt.Sprintf("%s.histogram.%d", field, value)] = n
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"imdb_id": imdbID}, bson.M{"$inc": inc})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+
+	// The average follows from the (already updated) sum & count, so a concurrent change can't leave it stale
+	average := bson.A{bson.M{"$set": bson.M{field + ".average": bson.M{"$cond": bson.A{
+		bson.M{"$gt": bson.A{"$" + field + ".count", 0}},
+		bson.M{"$round": bson.A{bson.M{"$divide": bson.A{"$" + field + ".sum", "$" + field + ".count"}}, 2}},
+		0,
+	}}}}}
+	_, err = r.collection.UpdateOne(ctx, bson.M{"imdb_id": imdbID}, average)
+	return err
+}
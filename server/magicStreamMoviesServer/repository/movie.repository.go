@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+// Sort-fields of a MovieQuery
+const (
+	SortByTitle   = "title"
+	SortByRanking = "ranking"
+	SortByImdbID  = "imdb_id"
+)
+
+// MovieCursor is the position after which the next page starts (sort-value + _id tie-breaker)
+type MovieCursor struct {
+	Value any
+	ID    string
+}
+
+// MovieQuery filters, sorts & pages (non-deleted) movies. Zero-values mean "no filter"
+type MovieQuery struct {
+	Genres      []string
+	MinRanking  *int
+	MaxRanking  *int
+	TitlePrefix string
+	// TitleWordPrefixes only keeps movies with a title word starting with one of these (case-insensitive)
+	TitleWordPrefixes []string
+	// HasAdminReview only keeps movies with a (non-empty) admin_review
+	HasAdminReview bool
+	// ImdbIDAfter only keeps imdb_id > ImdbIDAfter (batch-iteration, sorted by imdb_id)
+	ImdbIDAfter string
+	// ImdbIDs only keeps these movies (e.g. a watchlist)
+	ImdbIDs []string
+	// ExcludeImdbIDs leaves these movies out (e.g. the ones a user saw already)
+	ExcludeImdbIDs []string
+	// MinAudienceSentiment only keeps movies with at least that many AI-ranked user-reviews
+	MinAudienceSentiment int
+
+	Sort       string
+	Descending bool
+	After      *MovieCursor
+	Limit      int64 // 0 = unbounded
+}
+
+// RatingChange is what one user-review change does to a movie's user_rating (or audience_sentiment).
+// Added/Removed are ratings or ranking_values (0 = none): a new review only adds, an edit removes the old & adds the new one
+type RatingChange struct {
+	Added   int
+	Removed int
+}
+
+// deltas of count, sum & histogram (rating -> n, only the ratings that change)
+func (c RatingChange) deltas() (count, sum int, histogram map[int]int) {
+	histogram = map[int]int{}
+	if c.Added != 0 {
+		count, sum = count+1, sum+c.Added
+		histogram[c.Added]++
+	}
+	if c.Removed != 0 {
+		count, sum = count-1, sum-c.Removed
+		histogram[c.Removed]--
+	}
+	for rating, n := range histogram {
+		if n == 0 {
+			delete(histogram, rating)
+		}
+	}
+	return count, sum, histogram
+}
+
+// ScoredMovie is a text-search hit
+type ScoredMovie struct {
+	Movie models.Movie
+	Score float64
+}
+
+type MovieRepository interface {
+	Find(ctx context.Context, query MovieQuery) ([]models.Movie, error)
+	// Count ignores Sort, After & Limit
+	Count(ctx context.Context, query MovieQuery) (int64, error)
+	// FindByImdbID skips deleted movies (ErrNotFound)
+	FindByImdbID(ctx context.Context, imdbID string) (*models.Movie, error)
+	// ImdbIDExists also looks at the tombstones
+	ImdbIDExists(ctx context.Context, imdbID string) (bool, error)
+	Insert(ctx context.Context, movie *models.Movie) error
+	// Replace overwrites a non-deleted movie, matched by imdb_id (ErrNotFound)
+	Replace(ctx context.Context, movie models.Movie) error
+	UpdateReview(ctx context.Context, imdbID, adminReview string, ranking models.Ranking) error
+	// UpdateRanking only matches while the admin_review is still the ranked one (ErrNotFound otherwise)
+	UpdateRanking(ctx context.Context, imdbID, adminReview string, ranking models.Ranking) error
+	SoftDelete(ctx context.Context, imdbID string, deletedAt time.Time) error
+	// UpdateUserRating applies a RatingChange to user_rating (count, sum, histogram & average), incrementally.
+	// user_rating is left alone by Insert & Replace
+	UpdateUserRating(ctx context.Context, imdbID string, change RatingChange) error
+	// UpdateAudienceSentiment is the same for audience_sentiment (by ranking_value)
+	UpdateAudienceSentiment(ctx context.Context, imdbID string, change RatingChange) error
+	// TextSearch ranks by relevance, ErrTextIndexMissing when the store can't do it
+	TextSearch(ctx context.Context, query string, limit int) ([]ScoredMovie, error)
+	// RenameGenre & RenameRanking cascade a catalog rename into the embedded copies (tombstones included)
+	RenameGenre(ctx context.Context, genreID int, name string) (int64, error)
+	RenameRanking(ctx context.Context, rankingValue int, name string) (int64, error)
+}
+
+// SortValue reads the value a movie is sorted on (for the next-page cursor)
+func SortValue(movie models.Movie, sortBy string) any {
+	switch sortBy {
+	case SortByRanking:
+		return movie.Ranking.RankingValue
+	case SortByImdbID:
+		return movie.ImdbID
+	default:
+		return movie.Title
+	}
+}
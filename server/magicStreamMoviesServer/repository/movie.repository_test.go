@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestRatingChangeDeltas(t *testing.T) {
+	for name, tc := range map[string]struct {
+		change    RatingChange
+		count     int
+		sum       int
+		histogram map[int]int
+	}{
+		"new review":     {RatingChange{Added: 8}, 1, 8, map[int]int{8: 1}},
+		"deleted review": {RatingChange{Removed: 3}, -1, -3, map[int]int{3: -1}},
+		"edited rating":  {RatingChange{Added: 9, Removed: 4}, 0, 5, map[int]int{9: 1, 4: -1}},
+		"same rating":    {RatingChange{Added: 7, Removed: 7}, 0, 0, map[int]int{}},
+		"no rating":      {RatingChange{}, 0, 0, map[int]int{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			count, sum, histogram := tc.change.deltas()
+			if count != tc.count || sum != tc.sum || !maps.Equal(histogram, tc.histogram) {
+				t.Fatalf("got (%d, %d, %v), want (%d, %d, %v)", count, sum, histogram, tc.count, tc.sum, tc.histogram)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+type MemoryRankingRepository struct {
+	mu      sync.RWMutex
+	entries []models.RankingEntry
+}
+
+func NewMemoryRankingRepository() *MemoryRankingRepository {
+	return &MemoryRankingRepository{}
+}
+
+func (r *MemoryRankingRepository) seed(entries []models.RankingEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entries...)
+}
+
+func (r *MemoryRankingRepository) List(ctx context.Context) ([]models.Ranking, error) {
+	entries, _ := r.ListEntries(ctx, false)
+	return activeRankings(entries), nil
+}
+
+func (r *MemoryRankingRepository) ListEntries(ctx context.Context, includeRetired bool) ([]models.RankingEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := []models.RankingEntry{}
+	for _, entry := range r.entries {
+		if includeRetired || !entry.Retired {
+			entries = append(entries, entry)
+		}
+	}
+	slices.SortFunc(entries, func(a, b models.RankingEntry) int {
+		return cmp.Or(cmp.Compare(a.SortOrder, b.SortOrder), cmp.Compare(a.RankingValue, b.RankingValue))
+	})
+	return entries, nil
+}
+
+func (r *MemoryRankingRepository) indexOf(rankingValue int) int {
+	return slices.IndexFunc(r.entries, func(e models.RankingEntry) bool { return e.RankingValue == rankingValue })
+}
+
+func (r *MemoryRankingRepository) nameTaken(name string, exceptID int) bool {
+	return slices.ContainsFunc(r.entries, func(e models.RankingEntry) bool {
+		return e.RankingValue != exceptID && strings.EqualFold(e.RankingName, name)
+	})
+}
+
+func (r *MemoryRankingRepository) FindByValue(ctx context.Context, rankingValue int) (*models.RankingEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.indexOf(rankingValue)
+	if i == -1 {
+		return nil, ErrNotFound
+	}
+	entry := r.entries[i]
+	return &entry, nil
+}
+
+func (r *MemoryRankingRepository) Insert(ctx context.Context, entry *models.RankingEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.indexOf(entry.RankingValue) != -1 || r.nameTaken(entry.RankingName, entry.RankingValue) {
+		return ErrDuplicate
+	}
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *MemoryRankingRepository) Rename(ctx context.Context, rankingValue int, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(rankingValue)
+	if i == -1 {
+		return ErrNotFound
+	}
+	if r.nameTaken(name, rankingValue) {
+		return ErrDuplicate
+	}
+	r.entries[i].RankingName = name
+	return nil
+}
+
+func (r *MemoryRankingRepository) Reorder(ctx context.Context, rankingValues []int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for position, rankingValue := range rankingValues {
+		if i := r.indexOf(rankingValue); i != -1 {
+			r.entries[i].SortOrder = position + 1
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRankingRepository) SetRetired(ctx context.Context, rankingValue int, retired bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(rankingValue)
+	if i == -1 {
+		return ErrNotFound
+	}
+	r.entries[i].Retired = retired
+	return nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/database"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type MongoRankingRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoRankingRepository(client *mongo.Client) *MongoRankingRepository {
+	return &MongoRankingRepository{collection: database.OpenCollection("rankings", client)}
+}
+
+func (r *MongoRankingRepository) List(ctx context.Context) ([]models.Ranking, error) {
+	entries, err := r.ListEntries(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	return activeRankings(entries), nil
+}
+
+func (r *MongoRankingRepository) ListEntries(ctx context.Context, includeRetired bool) ([]models.RankingEntry, error) {
+	filter := bson.M{}
+	if !includeRetired {
+		filter["retired"] = bson.M{"$ne": true}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "sort_order", Value: 1}, {Key: "ranking_value", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []models.RankingEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *MongoRankingRepository) FindByValue(ctx context.Context, rankingValue int) (*models.RankingEntry, error) {
+	var entry models.RankingEntry
+	err := r.collection.FindOne(ctx, bson.M{"ranking_value": rankingValue}).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *MongoRankingRepository) Insert(ctx context.Context, entry *models.RankingEntry) error {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"$or": bson.A{
+		bson.M{"ranking_value": entry.RankingValue},
+		nameFilter("ranking_name", entry.RankingName),
+	}})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrDuplicate
+	}
+
+	_, err = r.collection.InsertOne(ctx, entry)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *MongoRankingRepository) Rename(ctx context.Context, rankingValue int, name string) error {
+	filter := nameFilter("ranking_name", name)
+	filter["ranking_value"] = bson.M{"$ne": rankingValue}
+	count, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrDuplicate
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"ranking_value": rankingValue}, bson.M{"$set": bson.M{"ranking_name": name}})
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoRankingRepository) Reorder(ctx context.Context, rankingValues []int) error {
+	writes := make([]mongo.WriteModel, 0, len(rankingValues))
+	for i, rankingValue := range rankingValues {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"ranking_value": rankingValue}).
+			SetUpdate(bson.M{"$set": bson.M{"sort_order": i + 1}}))
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+	_, err := r.collection.BulkWrite(ctx, writes)
+	return err
+}
+
+func (r *MongoRankingRepository) SetRetired(ctx context.Context, rankingValue int, retired bool) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"ranking_value": rankingValue}, bson.M{"$set": bson.M{"retired": retired}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
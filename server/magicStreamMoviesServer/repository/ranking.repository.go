@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+type RankingRepository interface {
+	// List returns the active rankings, ordered by sort_order (then ranking_value)
+	List(ctx context.Context) ([]models.Ranking, error)
+	ListEntries(ctx context.Context, includeRetired bool) ([]models.RankingEntry, error)
+	FindByValue(ctx context.Context, rankingValue int) (*models.RankingEntry, error)
+	// Insert fails with ErrDuplicate on a taken ranking_value or ranking_name
+	Insert(ctx context.Context, entry *models.RankingEntry) error
+	Rename(ctx context.Context, rankingValue int, name string) error
+	// Reorder sets sort_order to the position in rankingValues
+	Reorder(ctx context.Context, rankingValues []int) error
+	SetRetired(ctx context.Context, rankingValue int, retired bool) error
+}
+
+func activeRankings(entries []models.RankingEntry) []models.Ranking {
+	rankings := []models.Ranking{}
+	for _, entry := range entries {
+		if !entry.Retired {
+			rankings = append(rankings, entry.Ranking)
+		}
+	}
+	return rankings
+}
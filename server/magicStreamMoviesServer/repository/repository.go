@@ -0,0 +1,105 @@
+// Package repository hides the data-store behind small interfaces 🗄️
+// Every repository has a MongoDB implementation and an in-memory one (dev, CI & tests).
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrDuplicate        = errors.New("already exists")
+	ErrTextIndexMissing = errors.New("text index missing")
+	ErrConflict         = errors.New("changed concurrently")
+)
+
+// Store bundles all repositories, so they can be passed around (routes) as one
+type Store struct {
+	Movies        MovieRepository
+	Users         UserRepository
+	Genres        GenreRepository
+	Rankings      RankingRepository
+	Jobs          JobRepository
+	History       ReviewHistoryRepository
+	Sessions      SessionRepository
+	Revocations   RevocationRepository
+	APIKeys       APIKeyRepository
+	ActionTokens  ActionTokenRepository
+	LoginAttempts LoginAttemptRepository
+	Audit         AuditRepository
+	Watchlist     WatchlistRepository
+	UserReviews   UserReviewRepository
+	WatchHistory  WatchHistoryRepository
+}
+
+func NewMongoStore(client *mongo.Client) *Store {
+	return &Store{
+		Movies:        NewMongoMovieRepository(client),
+		Users:         NewMongoUserRepository(client),
+		Genres:        NewMongoGenreRepository(client),
+		Rankings:      NewMongoRankingRepository(client),
+		Jobs:          NewMongoJobRepository(client),
+		History:       NewMongoReviewHistoryRepository(client),
+		Sessions:      NewMongoSessionRepository(client),
+		Revocations:   NewMongoRevocationRepository(client),
+		APIKeys:       NewMongoAPIKeyRepository(client),
+		ActionTokens:  NewMongoActionTokenRepository(client),
+		LoginAttempts: NewMongoLoginAttemptRepository(client),
+		Audit:         NewMongoAuditRepository(client),
+		Watchlist:     NewMongoWatchlistRepository(client),
+		UserReviews:   NewMongoUserReviewRepository(client),
+		WatchHistory:  NewMongoWatchHistoryRepository(client),
+	}
+}
+
+func NewMemoryStore() *Store {
+	return &Store{
+		Movies:        NewMemoryMovieRepository(),
+		Users:         NewMemoryUserRepository(),
+		Genres:        NewMemoryGenreRepository(),
+		Rankings:      NewMemoryRankingRepository(),
+		Jobs:          NewMemoryJobRepository(),
+		History:       NewMemoryReviewHistoryRepository(),
+		Sessions:      NewMemorySessionRepository(),
+		Revocations:   NewMemoryRevocationRepository(),
+		APIKeys:       NewMemoryAPIKeyRepository(),
+		ActionTokens:  NewMemoryActionTokenRepository(),
+		LoginAttempts: NewMemoryLoginAttemptRepository(),
+		Audit:         NewMemoryAuditRepository(),
+		Watchlist:     NewMemoryWatchlistRepository(),
+		UserReviews:   NewMemoryUserReviewRepository(),
+		WatchHistory:  NewMemoryWatchHistoryRepository(),
+	}
+}
+
+// Seed-file for the in-memory store (same shape as the Mongo collections)
+type seedData struct {
+	Movies   []models.Movie        `json:"movies"`
+	Genres   []models.GenreEntry   `json:"genres"`
+	Rankings []models.RankingEntry `json:"rankings"`
+}
+
+// NewSeededMemoryStore creates an in-memory store, filled from a JSON seed-file
+func NewSeededMemoryStore(path string) (*Store, error) {
+	store := NewMemoryStore()
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var seed seedData
+	if err := json.Unmarshal(raw, &seed); err != nil {
+		return nil, err
+	}
+
+	store.Movies.(*MemoryMovieRepository).seed(seed.Movies)
+	store.Genres.(*MemoryGenreRepository).seed(seed.Genres)
+	store.Rankings.(*MemoryRankingRepository).seed(seed.Rankings)
+	return store, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type MemoryReviewHistoryRepository struct {
+	mu      sync.RWMutex
+	entries []models.ReviewHistory
+}
+
+func NewMemoryReviewHistoryRepository() *MemoryReviewHistoryRepository {
+	return &MemoryReviewHistoryRepository{}
+}
+
+func (r *MemoryReviewHistoryRepository) Append(ctx context.Context, entry *models.ReviewHistory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry.ID.IsZero() {
+		entry.ID = bson.NewObjectID()
+	}
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *MemoryReviewHistoryRepository) ListByMovie(ctx context.Context, imdbID string, limit int64) ([]models.ReviewHistory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := []models.ReviewHistory{}
+	for i := len(r.entries) - 1; i >= 0 && int64(len(entries)) < limit; i-- {
+		if r.entries[i].ImdbID == imdbID {
+			entries = append(entries, r.entries[i])
+		}
+	}
+	return entries, nil
+}
+
+func (r *MemoryReviewHistoryRepository) FindByHistoryID(ctx context.Context, historyID string) (*models.ReviewHistory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := slices.IndexFunc(r.entries, func(e models.ReviewHistory) bool { return e.HistoryID == historyID })
+	if i == -1 {
+		return nil, ErrNotFound
+	}
+	entry := r.entries[i]
+	return &entry, nil
+}
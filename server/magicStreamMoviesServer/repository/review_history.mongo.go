@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/database"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type MongoReviewHistoryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoReviewHistoryRepository(client *mongo.Client) *MongoReviewHistoryRepository {
+	return &MongoReviewHistoryRepository{collection: database.OpenCollection("review_history", client)}
+}
+
+func (r *MongoReviewHistoryRepository) Append(ctx context.Context, entry *models.ReviewHistory) error {
+	result, err := r.collection.InsertOne(ctx, entry)
+	if err != nil {
+		return err
+	}
+	if id, ok := result.InsertedID.(bson.ObjectID); ok {
+		entry.ID = id
+	}
+	return nil
+}
+
+func (r *MongoReviewHistoryRepository) ListByMovie(ctx context.Context, imdbID string, limit int64) ([]models.ReviewHistory, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, bson.M{"imdb_id": imdbID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []models.ReviewHistory{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *MongoReviewHistoryRepository) FindByHistoryID(ctx context.Context, historyID string) (*models.ReviewHistory, error) {
+	var entry models.ReviewHistory
+	err := r.collection.FindOne(ctx, bson.M{"history_id": historyID}).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+// ReviewHistoryRepository is append-only, entries are never updated or deleted
+type ReviewHistoryRepository interface {
+	Append(ctx context.Context, entry *models.ReviewHistory) error
+	// ListByMovie returns the newest entries first
+	ListByMovie(ctx context.Context, imdbID string, limit int64) ([]models.ReviewHistory, error)
+	FindByHistoryID(ctx context.Context, historyID string) (*models.ReviewHistory, error)
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type MemoryRevocationRepository struct {
+	mu          sync.RWMutex
+	revocations []models.Revocation
+}
+
+func NewMemoryRevocationRepository() *MemoryRevocationRepository {
+	return &MemoryRevocationRepository{}
+}
+
+func (r *MemoryRevocationRepository) Insert(ctx context.Context, revocation *models.Revocation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if revocation.ID.IsZero() {
+		revocation.ID = bson.NewObjectID()
+	}
+	r.revocations = append(r.revocations, *revocation)
+	return nil
+}
+
+func (r *MemoryRevocationRepository) ListActive(ctx context.Context, now time.Time) ([]models.Revocation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revocations := []models.Revocation{}
+	for _, revocation := range r.revocations {
+		if revocation.ExpiresAt.After(now) {
+			revocations = append(revocations, revocation)
+		}
+	}
+	return revocations, nil
+}
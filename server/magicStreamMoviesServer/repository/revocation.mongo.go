@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/database"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type MongoRevocationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoRevocationRepository(client *mongo.Client) *MongoRevocationRepository {
+	return &MongoRevocationRepository{collection: database.OpenCollection("revocations", client)}
+}
+
+func (r *MongoRevocationRepository) Insert(ctx context.Context, revocation *models.Revocation) error {
+	result, err := r.collection.InsertOne(ctx, revocation)
+	if err != nil {
+		return err
+	}
+	if id, ok := result.InsertedID.(bson.ObjectID); ok {
+		revocation.ID = id
+	}
+	return nil
+}
+
+func (r *MongoRevocationRepository) ListActive(ctx context.Context, now time.Time) ([]models.Revocation, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"expires_at": bson.M{"$gt": now}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	revocations := []models.Revocation{}
+	if err := cursor.All(ctx, &revocations); err != nil {
+		return nil, err
+	}
+	return revocations, nil
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+type RevocationRepository interface {
+	Insert(ctx context.Context, revocation *models.Revocation) error
+	// ListActive returns the entries that haven't expired at now
+	ListActive(ctx context.Context, now time.Time) ([]models.Revocation, error)
+}
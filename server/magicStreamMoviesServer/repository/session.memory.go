@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type MemorySessionRepository struct {
+	mu       sync.RWMutex
+	sessions []models.Session
+}
+
+func NewMemorySessionRepository() *MemorySessionRepository {
+	return &MemorySessionRepository{}
+}
+
+func isActiveSession(session models.Session, now time.Time) bool {
+	return session.RevokedAt == nil && session.ExpiresAt.After(now)
+}
+
+func (r *MemorySessionRepository) indexOf(sessionID string) int {
+	return slices.IndexFunc(r.sessions, func(s models.Session) bool { return s.SessionID == sessionID })
+}
+
+func (r *MemorySessionRepository) Insert(ctx context.Context, session *models.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.indexOf(session.SessionID) != -1 {
+		return ErrDuplicate
+	}
+	if session.ID.IsZero() {
+		session.ID = bson.NewObjectID()
+	}
+	r.sessions = append(r.sessions, *session)
+	return nil
+}
+
+func (r *MemorySessionRepository) FindBySessionID(ctx context.Context, sessionID string) (*models.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.indexOf(sessionID)
+	if i == -1 {
+		return nil, ErrNotFound
+	}
+	session := r.sessions[i]
+	return &session, nil
+}
+
+func (r *MemorySessionRepository) ListActiveByUser(ctx context.Context, userID string, now time.Time) ([]models.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sessions := []models.Session{}
+	for _, session := range r.sessions {
+		if session.UserID == userID && isActiveSession(session, now) {
+			sessions = append(sessions, session)
+		}
+	}
+	slices.SortStableFunc(sessions, func(a, b models.Session) int {
+		return b.LastUsedAt.Compare(a.LastUsedAt)
+	})
+	return sessions, nil
+}
+
+func (r *MemorySessionRepository) Rotate(ctx context.Context, sessionID, oldTokenID, newTokenID string, usedAt, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(sessionID)
+	if i == -1 || !isActiveSession(r.sessions[i], usedAt) || r.sessions[i].RefreshTokenID != oldTokenID {
+		return ErrNotFound
+	}
+	r.sessions[i].RefreshTokenID = newTokenID
+	r.sessions[i].LastUsedAt = usedAt
+	r.sessions[i].ExpiresAt = expiresAt
+	return nil
+}
+
+func (r *MemorySessionRepository) Revoke(ctx context.Context, sessionID, reason string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(sessionID)
+	if i == -1 || r.sessions[i].RevokedAt != nil {
+		return ErrNotFound
+	}
+	r.sessions[i].RevokedAt = &at
+	r.sessions[i].RevokedReason = reason
+	return nil
+}
+
+func (r *MemorySessionRepository) RevokeAllForUser(ctx context.Context, userID, reason string, at time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var revoked int64
+	for i := range r.sessions {
+		if r.sessions[i].UserID == userID && r.sessions[i].RevokedAt == nil {
+			r.sessions[i].RevokedAt = &at
+			r.sessions[i].RevokedReason = reason
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (r *MemorySessionRepository) DeleteAllForUser(ctx context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before := len(r.sessions)
+	r.sessions = slices.DeleteFunc(r.sessions, func(s models.Session) bool { return s.UserID == userID })
+	return int64(before - len(r.sessions)), nil
+}
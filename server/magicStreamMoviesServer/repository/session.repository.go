@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+type SessionRepository interface {
+	Insert(ctx context.Context, session *models.Session) error
+	// FindBySessionID also returns revoked & expired sessions (reuse-detection)
+	FindBySessionID(ctx context.Context, sessionID string) (*models.Session, error)
+	// ListActiveByUser returns the not revoked, not expired sessions, most recently used first
+	ListActiveByUser(ctx context.Context, userID string, now time.Time) ([]models.Session, error)
+	// Rotate swaps the refresh-token jti, only while oldTokenID is still the current one of an active session (ErrNotFound otherwise)
+	Rotate(ctx context.Context, sessionID, oldTokenID, newTokenID string, usedAt, expiresAt time.Time) error
+	// Revoke fails with ErrNotFound when the session doesn't exist or is already revoked
+	Revoke(ctx context.Context, sessionID, reason string, at time.Time) error
+	// RevokeAllForUser revokes every not yet revoked session of a user, returning how many
+	RevokeAllForUser(ctx context.Context, userID, reason string, at time.Time) (int64, error)
+	// DeleteAllForUser removes every session of a user, revoked ones included (account deletion)
+	DeleteAllForUser(ctx context.Context, userID string) (int64, error)
+}
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type MemoryUserRepository struct {
+	mu    sync.RWMutex
+	users []models.User
+}
+
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{}
+}
+
+func (r *MemoryUserRepository) find(match func(models.User) bool) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := slices.IndexFunc(r.users, match)
+	if i == -1 {
+		return nil, ErrNotFound
+	}
+	user := r.users[i]
+	return &user, nil
+}
+
+func (r *MemoryUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.find(func(u models.User) bool { return u.Email == email })
+}
+
+func (r *MemoryUserRepository) FindByUserID(ctx context.Context, userID string) (*models.User, error) {
+	return r.find(func(u models.User) bool { return u.UserID == userID })
+}
+
+func (r *MemoryUserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	_, err := r.FindByEmail(ctx, email)
+	if err == ErrNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *MemoryUserRepository) Insert(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if slices.ContainsFunc(r.users, func(u models.User) bool { return u.Email == user.Email }) {
+		return ErrDuplicate
+	}
+	if user.ID.IsZero() {
+		user.ID = bson.NewObjectID()
+	}
+	r.users = append(r.users, *user)
+	return nil
+}
+
+// update applies fn to the stored user (ErrNotFound)
+func (r *MemoryUserRepository) update(userID string, fn func(*models.User)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.users, func(u models.User) bool { return u.UserID == userID })
+	if i == -1 {
+		return ErrNotFound
+	}
+	fn(&r.users[i])
+	return nil
+}
+
+func (r *MemoryUserRepository) RenameFavouriteGenre(ctx context.Context, genreID int, name string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var modified int64
+	for i := range r.users {
+		if renameGenre(r.users[i].FavouriteGenres, genreID, name) {
+			modified++
+		}
+	}
+	return modified, nil
+}
+
+func (r *MemoryUserRepository) SetEmailVerified(ctx context.Context, userID string, at time.Time) error {
+	return r.update(userID, func(u *models.User) {
+		u.EmailVerified = true
+		u.EmailVerifiedAt = &at
+		u.UpdatedAt = at
+	})
+}
+
+func (r *MemoryUserRepository) UpdatePassword(ctx context.Context, userID, hash string, at time.Time) error {
+	return r.update(userID, func(u *models.User) {
+		u.Password = hash
+		u.PasswordResetRequired = false
+		u.UpdatedAt = at
+	})
+}
+
+func (r *MemoryUserRepository) SetMFA(ctx context.Context, userID string, mfa models.UserMFA, at time.Time) error {
+	return r.update(userID, func(u *models.User) {
+		mfa.RecoveryCodes = slices.Clone(mfa.RecoveryCodes)
+		u.MFA = mfa
+		u.UpdatedAt = at
+	})
+}
+
+func (r *MemoryUserRepository) UseMFAStep(ctx context.Context, userID string, step int64) error {
+	used := false
+	err := r.update(userID, func(u *models.User) {
+		if u.MFA.Enabled && u.MFA.LastUsedStep < step {
+			u.MFA.LastUsedStep = step
+			used = true
+		}
+	})
+	if err == nil && !used {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (r *MemoryUserRepository) UseRecoveryCode(ctx context.Context, userID, hash string) error {
+	used := false
+	err := r.update(userID, func(u *models.User) {
+		if i := slices.Index(u.MFA.RecoveryCodes, hash); u.MFA.Enabled && i != -1 {
+			u.MFA.RecoveryCodes = slices.Delete(slices.Clone(u.MFA.RecoveryCodes), i, i+1)
+			used = true
+		}
+	})
+	if err == nil && !used {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (r *MemoryUserRepository) UpdateProfile(ctx context.Context, userID, firstName, lastName string, genres []models.Genre, at time.Time) error {
+	return r.update(userID, func(u *models.User) {
+		u.FirstName = firstName
+		u.LastName = lastName
+		u.FavouriteGenres = slices.Clone(genres)
+		u.UpdatedAt = at
+	})
+}
+
+func (r *MemoryUserRepository) Delete(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.users, func(u models.User) bool { return u.UserID == userID })
+	if i == -1 {
+		return ErrNotFound
+	}
+	r.users = slices.Delete(r.users, i, i+1)
+	return nil
+}
+
+func userMatches(u models.User, query UserQuery) bool {
+	if query.Search != "" {
+		search := strings.ToLower(query.Search)
+		if !strings.Contains(strings.ToLower(u.Email), search) &&
+			!strings.Contains(strings.ToLower(u.FirstName), search) &&
+			!strings.Contains(strings.ToLower(u.LastName), search) {
+			return false
+		}
+	}
+	if query.Role != "" && u.Role != query.Role {
+		return false
+	}
+	if query.Disabled != nil && u.Disabled != *query.Disabled {
+		return false
+	}
+	return true
+}
+
+func (r *MemoryUserRepository) Find(ctx context.Context, query UserQuery) ([]models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := []models.User{}
+	for _, user := range r.users {
+		if userMatches(user, query) && (query.AfterID == "" || user.ID.Hex() > query.AfterID) {
+			users = append(users, user)
+		}
+	}
+
+	slices.SortFunc(users, func(a, b models.User) int { return strings.Compare(a.ID.Hex(), b.ID.Hex()) })
+
+	if query.Limit > 0 && int64(len(users)) > query.Limit {
+		users = users[:query.Limit]
+	}
+	return users, nil
+}
+
+func (r *MemoryUserRepository) Count(ctx context.Context, query UserQuery) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, user := range r.users {
+		if userMatches(user, query) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryUserRepository) SetRole(ctx context.Context, userID, role string, at time.Time) error {
+	return r.update(userID, func(u *models.User) {
+		u.Role = role
+		u.UpdatedAt = at
+	})
+}
+
+func (r *MemoryUserRepository) SetDisabled(ctx context.Context, userID string, disabled bool, reason string, at time.Time) error {
+	return r.update(userID, func(u *models.User) {
+		u.Disabled = disabled
+		u.DisabledAt, u.DisabledReason = nil, ""
+		if disabled {
+			u.DisabledAt, u.DisabledReason = &at, reason
+		}
+		u.UpdatedAt = at
+	})
+}
+
+func (r *MemoryUserRepository) RequirePasswordReset(ctx context.Context, userID string, at time.Time) error {
+	return r.update(userID, func(u *models.User) {
+		u.PasswordResetRequired = true
+		u.UpdatedAt = at
+	})
+}
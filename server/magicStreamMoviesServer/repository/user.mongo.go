@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/database"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type MongoUserRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoUserRepository(client *mongo.Client) *MongoUserRepository {
+	return &MongoUserRepository{collection: database.OpenCollection("users", client)}
+}
+
+func (r *MongoUserRepository) findOne(ctx context.Context, filter bson.M) (*models.User, error) {
+	var user models.User
+	err := r.collection.FindOne(ctx, filter).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *MongoUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.findOne(ctx, bson.M{"email": email})
+}
+
+func (r *MongoUserRepository) FindByUserID(ctx context.Context, userID string) (*models.User, error) {
+	return r.findOne(ctx, bson.M{"user_id": userID})
+}
+
+func (r *MongoUserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"email": email})
+	return count > 0, err
+}
+
+func (r *MongoUserRepository) Insert(ctx context.Context, user *models.User) error {
+	result, err := r.collection.InsertOne(ctx, user)
+	if err != nil {
+		return err
+	}
+	if id, ok := result.InsertedID.(bson.ObjectID); ok {
+		user.ID = id
+	}
+	return nil
+}
+
+func (r *MongoUserRepository) RenameFavouriteGenre(ctx context.Context, genreID int, name string) (int64, error) {
+	opts := options.UpdateMany().SetArrayFilters([]any{bson.M{"g.genre_id": genreID}})
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"favourite_genres.genre_id": genreID},
+		bson.M{"$set": bson.M{"favourite_genres.$[g].genre_name": name}},
+		opts)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// set updates fields of one user (ErrNotFound)
+func (r *MongoUserRepository) set(ctx context.Context, userID string, fields bson.M) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"user_id": userID}, bson.M{"$set": fields})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoUserRepository) SetEmailVerified(ctx context.Context, userID string, at time.Time) error {
+	return r.set(ctx, userID, bson.M{"email_verified": true, "email_verified_at": at, "updated_at": at})
+}
+
+func (r *MongoUserRepository) UpdatePassword(ctx context.Context, userID, hash string, at time.Time) error {
+	return r.set(ctx, userID, bson.M{"password": hash, "password_reset_required": false, "updated_at": at})
+}
+
+func (r *MongoUserRepository) SetMFA(ctx context.Context, userID string, mfa models.UserMFA, at time.Time) error {
+	return r.set(ctx, userID, bson.M{"mfa": mfa, "updated_at": at})
+}
+
+func (r *MongoUserRepository) UseMFAStep(ctx context.Context, userID string, step int64) error {
+	filter := bson.M{"user_id": userID, "mfa.enabled": true, "mfa.last_used_step": bson.M{"$lt": step}}
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"mfa.last_used_step": step}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoUserRepository) UseRecoveryCode(ctx context.Context, userID, hash string) error {
+	filter := bson.M{"user_id": userID, "mfa.enabled": true, "mfa.recovery_codes": hash}
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$pull": bson.M{"mfa.recovery_codes": hash}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoUserRepository) UpdateProfile(ctx context.Context, userID, firstName, lastName string, genres []models.Genre, at time.Time) error {
+	return r.set(ctx, userID, bson.M{"first_name": firstName, "last_name": lastName, "favourite_genres": genres, "updated_at": at})
+}
+
+func (r *MongoUserRepository) Delete(ctx context.Context, userID string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func userFilter(query UserQuery) bson.M {
+	filter := bson.M{}
+	if query.Search != "" {
+		pattern := bson.Regex{Pattern: regexp.QuoteMeta(query.Search), Options: "i"}
+		filter["$or"] = bson.A{
+			bson.M{"email": pattern},
+			bson.M{"first_name": pattern},
+			bson.M{"last_name": pattern},
+		}
+	}
+	if query.Role != "" {
+		filter["role"] = query.Role
+	}
+	if query.Disabled != nil {
+		// Users from before accounts could be disabled have no such field
+		if *query.Disabled {
+			filter["disabled"] = true
+		} else {
+			filter["disabled"] = bson.M{"$ne": true}
+		}
+	}
+	return filter
+}
+
+func (r *MongoUserRepository) Find(ctx context.Context, query UserQuery) ([]models.User, error) {
+	filter := userFilter(query)
+	if query.AfterID != "" {
+		lastID, err := bson.ObjectIDFromHex(query.AfterID)
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{"$gt": lastID}
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	if query.Limit > 0 {
+		findOptions.SetLimit(query.Limit)
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	users := []models.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *MongoUserRepository) Count(ctx context.Context, query UserQuery) (int64, error) {
+	return r.collection.CountDocuments(ctx, userFilter(query))
+}
+
+func (r *MongoUserRepository) SetRole(ctx context.Context, userID, role string, at time.Time) error {
+	return r.set(ctx, userID, bson.M{"role": role, "updated_at": at})
+}
+
+func (r *MongoUserRepository) SetDisabled(ctx context.Context, userID string, disabled bool, reason string, at time.Time) error {
+	if !disabled {
+		result, err := r.collection.UpdateOne(ctx, bson.M{"user_id": userID}, bson.M{
+			"$set":   bson.M{"disabled": false, "updated_at": at},
+			"$unset": bson.M{"disabled_at": "", "disabled_reason": ""},
+		})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return ErrNotFound
+		}
+		return nil
+	}
+	return r.set(ctx, userID, bson.M{"disabled": true, "disabled_at": at, "disabled_reason": reason, "updated_at": at})
+}
+
+func (r *MongoUserRepository) RequirePasswordReset(ctx context.Context, userID string, at time.Time) error {
+	return r.set(ctx, userID, bson.M{"password_reset_required": true, "updated_at": at})
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+// UserQuery filters & pages users (oldest first, by _id). Zero-values mean "no filter"
+type UserQuery struct {
+	// Search matches e-mail, first or last name (case-insensitive, anywhere in them)
+	Search   string
+	Role     string
+	Disabled *bool
+
+	AfterID string // _id (hex) of the last user of the previous page
+	Limit   int64  // 0 = unbounded
+}
+
+type UserRepository interface {
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	FindByUserID(ctx context.Context, userID string) (*models.User, error)
+	EmailExists(ctx context.Context, email string) (bool, error)
+	Insert(ctx context.Context, user *models.User) error
+	// RenameFavouriteGenre cascades a genre rename into every user's favourite_genres
+	RenameFavouriteGenre(ctx context.Context, genreID int, name string) (int64, error)
+	SetEmailVerified(ctx context.Context, userID string, at time.Time) error
+	// UpdatePassword stores a new (already hashed) password, and clears password_reset_required
+	UpdatePassword(ctx context.Context, userID, hash string, at time.Time) error
+	UpdateProfile(ctx context.Context, userID, firstName, lastName string, genres []models.Genre, at time.Time) error
+	// Delete removes the user for good (ErrNotFound)
+	Delete(ctx context.Context, userID string) error
+	SetMFA(ctx context.Context, userID string, mfa models.UserMFA, at time.Time) error
+	// UseMFAStep records a used TOTP time-step, ErrNotFound when it (or a later one) was used already
+	UseMFAStep(ctx context.Context, userID string, step int64) error
+	// UseRecoveryCode removes a recovery-code (hash), ErrNotFound when it isn't one of the user's unused codes
+	UseRecoveryCode(ctx context.Context, userID, hash string) error
+	Find(ctx context.Context, query UserQuery) ([]models.User, error)
+	// Count ignores AfterID & Limit
+	Count(ctx context.Context, query UserQuery) (int64, error)
+	SetRole(ctx context.Context, userID, role string, at time.Time) error
+	// SetDisabled disables (with a reason) or re-enables an account
+	SetDisabled(ctx context.Context, userID string, disabled bool, reason string, at time.Time) error
+	// RequirePasswordReset flags the account until UpdatePassword is called
+	RequirePasswordReset(ctx context.Context, userID string, at time.Time) error
+}
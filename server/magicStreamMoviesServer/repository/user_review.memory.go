@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type MemoryUserReviewRepository struct {
+	mu      sync.RWMutex
+	reviews []models.UserReview
+}
+
+func NewMemoryUserReviewRepository() *MemoryUserReviewRepository {
+	return &MemoryUserReviewRepository{}
+}
+
+func (r *MemoryUserReviewRepository) Upsert(ctx context.Context, review *models.UserReview) (*models.UserReview, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.reviews, func(rv models.UserReview) bool {
+		return rv.ImdbID == review.ImdbID && rv.UserID == review.UserID
+	})
+	if i == -1 {
+		if review.ID.IsZero() {
+			review.ID = bson.NewObjectID()
+		}
+		review.Hidden = false
+		r.reviews = append(r.reviews, *review)
+		return nil, nil
+	}
+
+	before := r.reviews[i]
+	stored := &r.reviews[i]
+	stored.AuthorName, stored.Rating, stored.Text, stored.UpdatedAt = review.AuthorName, review.Rating, review.Text, review.UpdatedAt
+	*review = *stored
+	return &before, nil
+}
+
+func (r *MemoryUserReviewRepository) find(match func(models.UserReview) bool) (*models.UserReview, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := slices.IndexFunc(r.reviews, match)
+	if i == -1 {
+		return nil, ErrNotFound
+	}
+	review := r.reviews[i]
+	return &review, nil
+}
+
+func (r *MemoryUserReviewRepository) FindByReviewID(ctx context.Context, reviewID string) (*models.UserReview, error) {
+	return r.find(func(rv models.UserReview) bool { return rv.ReviewID == reviewID })
+}
+
+func (r *MemoryUserReviewRepository) FindByUserAndMovie(ctx context.Context, userID, imdbID string) (*models.UserReview, error) {
+	return r.find(func(rv models.UserReview) bool { return rv.UserID == userID && rv.ImdbID == imdbID })
+}
+
+func userReviewMatches(review models.UserReview, query UserReviewQuery) bool {
+	return (query.ImdbID == "" || review.ImdbID == query.ImdbID) &&
+		(query.UserID == "" || review.UserID == query.UserID) &&
+		(query.Hidden == nil || review.Hidden == *query.Hidden) &&
+		(query.SentimentStatus == "" || review.SentimentStatus == query.SentimentStatus)
+}
+
+func (r *MemoryUserReviewRepository) Find(ctx context.Context, query UserReviewQuery) ([]models.UserReview, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reviews := []models.UserReview{}
+	for _, review := range r.reviews {
+		if userReviewMatches(review, query) && (query.AfterID == "" || review.ID.Hex() < query.AfterID) {
+			reviews = append(reviews, review)
+		}
+	}
+
+	// Newest first
+	slices.SortFunc(reviews, func(a, b models.UserReview) int { return strings.Compare(b.ID.Hex(), a.ID.Hex()) })
+
+	if query.Limit > 0 && int64(len(reviews)) > query.Limit {
+		reviews = reviews[:query.Limit]
+	}
+	return reviews, nil
+}
+
+func (r *MemoryUserReviewRepository) ImdbIDsForUser(ctx context.Context, userID string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	imdbIDs := []string{}
+	for _, review := range r.reviews {
+		if review.UserID == userID {
+			imdbIDs = append(imdbIDs, review.ImdbID)
+		}
+	}
+	return imdbIDs, nil
+}
+
+func (r *MemoryUserReviewRepository) Count(ctx context.Context, query UserReviewQuery) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, review := range r.reviews {
+		if userReviewMatches(review, query) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryUserReviewRepository) Delete(ctx context.Context, reviewID string) (*models.UserReview, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.reviews, func(rv models.UserReview) bool { return rv.ReviewID == reviewID })
+	if i == -1 {
+		return nil, ErrNotFound
+	}
+	review := r.reviews[i]
+	r.reviews = slices.Delete(r.reviews, i, i+1)
+	return &review, nil
+}
+
+func (r *MemoryUserReviewRepository) SetHidden(ctx context.Context, reviewID string, hidden bool, by, reason string, at time.Time) (*models.UserReview, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.reviews, func(rv models.UserReview) bool { return rv.ReviewID == reviewID && rv.Hidden != hidden })
+	if i == -1 {
+		return nil, ErrNotFound
+	}
+	before := r.reviews[i]
+	stored := &r.reviews[i]
+	stored.Hidden, stored.HiddenBy, stored.HiddenReason, stored.HiddenAt = hidden, "", "", nil
+	if hidden {
+		stored.HiddenBy, stored.HiddenReason, stored.HiddenAt = by, reason, &at
+	}
+	return &before, nil
+}
+
+// updateSentiment applies update to the review (with that text, PENDING only when pendingOnly) and returns it as it was before
+func (r *MemoryUserReviewRepository) updateSentiment(reviewID, text string, pendingOnly bool, update func(*models.UserReview)) (*models.UserReview, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.reviews, func(rv models.UserReview) bool {
+		return rv.ReviewID == reviewID && rv.Text == text && (!pendingOnly || rv.SentimentStatus == models.SentimentPending)
+	})
+	if i == -1 {
+		return nil, ErrNotFound
+	}
+	before := r.reviews[i]
+	update(&r.reviews[i])
+	return &before, nil
+}
+
+func (r *MemoryUserReviewRepository) ResetSentiment(ctx context.Context, reviewID, text string) (*models.UserReview, error) {
+	return r.updateSentiment(reviewID, text, false, func(review *models.UserReview) {
+		review.Sentiment, review.SentimentStatus, review.SentimentProvider, review.SentimentError, review.SentimentAt = nil, "", "", "", nil
+		if text != "" {
+			review.SentimentStatus = models.SentimentPending
+		}
+	})
+}
+
+func (r *MemoryUserReviewRepository) SetSentiment(ctx context.Context, reviewID, text string, sentiment models.Ranking, provider string, at time.Time) (*models.UserReview, error) {
+	return r.updateSentiment(reviewID, text, true, func(review *models.UserReview) {
+		review.Sentiment, review.SentimentStatus, review.SentimentProvider, review.SentimentError, review.SentimentAt = &sentiment, models.SentimentRanked, provider, "", &at
+	})
+}
+
+func (r *MemoryUserReviewRepository) FailSentiment(ctx context.Context, reviewID, text, reason string, at time.Time) error {
+	_, err := r.updateSentiment(reviewID, text, true, func(review *models.UserReview) {
+		review.SentimentStatus, review.SentimentError, review.SentimentAt = models.SentimentFailed, reason, &at
+	})
+	return err
+}
+
+func (r *MemoryUserReviewRepository) RenameSentiment(ctx context.Context, rankingValue int, name string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var updated int64
+	for i := range r.reviews {
+		// A fresh Ranking, earlier copies of the review share the old one
+		if sentiment := r.reviews[i].Sentiment; sentiment != nil && sentiment.RankingValue == rankingValue && sentiment.RankingName != name {
+			r.reviews[i].Sentiment = &models.Ranking{RankingValue: rankingValue, RankingName: name}
+			updated++
+		}
+	}
+	return updated, nil
+}
@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/database"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type MongoUserReviewRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoUserReviewRepository(client *mongo.Client) *MongoUserReviewRepository {
+	return &MongoUserReviewRepository{collection: database.OpenCollection("user_reviews", client)}
+}
+
+// decodeReview decodes a single result (nil, nil when there was none)
+func decodeReview(result *mongo.SingleResult) (*models.UserReview, error) {
+	var review models.UserReview
+	err := result.Decode(&review)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *MongoUserReviewRepository) Upsert(ctx context.Context, review *models.UserReview) (*models.UserReview, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"author_name": review.AuthorName,
+			"rating":      review.Rating,
+			"text":        review.Text,
+			"updated_at":  review.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{
+			"review_id":  review.ReviewID,
+			"created_at": review.CreatedAt,
+			"hidden":     false,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before)
+	before, err := decodeReview(r.collection.FindOneAndUpdate(ctx,
+		bson.M{"imdb_id": review.ImdbID, "user_id": review.UserID}, update, opts))
+	if mongo.IsDuplicateKeyError(err) {
+		// Lost a race with the same user's first review, it's an edit now
+		before, err = decodeReview(r.collection.FindOneAndUpdate(ctx,
+			bson.M{"imdb_id": review.ImdbID, "user_id": review.UserID}, update, opts))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if before != nil {
+		review.ID, review.ReviewID, review.CreatedAt = before.ID, before.ReviewID, before.CreatedAt
+		review.Hidden, review.HiddenBy, review.HiddenReason, review.HiddenAt = before.Hidden, before.HiddenBy, before.HiddenReason, before.HiddenAt
+		review.Sentiment, review.SentimentStatus, review.SentimentProvider = before.Sentiment, before.SentimentStatus, before.SentimentProvider
+		review.SentimentError, review.SentimentAt = before.SentimentError, before.SentimentAt
+	}
+	return before, nil
+}
+
+func (r *MongoUserReviewRepository) findOne(ctx context.Context, filter bson.M) (*models.UserReview, error) {
+	review, err := decodeReview(r.collection.FindOne(ctx, filter))
+	if err == nil && review == nil {
+		return nil, ErrNotFound
+	}
+	return review, err
+}
+
+func (r *MongoUserReviewRepository) FindByReviewID(ctx context.Context, reviewID string) (*models.UserReview, error) {
+	return r.findOne(ctx, bson.M{"review_id": reviewID})
+}
+
+func (r *MongoUserReviewRepository) FindByUserAndMovie(ctx context.Context, userID, imdbID string) (*models.UserReview, error) {
+	return r.findOne(ctx, bson.M{"user_id": userID, "imdb_id": imdbID})
+}
+
+func userReviewFilter(query UserReviewQuery) bson.M {
+	filter := bson.M{}
+	if query.ImdbID != "" {
+		filter["imdb_id"] = query.ImdbID
+	}
+	if query.UserID != "" {
+		filter["user_id"] = query.UserID
+	}
+	if query.Hidden != nil {
+		filter["hidden"] = *query.Hidden
+	}
+	if query.SentimentStatus != "" {
+		filter["sentiment_status"] = query.SentimentStatus
+	}
+	return filter
+}
+
+func (r *MongoUserReviewRepository) Find(ctx context.Context, query UserReviewQuery) ([]models.UserReview, error) {
+	filter := userReviewFilter(query)
+	if query.AfterID != "" {
+		lastID, err := bson.ObjectIDFromHex(query.AfterID)
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{"$lt": lastID}
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}})
+	if query.Limit > 0 {
+		findOptions.SetLimit(query.Limit)
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	reviews := []models.UserReview{}
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+func (r *MongoUserReviewRepository) ImdbIDsForUser(ctx context.Context, userID string) ([]string, error) {
+	imdbIDs := []string{}
+	err := r.collection.Distinct(ctx, "imdb_id", bson.M{"user_id": userID}).Decode(&imdbIDs)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return []string{}, nil
+	}
+	return imdbIDs, err
+}
+
+func (r *MongoUserReviewRepository) Count(ctx context.Context, query UserReviewQuery) (int64, error) {
+	return r.collection.CountDocuments(ctx, userReviewFilter(query))
+}
+
+func (r *MongoUserReviewRepository) Delete(ctx context.Context, reviewID string) (*models.UserReview, error) {
+	review, err := decodeReview(r.collection.FindOneAndDelete(ctx, bson.M{"review_id": reviewID}))
+	if err == nil && review == nil {
+		return nil, ErrNotFound
+	}
+	return review, err
+}
+
+func (r *MongoUserReviewRepository) SetHidden(ctx context.Context, reviewID string, hidden bool, by, reason string, at time.Time) (*models.UserReview, error) {
+	update := bson.M{
+		"$set":   bson.M{"hidden": false},
+		"$unset": bson.M{"hidden_by": "", "hidden_reason": "", "hidden_at": ""},
+	}
+	if hidden {
+		update = bson.M{"$set": bson.M{"hidden": true, "hidden_by": by, "hidden_reason": reason, "hidden_at": at}}
+	}
+
+	filter := bson.M{"review_id": reviewID, "hidden": !hidden}
+	before, err := decodeReview(r.collection.FindOneAndUpdate(ctx, filter, update))
+	if err == nil && before == nil {
+		return nil, ErrNotFound
+	}
+	return before, err
+}
+
+func (r *MongoUserReviewRepository) ResetSentiment(ctx context.Context, reviewID, text string) (*models.UserReview, error) {
+	unset := bson.M{"sentiment": "", "sentiment_provider": "", "sentiment_error": "", "sentiment_at": ""}
+	update := bson.M{"$unset": unset}
+	if text != "" {
+		update["$set"] = bson.M{"sentiment_status": models.SentimentPending}
+	} else {
+		unset["sentiment_status"] = ""
+	}
+
+	before, err := decodeReview(r.collection.FindOneAndUpdate(ctx, bson.M{"review_id": reviewID, "text": text}, update))
+	if err == nil && before == nil {
+		return nil, ErrNotFound
+	}
+	return before, err
+}
+
+func (r *MongoUserReviewRepository) SetSentiment(ctx context.Context, reviewID, text string, sentiment models.Ranking, provider string, at time.Time) (*models.UserReview, error) {
+	filter := bson.M{"review_id": reviewID, "text": text, "sentiment_status": models.SentimentPending}
+	update := bson.M{
+		"$set": bson.M{
+			"sentiment":          sentiment,
+			"sentiment_status":   models.SentimentRanked,
+			"sentiment_provider": provider,
+			"sentiment_at":       at,
+		},
+		"$unset": bson.M{"sentiment_error": ""},
+	}
+
+	before, err := decodeReview(r.collection.FindOneAndUpdate(ctx, filter, update))
+	if err == nil && before == nil {
+		return nil, ErrNotFound
+	}
+	return before, err
+}
+
+func (r *MongoUserReviewRepository) FailSentiment(ctx context.Context, reviewID, text, reason string, at time.Time) error {
+	filter := bson.M{"review_id": reviewID, "text": text, "sentiment_status": models.SentimentPending}
+	update := bson.M{"$set": bson.M{"sentiment_status": models.SentimentFailed, "sentiment_error": reason, "sentiment_at": at}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoUserReviewRepository) RenameSentiment(ctx context.Context, rankingValue int, name string) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"sentiment.ranking_value": rankingValue},
+		bson.M{"$set": bson.M{"sentiment.ranking_name": name}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+// UserReviewQuery filters & pages user-reviews (newest first, by _id). Zero-values mean "no filter"
+type UserReviewQuery struct {
+	ImdbID string
+	UserID string
+	Hidden *bool
+	// SentimentStatus e.g. PENDING (for the background sentiment-ranking)
+	SentimentStatus string
+
+	AfterID string // _id (hex) of the last review of the previous page
+	Limit   int64  // 0 = unbounded
+}
+
+type UserReviewRepository interface {
+	// Upsert creates or edits the user's review of the movie (one per user & movie), and returns the review
+	// as it was before (nil when it's new), so the movie's user_rating can be adjusted by the difference
+	Upsert(ctx context.Context, review *models.UserReview) (*models.UserReview, error)
+	FindByReviewID(ctx context.Context, reviewID string) (*models.UserReview, error)
+	FindByUserAndMovie(ctx context.Context, userID, imdbID string) (*models.UserReview, error)
+	Find(ctx context.Context, query UserReviewQuery) ([]models.UserReview, error)
+	// ImdbIDsForUser returns the imdb_id of every movie the user reviewed, nothing else
+	ImdbIDsForUser(ctx context.Context, userID string) ([]string, error)
+	// Count ignores AfterID & Limit
+	Count(ctx context.Context, query UserReviewQuery) (int64, error)
+	// Delete removes the review and returns it (ErrNotFound)
+	Delete(ctx context.Context, reviewID string) (*models.UserReview, error)
+	// SetHidden hides (by a moderator, with a reason) or un-hides a review, and returns it as it was before.
+	// ErrNotFound when the review doesn't exist or is already in that state
+	SetHidden(ctx context.Context, reviewID string, hidden bool, by, reason string, at time.Time) (*models.UserReview, error)
+	// ResetSentiment drops the sentiment of a review whose text is (still) text, and marks it PENDING (no status for an
+	// empty text). Returns the review as it was before, ErrNotFound when the text changed in the meantime
+	ResetSentiment(ctx context.Context, reviewID, text string) (*models.UserReview, error)
+	// SetSentiment stores the ranked sentiment of a PENDING review whose text is (still) text, and returns the review
+	// as it was before. ErrNotFound when it was edited, re-ranked or deleted in the meantime
+	SetSentiment(ctx context.Context, reviewID, text string, sentiment models.Ranking, provider string, at time.Time) (*models.UserReview, error)
+	// FailSentiment marks a PENDING review (with that text) as FAILED
+	FailSentiment(ctx context.Context, reviewID, text, reason string, at time.Time) error
+	// RenameSentiment cascades a ranking rename into the reviews' sentiment
+	RenameSentiment(ctx context.Context, rankingValue int, name string) (int64, error)
+}
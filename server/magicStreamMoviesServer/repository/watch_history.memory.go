@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type MemoryWatchHistoryRepository struct {
+	mu      sync.RWMutex
+	entries []models.WatchHistory
+}
+
+func NewMemoryWatchHistoryRepository() *MemoryWatchHistoryRepository {
+	return &MemoryWatchHistoryRepository{}
+}
+
+func (r *MemoryWatchHistoryRepository) Record(ctx context.Context, update PlaybackUpdate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.entries, func(e models.WatchHistory) bool {
+		return e.UserID == update.UserID && e.ImdbID == update.ImdbID
+	})
+	if i == -1 {
+		r.entries = append(r.entries, models.WatchHistory{
+			ID:             bson.NewObjectID(),
+			UserID:         update.UserID,
+			ImdbID:         update.ImdbID,
+			FirstWatchedAt: update.At,
+		})
+		i = len(r.entries) - 1
+	}
+
+	entry := &r.entries[i]
+	if slices.Contains(entry.EventIDs, update.EventID) || entry.LastWatchedAt.After(update.At) {
+		return nil
+	}
+	update.ApplyTo(entry)
+	// A fresh slice, earlier copies of the entry share the old one
+	eventIDs := append(slices.Clone(entry.EventIDs), update.EventID)
+	entry.EventIDs = eventIDs[max(0, len(eventIDs)-maxPlaybackEventIDs):]
+	return nil
+}
+
+func (r *MemoryWatchHistoryRepository) FindByUserAndMovie(ctx context.Context, userID, imdbID string) (*models.WatchHistory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := slices.IndexFunc(r.entries, func(e models.WatchHistory) bool { return e.UserID == userID && e.ImdbID == imdbID })
+	if i == -1 {
+		return nil, ErrNotFound
+	}
+	entry := r.entries[i]
+	return &entry, nil
+}
+
+// Most recently watched first, same order as Mongo
+func compareWatchHistory(a, b models.WatchHistory) int {
+	return cmp.Or(b.LastWatchedAt.Compare(a.LastWatchedAt), strings.Compare(b.ID.Hex(), a.ID.Hex()))
+}
+
+func (r *MemoryWatchHistoryRepository) Find(ctx context.Context, query WatchHistoryQuery) ([]models.WatchHistory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var after *models.WatchHistory
+	if query.After != nil {
+		lastID, err := bson.ObjectIDFromHex(query.After.ID)
+		if err != nil {
+			return nil, err
+		}
+		after = &models.WatchHistory{ID: lastID, LastWatchedAt: query.After.LastWatchedAt}
+	}
+
+	entries := []models.WatchHistory{}
+	for _, entry := range r.entries {
+		if entry.UserID != query.UserID || (query.InProgress && !entry.InProgress) {
+			continue
+		}
+		if after != nil && compareWatchHistory(entry, *after) <= 0 {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, compareWatchHistory)
+
+	if query.Limit > 0 && int64(len(entries)) > query.Limit {
+		entries = entries[:query.Limit]
+	}
+	return entries, nil
+}
+
+func (r *MemoryWatchHistoryRepository) ImdbIDsForUser(ctx context.Context, userID string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	imdbIDs := []string{}
+	for _, entry := range r.entries {
+		if entry.UserID == userID {
+			imdbIDs = append(imdbIDs, entry.ImdbID)
+		}
+	}
+	return imdbIDs, nil
+}
+
+func (r *MemoryWatchHistoryRepository) DeleteAllForUser(ctx context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before := len(r.entries)
+	r.entries = slices.DeleteFunc(r.entries, func(e models.WatchHistory) bool { return e.UserID == userID })
+	return int64(before - len(r.entries)), nil
+}
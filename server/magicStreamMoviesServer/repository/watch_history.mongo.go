@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/database"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type MongoWatchHistoryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoWatchHistoryRepository(client *mongo.Client) *MongoWatchHistoryRepository {
+	return &MongoWatchHistoryRepository{collection: database.OpenCollection("watch_history", client)}
+}
+
+func (r *MongoWatchHistoryRepository) Record(ctx context.Context, update PlaybackUpdate) error {
+	set := bson.M{
+		"position_seconds": update.Position,
+		"in_progress":      update.InProgress,
+		"completed":        update.Completed,
+		"last_watched_at":  update.At,
+	}
+	if update.Duration > 0 {
+		set["duration_seconds"] = update.Duration
+	}
+	if update.Completed {
+		set["completed_at"] = update.At
+	}
+	plays := 0
+	if update.Started {
+		plays = 1
+	}
+
+	// Only matches while the event is new & not older than the last one. Otherwise the upsert runs into the
+	// unique (user_id, imdb_id) index, and the event is skipped
+	filter := bson.M{
+		"user_id":         update.UserID,
+		"imdb_id":         update.ImdbID,
+		"event_ids":       bson.M{"$ne": update.EventID},
+		"last_watched_at": bson.M{"$lte": update.At},
+	}
+	_, err := r.collection.UpdateOne(ctx, filter, bson.M{
+		"$set":         set,
+		"$inc":         bson.M{"play_count": plays},
+		"$setOnInsert": bson.M{"first_watched_at": update.At},
+		"$push":        bson.M{"event_ids": bson.M{"$each": bson.A{update.EventID}, "$slice": -maxPlaybackEventIDs}},
+	}, options.UpdateOne().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+func (r *MongoWatchHistoryRepository) FindByUserAndMovie(ctx context.Context, userID, imdbID string) (*models.WatchHistory, error) {
+	var entry models.WatchHistory
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "imdb_id": imdbID}).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *MongoWatchHistoryRepository) Find(ctx context.Context, query WatchHistoryQuery) ([]models.WatchHistory, error) {
+	filter := bson.M{"user_id": query.UserID}
+	if query.InProgress {
+		filter["in_progress"] = true
+	}
+	if query.After != nil {
+		lastID, err := bson.ObjectIDFromHex(query.After.ID)
+		if err != nil {
+			return nil, err
+		}
+		filter["$or"] = bson.A{
+			bson.M{"last_watched_at": bson.M{"$lt": query.After.LastWatchedAt}},
+			bson.M{"last_watched_at": query.After.LastWatchedAt, "_id": bson.M{"$lt": lastID}},
+		}
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "last_watched_at", Value: -1}, {Key: "_id", Value: -1}})
+	if query.Limit > 0 {
+		findOptions.SetLimit(query.Limit)
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []models.WatchHistory{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *MongoWatchHistoryRepository) ImdbIDsForUser(ctx context.Context, userID string) ([]string, error) {
+	imdbIDs := []string{}
+	err := r.collection.Distinct(ctx, "imdb_id", bson.M{"user_id": userID}).Decode(&imdbIDs)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return []string{}, nil
+	}
+	return imdbIDs, err
+}
+
+func (r *MongoWatchHistoryRepository) DeleteAllForUser(ctx context.Context, userID string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
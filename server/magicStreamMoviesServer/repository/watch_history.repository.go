@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+// How many of the latest event_ids a watch-history entry remembers (idempotent retries)
+const maxPlaybackEventIDs = 20
+
+// PlaybackUpdate is a (coalesced) playback-event, as it's written to the user's watch-history of a movie
+type PlaybackUpdate struct {
+	UserID   string
+	ImdbID   string
+	EventID  string
+	Started  bool // counts as a play
+	Position float64
+	Duration float64 // 0 = keep the known one
+	// Completed/InProgress: the entry's state after this event
+	Completed  bool
+	InProgress bool
+	At         time.Time // when the server got the event, older ones than the entry's last_watched_at are dropped
+}
+
+// ApplyTo updates an entry (e.g. a stored one, with a not yet written update)
+func (u PlaybackUpdate) ApplyTo(entry *models.WatchHistory) {
+	if u.Started {
+		entry.PlayCount++
+	}
+	entry.PositionSeconds = u.Position
+	if u.Duration > 0 {
+		entry.DurationSeconds = u.Duration
+	}
+	entry.InProgress, entry.Completed = u.InProgress, u.Completed
+	if u.Completed {
+		at := u.At
+		entry.CompletedAt = &at
+	}
+	entry.LastWatchedAt = u.At
+}
+
+// WatchHistoryCursor is the position after which the next page starts (last_watched_at + _id tie-breaker)
+type WatchHistoryCursor struct {
+	LastWatchedAt time.Time
+	ID            string
+}
+
+// WatchHistoryQuery lists a user's watch-history, most recently watched first
+type WatchHistoryQuery struct {
+	UserID string
+	// InProgress only keeps "continue watching"
+	InProgress bool
+
+	After *WatchHistoryCursor
+	Limit int64 // 0 = unbounded
+}
+
+type WatchHistoryRepository interface {
+	// Record applies the update (creating the entry on the first event). An event that was applied already, or is
+	// older than the entry's last one, is skipped without an error
+	Record(ctx context.Context, update PlaybackUpdate) error
+	FindByUserAndMovie(ctx context.Context, userID, imdbID string) (*models.WatchHistory, error)
+	Find(ctx context.Context, query WatchHistoryQuery) ([]models.WatchHistory, error)
+	// ImdbIDsForUser returns the imdb_id of every movie the user watched (or started), nothing else
+	ImdbIDsForUser(ctx context.Context, userID string) ([]string, error)
+	DeleteAllForUser(ctx context.Context, userID string) (int64, error)
+}
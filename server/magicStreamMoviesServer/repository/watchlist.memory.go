@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type MemoryWatchlistRepository struct {
+	mu      sync.RWMutex
+	entries []models.WatchlistEntry
+}
+
+func NewMemoryWatchlistRepository() *MemoryWatchlistRepository {
+	return &MemoryWatchlistRepository{}
+}
+
+func (r *MemoryWatchlistRepository) List(ctx context.Context, userID string) ([]models.WatchlistEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := []models.WatchlistEntry{}
+	for _, entry := range r.entries {
+		if entry.UserID == userID {
+			entries = append(entries, entry)
+		}
+	}
+	slices.SortStableFunc(entries, func(a, b models.WatchlistEntry) int {
+		return cmp.Or(cmp.Compare(a.Position, b.Position), a.AddedAt.Compare(b.AddedAt))
+	})
+	return entries, nil
+}
+
+func (r *MemoryWatchlistRepository) Add(ctx context.Context, entry *models.WatchlistEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := 0
+	for _, e := range r.entries {
+		if e.UserID != entry.UserID {
+			continue
+		}
+		if e.ImdbID == entry.ImdbID {
+			return ErrDuplicate
+		}
+		last = max(last, e.Position)
+	}
+	entry.Position = last + 1
+	if entry.ID.IsZero() {
+		entry.ID = bson.NewObjectID()
+	}
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *MemoryWatchlistRepository) Remove(ctx context.Context, userID, imdbID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.entries, func(e models.WatchlistEntry) bool { return e.UserID == userID && e.ImdbID == imdbID })
+	if i == -1 {
+		return ErrNotFound
+	}
+	r.entries = slices.Delete(r.entries, i, i+1)
+	return nil
+}
+
+func (r *MemoryWatchlistRepository) Reorder(ctx context.Context, userID string, imdbIDs []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.entries {
+		if r.entries[i].UserID != userID {
+			continue
+		}
+		if pos := slices.Index(imdbIDs, r.entries[i].ImdbID); pos != -1 {
+			r.entries[i].Position = pos + 1
+		}
+	}
+	return nil
+}
+
+func (r *MemoryWatchlistRepository) Contains(ctx context.Context, userID string, imdbIDs []string) (map[string]bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	found := map[string]bool{}
+	for _, entry := range r.entries {
+		if entry.UserID == userID && slices.Contains(imdbIDs, entry.ImdbID) {
+			found[entry.ImdbID] = true
+		}
+	}
+	return found, nil
+}
+
+func (r *MemoryWatchlistRepository) DeleteAllForUser(ctx context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before := len(r.entries)
+	r.entries = slices.DeleteFunc(r.entries, func(e models.WatchlistEntry) bool { return e.UserID == userID })
+	return int64(before - len(r.entries)), nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/database"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type MongoWatchlistRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoWatchlistRepository(client *mongo.Client) *MongoWatchlistRepository {
+	return &MongoWatchlistRepository{collection: database.OpenCollection("watchlist", client)}
+}
+
+func (r *MongoWatchlistRepository) List(ctx context.Context, userID string) ([]models.WatchlistEntry, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "position", Value: 1}, {Key: "added_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []models.WatchlistEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *MongoWatchlistRepository) Add(ctx context.Context, entry *models.WatchlistEntry) error {
+	// After the current last one. Two concurrent adds may share a position, added_at keeps them apart
+	var last models.WatchlistEntry
+	err := r.collection.FindOne(ctx, bson.M{"user_id": entry.UserID},
+		options.FindOne().SetSort(bson.D{{Key: "position", Value: -1}})).Decode(&last)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+	entry.Position = last.Position + 1
+
+	result, err := r.collection.InsertOne(ctx, entry)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	if id, ok := result.InsertedID.(bson.ObjectID); ok {
+		entry.ID = id
+	}
+	return nil
+}
+
+func (r *MongoWatchlistRepository) Remove(ctx context.Context, userID, imdbID string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"user_id": userID, "imdb_id": imdbID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoWatchlistRepository) Reorder(ctx context.Context, userID string, imdbIDs []string) error {
+	writes := make([]mongo.WriteModel, 0, len(imdbIDs))
+	for i, imdbID := range imdbIDs {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"user_id": userID, "imdb_id": imdbID}).
+			SetUpdate(bson.M{"$set": bson.M{"position": i + 1}}))
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+	_, err := r.collection.BulkWrite(ctx, writes)
+	return err
+}
+
+func (r *MongoWatchlistRepository) Contains(ctx context.Context, userID string, imdbIDs []string) (map[string]bool, error) {
+	found := map[string]bool{}
+	if len(imdbIDs) == 0 {
+		return found, nil
+	}
+
+	filter := bson.M{"user_id": userID, "imdb_id": bson.M{"$in": imdbIDs}}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"imdb_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.WatchlistEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		found[entry.ImdbID] = true
+	}
+	return found, nil
+}
+
+func (r *MongoWatchlistRepository) DeleteAllForUser(ctx context.Context, userID string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+type WatchlistRepository interface {
+	// List returns the user's watchlist in order (position, then added_at)
+	List(ctx context.Context, userID string) ([]models.WatchlistEntry, error)
+	// Add appends the movie at the end of the watchlist (ErrDuplicate when it's on it already)
+	Add(ctx context.Context, entry *models.WatchlistEntry) error
+	Remove(ctx context.Context, userID, imdbID string) error
+	// Reorder sets the positions in the given order (1-based)
+	Reorder(ctx context.Context, userID string, imdbIDs []string) error
+	// Contains tells which of the imdb_ids are on the user's watchlist
+	Contains(ctx context.Context, userID string, imdbIDs []string) (map[string]bool, error)
+	DeleteAllForUser(ctx context.Context, userID string) (int64, error)
+}
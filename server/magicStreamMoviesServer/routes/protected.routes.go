@@ -2,16 +2,91 @@ package routes
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/ai"
 	controller "github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/controllers"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/jobs"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/mailer"
 	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/middleware"
-	"go.mongodb.org/mongo-driver/v2/mongo"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
 )
 
-func SetUpProtectedRoutes(router *gin.Engine,client *mongo.Client){
-	router.Use(middleware.AuthMiddleware())
+func SetUpProtectedRoutes(router *gin.Engine,store *repository.Store,ranker ai.SentimentRanker,rerankManager *jobs.RerankManager,sentimentWorker *jobs.SentimentWorker,recorder *jobs.PlaybackRecorder,revoker *utils.Revoker,guard *utils.LoginGuard,mail mailer.Mailer){
+	router.Use(middleware.AuthMiddleware(revoker,store.APIKeys,store.Users))
 
-	router.GET("/movie/:imdb_id",controller.GetSingleMovieHandler(client))
-	router.POST("/add-movie",controller.AddMovieHandler(client))
-	router.GET("/recommended-movies",controller.GetRecommendedMoviesHandler(client))
-	router.PATCH("/update-review/:imdb_id",controller.AdminReviewUpdateHandler(client))
-}	
\ No newline at end of file
+	// Required permission per endpoint (models.RolePermissions), everything else is open to every logged-in user (but not to API-keys)
+	can:=middleware.RequirePermission
+	own:=middleware.RejectAPIKey()
+
+	router.GET("/movie/:imdb_id",own,controller.GetSingleMovieHandler(store.Movies,store.Watchlist,store.WatchHistory,recorder))
+	router.POST("/movie/:imdb_id/playback",own,controller.RecordPlaybackHandler(store.Movies,recorder))
+	router.GET("/movie/:imdb_id/review",own,controller.GetMyReviewHandler(store.UserReviews))
+	router.PUT("/movie/:imdb_id/review",own,controller.PutMyReviewHandler(store.UserReviews,store.Movies,store.Users,sentimentWorker))
+	router.DELETE("/movie/:imdb_id/review",own,controller.DeleteMyReviewHandler(store.UserReviews,store.Movies))
+	router.POST("/add-movie",can(models.PermMovieCreate),controller.AddMovieHandler(store.Movies))
+	router.PUT("/movie/:imdb_id",can(models.PermMovieUpdate),controller.ReplaceMovieHandler(store.Movies))
+	router.PATCH("/movie/:imdb_id",can(models.PermMovieUpdate),controller.UpdateMovieHandler(store.Movies))
+	router.DELETE("/movie/:imdb_id",can(models.PermMovieDelete),controller.DeleteMovieHandler(store.Movies))
+	router.POST("/logout-all",own,controller.LogoutAllHandler(store.Sessions,revoker))
+	router.GET("/sessions",own,controller.GetSessionsHandler(store.Sessions))
+	router.DELETE("/sessions/:session_id",own,controller.RevokeSessionHandler(store.Sessions,revoker))
+	router.GET("/me",own,controller.GetMeHandler(store.Users))
+	router.PATCH("/me",own,controller.UpdateMeHandler(store.Users,store.Genres))
+	router.POST("/me/password",own,controller.ChangePasswordHandler(store.Users,store.Sessions,revoker,store.Audit))
+	router.GET("/me/export",own,controller.ExportMeHandler(store))
+	router.DELETE("/me",own,controller.DeleteMeHandler(store,revoker,guard,recorder))
+	router.GET("/me/watchlist",own,controller.GetWatchlistHandler(store.Watchlist,store.Movies))
+	router.POST("/me/watchlist",own,controller.AddToWatchlistHandler(store.Watchlist,store.Movies))
+	router.PUT("/me/watchlist/order",own,controller.ReorderWatchlistHandler(store.Watchlist,store.Movies))
+	router.DELETE("/me/watchlist/:imdb_id",own,controller.RemoveFromWatchlistHandler(store.Watchlist))
+	router.GET("/me/history",own,controller.GetWatchHistoryHandler(store.WatchHistory,store.Movies,recorder))
+	router.GET("/me/mfa",own,controller.GetMFAStatusHandler(store.Users))
+	router.POST("/me/mfa/enroll",own,controller.EnrollMFAHandler(store.Users))
+	router.POST("/me/mfa/activate",own,controller.ActivateMFAHandler(store.Users))
+	router.POST("/me/mfa/recovery-codes",own,controller.RegenerateRecoveryCodesHandler(store.Users))
+	router.DELETE("/me/mfa",own,controller.DisableMFAHandler(store.Users))
+	router.GET("/recommended-movies",own,controller.GetRecommendedMoviesHandler(store))
+	router.PATCH("/update-review/:imdb_id",can(models.PermReviewWrite),controller.AdminReviewUpdateHandler(store.Movies,store.Rankings,store.History,ranker))
+	router.GET("/admin/movies/:imdb_id/review-history",can(models.PermReviewHistory),controller.GetReviewHistoryHandler(store.History))
+	router.POST("/admin/movies/:imdb_id/review-history/:history_id/revert",can(models.PermReviewWrite),controller.RevertReviewHandler(store.Movies,store.History))
+
+	router.GET("/admin/reviews",can(models.PermReviewModerate),controller.GetModerationReviewsHandler(store.UserReviews))
+	router.POST("/admin/reviews/:review_id/hide",can(models.PermReviewModerate),controller.HideUserReviewHandler(store.UserReviews,store.Movies,store.Audit))
+	router.POST("/admin/reviews/:review_id/unhide",can(models.PermReviewModerate),controller.UnhideUserReviewHandler(store.UserReviews,store.Movies,store.Audit))
+	router.GET("/admin/sentiment-disagreements",can(models.PermReviewHistory),controller.GetSentimentDisagreementsHandler(store.Movies,store.Rankings))
+
+	router.POST("/admin/rerank-jobs",can(models.PermRerankRun),controller.StartRerankJobHandler(rerankManager))
+	router.GET("/admin/rerank-jobs",can(models.PermRerankRun),controller.GetRerankJobsHandler(store.Jobs))
+	router.GET("/admin/rerank-jobs/:job_id",can(models.PermRerankRun),controller.GetRerankJobHandler(store.Jobs))
+	router.POST("/admin/rerank-jobs/:job_id/cancel",can(models.PermRerankRun),controller.CancelRerankJobHandler(rerankManager))
+
+	router.GET("/admin/users",can(models.PermUserManage),controller.GetUsersHandler(store.Users))
+	router.GET("/admin/users/:user_id",can(models.PermUserManage),controller.GetUserHandler(store.Users))
+	router.PUT("/admin/users/:user_id/role",can(models.PermUserManage),controller.UpdateUserRoleHandler(store.Users,revoker,store.Audit))
+	router.POST("/admin/users/:user_id/disable",can(models.PermUserManage),controller.DisableUserHandler(store.Users,store.Sessions,revoker,store.Audit))
+	router.POST("/admin/users/:user_id/enable",can(models.PermUserManage),controller.EnableUserHandler(store.Users,store.Audit))
+	router.POST("/admin/users/:user_id/password-reset",can(models.PermUserManage),controller.ForcePasswordResetHandler(store,revoker,mail))
+	router.POST("/admin/users/:user_id/sign-out",can(models.PermUserManage),controller.ForceSignOutHandler(store.Users,store.Sessions,revoker,store.Audit))
+	router.POST("/admin/users/:user_id/unlock",can(models.PermUserManage),controller.UnlockUserHandler(store.Users,guard,store.Audit))
+	router.GET("/admin/login-locks",can(models.PermUserManage),controller.GetLoginLocksHandler(guard))
+	router.DELETE("/admin/login-locks/ip/:ip",can(models.PermUserManage),controller.UnlockIPHandler(guard,store.Audit))
+	router.GET("/admin/audit-events",can(models.PermAuditRead),controller.GetAuditEventsHandler(store.Audit))
+
+	router.POST("/admin/api-keys",can(models.PermAPIKeyManage),controller.CreateAPIKeyHandler(store.APIKeys,store.Users))
+	router.GET("/admin/api-keys",can(models.PermAPIKeyManage),controller.GetAPIKeysHandler(store.APIKeys))
+	router.PUT("/admin/api-keys/:key_id/scopes",can(models.PermAPIKeyManage),controller.UpdateAPIKeyScopesHandler(store.APIKeys,store.Users))
+	router.DELETE("/admin/api-keys/:key_id",can(models.PermAPIKeyManage),controller.RevokeAPIKeyHandler(store.APIKeys))
+
+	router.GET("/admin/genres",can(models.PermCatalogManage),controller.GetGenreEntriesHandler(store.Genres))
+	router.POST("/admin/genres",can(models.PermCatalogManage),controller.AddGenreHandler(store.Genres))
+	router.PUT("/admin/genres/order",can(models.PermCatalogManage),controller.ReorderGenresHandler(store.Genres))
+	router.PATCH("/admin/genres/:genre_id",can(models.PermCatalogManage),controller.UpdateGenreHandler(store.Genres,store.Movies,store.Users))
+	router.DELETE("/admin/genres/:genre_id",can(models.PermCatalogManage),controller.RetireGenreHandler(store.Genres))
+
+	router.GET("/admin/rankings",can(models.PermCatalogManage),controller.GetRankingEntriesHandler(store.Rankings))
+	router.POST("/admin/rankings",can(models.PermCatalogManage),controller.AddRankingHandler(store.Rankings))
+	router.PUT("/admin/rankings/order",can(models.PermCatalogManage),controller.ReorderRankingsHandler(store.Rankings))
+	router.PATCH("/admin/rankings/:ranking_value",can(models.PermCatalogManage),controller.UpdateRankingHandler(store.Rankings,store.Movies,store.UserReviews))
+	router.DELETE("/admin/rankings/:ranking_value",can(models.PermCatalogManage),controller.RetireRankingHandler(store.Rankings))
+}
\ No newline at end of file
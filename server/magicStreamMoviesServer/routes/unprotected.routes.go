@@ -3,14 +3,27 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	controller "github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/controllers"
-	"go.mongodb.org/mongo-driver/v2/mongo"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/keystore"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/mailer"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/middleware"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/utils"
 )
 
-func SetUpUnProtectedRoutes(router *gin.Engine,client *mongo.Client){
-   	router.GET("/movies",controller.GetMoviesHandler(client))
-	router.POST("/register",controller.RegisterUserHandler(client))
-	router.POST("/login",controller.LoginUserHandler(client))
-	router.POST("/logout",controller.LogoutUserHandler(client))
-	router.GET("/genres",controller.GetGenresHandler(client))
-	router.POST("/refresh", controller.RefreshTokenHandler(client))
-}	
\ No newline at end of file
+func SetUpUnProtectedRoutes(router *gin.Engine,store *repository.Store,revoker *utils.Revoker,keys *keystore.Store,mail mailer.Mailer,guard *utils.LoginGuard){
+   	router.GET("/movies",middleware.OptionalAuthMiddleware(revoker,store.APIKeys,store.Users),controller.GetMoviesHandler(store.Movies,store.Watchlist))
+	router.GET("/movie/:imdb_id/reviews",controller.GetUserReviewsHandler(store.UserReviews))
+	router.GET("/movie/:imdb_id/sentiment",controller.GetMovieSentimentHandler(store.Movies,store.Rankings))
+	router.GET("/movies/search",controller.SearchMoviesHandler(store.Movies))
+	router.POST("/register",controller.RegisterUserHandler(store.Users,store.ActionTokens,mail))
+	router.POST("/login",controller.LoginUserHandler(store.Users,store.Sessions,store.ActionTokens,guard))
+	router.POST("/login/mfa",controller.LoginMFAHandler(store.Users,store.Sessions,store.ActionTokens,guard))
+	router.POST("/logout",controller.LogoutUserHandler(store.Sessions,revoker))
+	router.GET("/genres",controller.GetGenresHandler(store.Genres))
+	router.POST("/refresh", controller.RefreshTokenHandler(store.Users, store.Sessions, revoker))
+	router.POST("/verify-email/request",controller.RequestEmailVerificationHandler(store.Users,store.ActionTokens,mail))
+	router.POST("/verify-email",controller.VerifyEmailHandler(store.Users,store.ActionTokens))
+	router.POST("/password-reset/request",controller.RequestPasswordResetHandler(store.Users,store.ActionTokens,mail))
+	router.POST("/password-reset",controller.ResetPasswordHandler(store.Users,store.ActionTokens,store.Sessions,revoker))
+	router.GET("/.well-known/jwks.json",controller.JWKSHandler(keys))
+}	
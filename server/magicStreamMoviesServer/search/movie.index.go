@@ -0,0 +1,255 @@
+package search
+
+// In-process movie search index 🔎
+// Used for prefix/typo-tolerant title matching, and as a full fallback when the Mongo text-index is missing.
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/textdist"
+)
+
+// Score weights (same order of importance as the Mongo text-index weights)
+const (
+	titleExactScore   = 10.0
+	titlePrefixScore  = 6.0
+	titleTypoScore    = 4.0
+	titlePhraseScore  = 15.0
+	genreScore        = 5.0
+	reviewExactScore  = 2.0
+	reviewPrefixScore = 1.0
+)
+
+type Result struct {
+	Movie   models.Movie `json:"movie"`
+	Score   float64      `json:"score"`
+	Snippet string       `json:"snippet,omitempty"`
+}
+
+type document struct {
+	movie        models.Movie
+	title        string
+	titleTokens  []string
+	genreTokens  []string
+	reviewTokens []string
+}
+
+type Index struct {
+	docs []document
+}
+
+func NewIndex(movies []models.Movie) *Index {
+	index := &Index{docs: make([]document, 0, len(movies))}
+	for _, movie := range movies {
+		var genreTokens []string
+		for _, genre := range movie.Genre {
+			genreTokens = append(genreTokens, Tokenize(genre.GenreName)...)
+		}
+		index.docs = append(index.docs, document{
+			movie:        movie,
+			title:        strings.ToLower(strings.TrimSpace(movie.Title)),
+			titleTokens:  Tokenize(movie.Title),
+			genreTokens:  genreTokens,
+			reviewTokens: Tokenize(movie.AdminReview),
+		})
+	}
+	return index
+}
+
+// Tokenize lower-cases the text and splits it on anything that isn't a letter or a digit
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Search over title, genre and admin-review
+func (index *Index) Search(query string, limit int) []Result {
+	return index.search(query, limit, true)
+}
+
+// TitleMatches only looks at the titles (prefix & typo-tolerant)
+func (index *Index) TitleMatches(query string, limit int) []Result {
+	return index.search(query, limit, false)
+}
+
+func (index *Index) search(query string, limit int, allFields bool) []Result {
+	terms := Tokenize(query)
+	if len(terms) == 0 {
+		return []Result{}
+	}
+	phrase := strings.Join(terms, " ")
+
+	var results []Result
+	for _, doc := range index.docs {
+		score := 0.0
+		if strings.HasPrefix(doc.title, phrase) {
+			score += titlePhraseScore
+		}
+		for _, term := range terms {
+			score += scoreTitleTerm(term, doc.titleTokens)
+			if allFields {
+				score += scoreTerm(term, doc.genreTokens, genreScore, genreScore)
+				score += scoreTerm(term, doc.reviewTokens, reviewExactScore, reviewPrefixScore)
+			}
+		}
+		if score == 0 {
+			continue
+		}
+		results = append(results, Result{
+			Movie:   doc.movie,
+			Score:   score,
+			Snippet: Highlight(doc.movie.AdminReview, terms),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+func scoreTitleTerm(term string, tokens []string) float64 {
+	best := 0.0
+	for _, token := range tokens {
+		switch {
+		case token == term:
+			return titleExactScore
+		case strings.HasPrefix(token, term):
+			best = max(best, titlePrefixScore)
+		case withinTypoDistance(term, token):
+			best = max(best, titleTypoScore)
+		}
+	}
+	return best
+}
+
+func scoreTerm(term string, tokens []string, exact, prefix float64) float64 {
+	best := 0.0
+	for _, token := range tokens {
+		if token == term {
+			return exact
+		}
+		if strings.HasPrefix(token, term) {
+			best = prefix
+		}
+	}
+	return best
+}
+
+// Short words must match exactly, longer ones may have 1 (or 2) typos
+func withinTypoDistance(term, token string) bool {
+	allowed := 0
+	switch n := len([]rune(term)); {
+	case n >= 8:
+		allowed = 2
+	case n >= 4:
+		allowed = 1
+	}
+	if allowed == 0 {
+		return false
+	}
+	return textdist.Levenshtein(term, token, allowed) <= allowed
+}
+
+const snippetRadius = 80
+
+// Highlight returns a snippet of the text around the first matching term, with matches wrapped in <mark></mark>
+func Highlight(text string, terms []string) string {
+	if text == "" || len(terms) == 0 {
+		return ""
+	}
+
+	runes := []rune(text)
+	type span struct{ start, end int }
+	var matches []span
+
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		isWord := i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]))
+		if isWord && start == -1 {
+			start = i
+		}
+		if !isWord && start != -1 {
+			word := strings.ToLower(string(runes[start:i]))
+			for _, term := range terms {
+				if strings.HasPrefix(word, term) {
+					matches = append(matches, span{start, i})
+					break
+				}
+			}
+			start = -1
+		}
+	}
+	if len(matches) == 0 {
+		return ""
+	}
+
+	from := max(0, matches[0].start-snippetRadius)
+	to := min(len(runes), matches[0].end+snippetRadius)
+
+	var sb strings.Builder
+	if from > 0 {
+		sb.WriteString("…")
+	}
+	pos := from
+	for _, m := range matches {
+		if m.start < from || m.end > to {
+			continue
+		}
+		sb.WriteString(html.EscapeString(string(runes[pos:m.start])))
+		sb.WriteString("<mark>")
+		sb.WriteString(html.EscapeString(string(runes[m.start:m.end])))
+		sb.WriteString("</mark>")
+		pos = m.end
+	}
+	sb.WriteString(html.EscapeString(string(runes[pos:to])))
+	if to < len(runes) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
+
+//! Cached index (rebuilt lazily, after the TTL or after a movie write)
+
+const indexTTL = 5 * time.Minute
+
+var (
+	cacheMu  sync.Mutex
+	cached   *Index
+	cachedAt time.Time
+)
+
+// CachedIndex returns the cached index, or builds a fresh one with the loader
+func CachedIndex(loader func() ([]models.Movie, error)) (*Index, error) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	if cached != nil && time.Since(cachedAt) < indexTTL {
+		return cached, nil
+	}
+
+	movies, err := loader()
+	if err != nil {
+		return nil, err
+	}
+	cached = NewIndex(movies)
+	cachedAt = time.Now()
+	return cached, nil
+}
+
+// Invalidate drops the cached index (call after adding/updating/deleting movies)
+func Invalidate() {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	cached = nil
+}
@@ -0,0 +1,25 @@
+package textdist
+
+import "testing"
+
+func TestLevenshtein(t *testing.T) {
+	for _, tc := range []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"good", "good", 2, 0},
+		{"excelent", "excellent", 2, 1},
+		{"terible", "terrible", 2, 1},
+		{"okay", "oaky", 2, 2},
+		{"café", "cafe", 2, 1}, // runes, not bytes
+		{"", "bad", 3, 3},
+		{"good", "terrible", 2, 3}, // lengths too far apart
+		{"abcdef", "uvwxyz", 2, 3}, // gives up at limit+1
+		{"masterpiece", "masterpice", 0, 1},
+	} {
+		if got := Levenshtein(tc.a, tc.b, tc.limit); got != tc.want {
+			t.Errorf("Levenshtein(%q, %q, %d) = %d, want %d", tc.a, tc.b, tc.limit, got, tc.want)
+		}
+	}
+}
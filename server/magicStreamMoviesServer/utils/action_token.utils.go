@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/keystore"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+)
+
+// Action-tokens are the links we e-mail (verify address / reset password) & the MFA log-in challenge: signed like the auth-tokens,
+// plus a row per jti so a link works only once & asking for a new one kills the old ones
+type ActionClaims struct {
+	UserId  string
+	Purpose string
+	jwt.RegisteredClaims
+}
+
+var ErrInvalidActionToken = errors.New("invalid or expired link")
+
+const (
+	EmailVerificationLifetime = 48*time.Hour
+	PasswordResetLifetime     = time.Hour
+	MFAChallengeLifetime      = 5*time.Minute
+)
+
+func ActionTokenLifetime(purpose string)time.Duration{
+	switch purpose{
+	case models.ActionResetPassword:
+		return PasswordResetLifetime
+	case models.ActionMFALogin:
+		return MFAChallengeLifetime
+	}
+	return EmailVerificationLifetime
+}
+
+// Issues a new action-token for the user, the user's earlier ones for the same purpose stop working
+func GenerateActionToken(c context.Context,tokens repository.ActionTokenRepository,userId,purpose string)(string,error){
+	now:=time.Now()
+	claims:=&ActionClaims{
+		UserId: userId,
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID: NewTokenID(),
+			Issuer:"MagikStream",
+			IssuedAt: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ActionTokenLifetime(purpose))),
+		},
+	}
+
+	signed,err:=signToken(claims)
+	if err!=nil{
+		return "",err
+	}
+
+	if err:=tokens.InvalidateAll(c,userId,purpose,now); err!=nil{
+		return "",err
+	}
+	err=tokens.Insert(c,&models.ActionToken{
+		TokenID: claims.ID,
+		UserID: userId,
+		Purpose: purpose,
+		CreatedAt: now,
+		ExpiresAt: claims.ExpiresAt.Time,
+	})
+	if err!=nil{
+		return "",err
+	}
+	return signed,nil
+}
+
+// Checks the signature/purpose/expiry & uses the token up, ErrInvalidActionToken for anything that doesn't pass
+func ConsumeActionToken(c context.Context,tokens repository.ActionTokenRepository,tokenStr,purpose string)(*ActionClaims,error){
+	claims:=&ActionClaims{}
+	token,err:=jwt.ParseWithClaims(tokenStr,claims,verificationKey,jwt.WithValidMethods([]string{keystore.AlgRS256,keystore.AlgEdDSA}))
+	if err!=nil || !token.Valid || claims.Purpose!=purpose || claims.ID==""{
+		return nil,ErrInvalidActionToken
+	}
+
+	_,err=tokens.Consume(c,claims.ID,purpose,time.Now())
+	if errors.Is(err,repository.ErrNotFound){
+		return nil,ErrInvalidActionToken
+	}
+	if err!=nil{
+		return nil,err
+	}
+	return claims,nil
+}
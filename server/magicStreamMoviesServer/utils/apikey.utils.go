@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+)
+
+// NewAPIKey returns a fresh key ("msk_" + 32 random bytes) and its displayable prefix
+func NewAPIKey() (string, string) {
+	b := make([]byte, 32)
+	rand.Read(b)
+	key := models.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return key, key[:len(models.APIKeyPrefix)+8]
+}
+
+// HashAPIKey is what gets stored & looked up. The keys are random, so a plain SHA-256 is enough (no bcrypt)
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,18 @@
+package utils
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+)
+
+// RecordAudit appends an audit-event. What it records already happened, so a failure is only logged
+func RecordAudit(ctx context.Context, audit repository.AuditRepository, event *models.AuditEvent) {
+	event.CreatedAt = time.Now()
+	if err := audit.Append(ctx, event); err != nil {
+		log.Printf("⚠️ ERROR recording audit-event %s --- %v", event.Type, err)
+	}
+}
@@ -0,0 +1,247 @@
+package utils
+
+// Brute-force protection of the log-in 🚪
+// Failed log-ins are counted per account (e-mail, whether it exists or not) and per client-IP. After a few free
+// attempts every further one has to wait exponentially longer, and at the limit the account/IP is locked for a while
+// (doubling with every failure past it). A successful log-in resets the account's counter, never the IP's.
+//
+// Env:
+//   LOGIN_MAX_FAILURES       failures of an account until it's locked (default 10)
+//   LOGIN_IP_MAX_FAILURES    failures from one IP until it's locked (default 50)
+//   LOGIN_LOCKOUT_MINUTES    first lockout (default 15), the counters are forgotten after 24h without failures
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+)
+
+const (
+	loginFailureWindow = 24 * time.Hour
+	loginBaseDelay     = time.Second
+	maxLockout         = 24 * time.Hour
+)
+
+type loginLimit struct {
+	free int // failures without any delay
+	max  int // failures until the lockout
+}
+
+type LoginGuard struct {
+	attempts repository.LoginAttemptRepository
+	audit    repository.AuditRepository
+	account  loginLimit
+	ip       loginLimit
+	lockout  time.Duration
+}
+
+func envInt(name string, fallback int) int {
+	if val, err := strconv.Atoi(os.Getenv(name)); err == nil && val > 0 {
+		return val
+	}
+	return fallback
+}
+
+func NewLoginGuard(attempts repository.LoginAttemptRepository, audit repository.AuditRepository) *LoginGuard {
+	accountMax := envInt("LOGIN_MAX_FAILURES", 10)
+	ipMax := envInt("LOGIN_IP_MAX_FAILURES", 50)
+	return &LoginGuard{
+		attempts: attempts,
+		audit:    audit,
+		account:  loginLimit{free: min(3, accountMax), max: accountMax},
+		ip:       loginLimit{free: min(10, ipMax), max: ipMax},
+		lockout:  time.Duration(envInt("LOGIN_LOCKOUT_MINUTES", 15)) * time.Minute,
+	}
+}
+
+// Account-counters are kept per (normalised) e-mail
+func normaliseEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func (g *LoginGuard) limit(kind string) loginLimit {
+	if kind == models.LoginAttemptIP {
+		return g.ip
+	}
+	return g.account
+}
+
+// How long after its last failure the next attempt has to wait: 1s, 2s, 4s, ... once the free attempts are used up
+func (g *LoginGuard) backoff(kind string, failures int) time.Duration {
+	over := failures - g.limit(kind).free
+	if over <= 0 {
+		return 0
+	}
+	return min(loginBaseDelay<<min(over-1, 20), g.lockout)
+}
+
+// Lockout length for the n-th failure at/past the limit: lockout, 2x, 4x, ... (max 24h)
+func (g *LoginGuard) lockoutFor(kind string, failures int) time.Duration {
+	over := failures - g.limit(kind).max
+	if over < 0 {
+		return 0
+	}
+	return min(g.lockout<<min(over, 10), maxLockout)
+}
+
+// wait is how long the key is still blocked (0 = not at all), and its current failures
+func (g *LoginGuard) wait(ctx context.Context, key string, now time.Time) (time.Duration, int, error) {
+	attempt, err := g.attempts.Find(ctx, key, now)
+	if errors.Is(err, repository.ErrNotFound) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	until := attempt.LastFailureAt.Add(g.backoff(attempt.Kind, attempt.Failures))
+	if attempt.LockedUntil != nil && attempt.LockedUntil.After(until) {
+		until = *attempt.LockedUntil
+	}
+	return max(until.Sub(now), 0), attempt.Failures, nil
+}
+
+var loginTargets = []string{models.LoginAttemptAccount, models.LoginAttemptIP}
+
+func loginSubject(kind, email, ip string) string {
+	if kind == models.LoginAttemptIP {
+		return ip
+	}
+	return normaliseEmail(email)
+}
+
+// LoginReservation is a log-in attempt counted in advance (as a failure), before the password is looked at
+type LoginReservation struct {
+	guard    *LoginGuard
+	ip       string
+	attempts []*models.LoginAttempt // the account's & the IP's counters, with this attempt
+}
+
+// Reserve is called before the password is even looked at: >0 means try again after that long.
+// Otherwise the attempt is counted right away (compare-and-swap on the counters), so concurrent attempts can't all
+// get past the check before the first failure is recorded. Fail or Release the reservation once the outcome is known
+func (g *LoginGuard) Reserve(ctx context.Context, email, ip string) (*LoginReservation, time.Duration, error) {
+	reservation := &LoginReservation{guard: g, ip: ip}
+	for _, kind := range loginTargets {
+		attempt, wait, err := g.reserve(ctx, kind, loginSubject(kind, email, ip))
+		if err == nil && wait == 0 {
+			reservation.attempts = append(reservation.attempts, attempt)
+			continue
+		}
+
+		if releaseErr := reservation.Release(ctx); releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+		return nil, wait, err
+	}
+	return reservation, 0, nil
+}
+
+func (g *LoginGuard) reserve(ctx context.Context, kind, subject string) (*models.LoginAttempt, time.Duration, error) {
+	key := repository.LoginAttemptKey(kind, subject)
+	for range 5 {
+		now := time.Now()
+		wait, seen, err := g.wait(ctx, key, now)
+		if err != nil || wait > 0 {
+			return nil, wait, err
+		}
+
+		attempt, err := g.attempts.RecordFailure(ctx, kind, subject, seen, now, now.Add(loginFailureWindow))
+		if errors.Is(err, repository.ErrConflict) {
+			continue
+		}
+		return attempt, 0, err
+	}
+	// Lost the race every time: that many concurrent attempts are refused
+	return nil, loginBaseDelay, nil
+}
+
+// Fail: the reserved attempt was a failed log-in (wrong password, unknown e-mail). It's counted already,
+// what reached its limit gets locked. userId is empty for unknown e-mails
+func (r *LoginReservation) Fail(ctx context.Context, userId string) error {
+	now := time.Now()
+	for _, attempt := range r.attempts {
+		if err := r.guard.lockIfOverLimit(ctx, attempt, r.ip, userId, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Release: the reserved attempt wasn't a failure (right password), it no longer counts
+func (r *LoginReservation) Release(ctx context.Context) error {
+	for _, attempt := range r.attempts {
+		if err := r.guard.attempts.Release(ctx, attempt.Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Fail counts a failed log-in that wasn't reserved (a wrong MFA code, after the password's attempt was released)
+// for the account & the IP, and locks whichever reached its limit
+func (g *LoginGuard) Fail(ctx context.Context, email, ip, userId string) error {
+	now := time.Now()
+	for _, kind := range loginTargets {
+		attempt, err := g.attempts.RecordFailure(ctx, kind, loginSubject(kind, email, ip), -1, now, now.Add(loginFailureWindow))
+		if err != nil {
+			return err
+		}
+		if err := g.lockIfOverLimit(ctx, attempt, ip, userId, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *LoginGuard) lockIfOverLimit(ctx context.Context, attempt *models.LoginAttempt, ip, userId string, now time.Time) error {
+	lockout := g.lockoutFor(attempt.Kind, attempt.Failures)
+	if lockout == 0 {
+		return nil
+	}
+	until := now.Add(lockout)
+	if err := g.attempts.Lock(ctx, attempt.Key, until); err != nil {
+		return err
+	}
+
+	event := &models.AuditEvent{
+		Type:      models.AuditLoginLockout,
+		IPAddress: ip,
+		Details: map[string]string{
+			"kind":         attempt.Kind,
+			"subject":      attempt.Subject,
+			"failures":     strconv.Itoa(attempt.Failures),
+			"locked_until": until.UTC().Format(time.RFC3339),
+		},
+	}
+	if attempt.Kind == models.LoginAttemptAccount {
+		event.TargetUserID = userId
+	}
+	RecordAudit(ctx, g.audit, event)
+	return nil
+}
+
+// Succeed resets the account's counter after a complete log-in
+func (g *LoginGuard) Succeed(ctx context.Context, email string) error {
+	return g.attempts.Clear(ctx, repository.LoginAttemptKey(models.LoginAttemptAccount, normaliseEmail(email)))
+}
+
+// Unlock lifts an account's lock (and forgets its failures), for the admins
+func (g *LoginGuard) Unlock(ctx context.Context, email string) error {
+	return g.attempts.Clear(ctx, repository.LoginAttemptKey(models.LoginAttemptAccount, normaliseEmail(email)))
+}
+
+// UnlockIP lifts the lock of a client-IP
+func (g *LoginGuard) UnlockIP(ctx context.Context, ip string) error {
+	return g.attempts.Clear(ctx, repository.LoginAttemptKey(models.LoginAttemptIP, ip))
+}
+
+func (g *LoginGuard) ListLocked(ctx context.Context) ([]models.LoginAttempt, error) {
+	return g.attempts.ListLocked(ctx, time.Now())
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+)
+
+func TestReserveLetsOnlyThePermittedConcurrentAttemptsThrough(t *testing.T) {
+	store := repository.NewMemoryStore()
+	guard := NewLoginGuard(store.LoginAttempts, store.Audit)
+	ctx := context.Background()
+
+	// Every attempt is still "comparing the password" while the others arrive
+	var reserved atomic.Int32
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reservation, wait, err := guard.Reserve(ctx, "alice@example.com", "10.0.0.1")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if wait == 0 && reservation != nil {
+				reserved.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The free attempts, plus the one right after them that has no backoff yet
+	if want := int32(guard.account.free + 1); reserved.Load() != want {
+		t.Fatalf("%d attempts got through, want %d", reserved.Load(), want)
+	}
+}
+
+func TestReleaseTakesBackTheAttempt(t *testing.T) {
+	store := repository.NewMemoryStore()
+	guard := NewLoginGuard(store.LoginAttempts, store.Audit)
+	ctx := context.Background()
+
+	for range 20 {
+		reservation, wait, err := guard.Reserve(ctx, "alice@example.com", "10.0.0.1")
+		if err != nil || wait > 0 {
+			t.Fatalf("got (%v, %v), want a reservation", wait, err)
+		}
+		if err := reservation.Release(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, key := range []string{
+		repository.LoginAttemptKey(models.LoginAttemptAccount, "alice@example.com"),
+		repository.LoginAttemptKey(models.LoginAttemptIP, "10.0.0.1"),
+	} {
+		if attempt, err := store.LoginAttempts.Find(ctx, key, time.Now()); err == nil && attempt.Failures != 0 {
+			t.Fatalf("%s: %d failures left, want 0", key, attempt.Failures)
+		}
+	}
+}
+
+func TestFailedReservationsLockTheAccount(t *testing.T) {
+	t.Setenv("LOGIN_MAX_FAILURES", "2")
+	store := repository.NewMemoryStore()
+	guard := NewLoginGuard(store.LoginAttempts, store.Audit)
+	ctx := context.Background()
+
+	for range 2 {
+		reservation, wait, err := guard.Reserve(ctx, "alice@example.com", "10.0.0.1")
+		if err != nil || wait > 0 {
+			t.Fatalf("got (%v, %v), want a reservation", wait, err)
+		}
+		if err := reservation.Fail(ctx, "user-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	locked, err := guard.ListLocked(ctx)
+	if err != nil || len(locked) != 1 || locked[0].Kind != models.LoginAttemptAccount {
+		t.Fatalf("got %+v (%v), want the account locked", locked, err)
+	}
+	if _, wait, _ := guard.Reserve(ctx, "Alice@Example.com ", "10.0.0.2"); wait == 0 {
+		t.Fatal("locked account got a reservation")
+	}
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 appendix B (SHA1), the last 6 of the 8 digits
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestValidateTOTPAcceptsTheRFCVectors(t *testing.T) {
+	for _, tc := range []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	} {
+		step, ok := ValidateTOTP(rfcSecret, tc.code, time.Unix(tc.unix, 0))
+		if !ok || step != tc.unix/totpPeriod {
+			t.Fatalf("%d: got (%d, %v), want step %d", tc.unix, step, ok, tc.unix/totpPeriod)
+		}
+	}
+}
+
+func TestValidateTOTPSkew(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	if _, ok := ValidateTOTP(rfcSecret, "081 804", now.Add(totpPeriod*time.Second)); !ok {
+		t.Fatal("one step of drift (and a space) should be accepted")
+	}
+	if _, ok := ValidateTOTP(rfcSecret, "081804", now.Add(2*totpPeriod*time.Second)); ok {
+		t.Fatal("two steps of drift should be refused")
+	}
+	if _, ok := ValidateTOTP(rfcSecret, "081805", now); ok {
+		t.Fatal("a wrong code should be refused")
+	}
+	if _, ok := ValidateTOTP(strings.ToLower(rfcSecret), "081804", now); !ok {
+		t.Fatal("the secret's case shouldn't matter")
+	}
+}
+
+func TestNewTOTPSecretValidatesItsOwnCodes(t *testing.T) {
+	secret := NewTOTPSecret()
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil || len(key) != 20 {
+		t.Fatalf("secret %q: %d bytes, %v", secret, len(key), err)
+	}
+	now := time.Now()
+	if _, ok := ValidateTOTP(secret, totpCode(key, now.Unix()/totpPeriod), now); !ok {
+		t.Fatal("own code refused")
+	}
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestPageCursorRoundTrip(t *testing.T) {
+	token, err := EncodePageCursor(PageCursor{Sort: "-ranking", Value: 3, ID: "6ad4785d7a18a02fb6cbdeb2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cursor, err := DecodePageCursor(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// JSON numbers come back as float64, the handlers check the type against the sort
+	if cursor.Sort != "-ranking" || cursor.Value != float64(3) || cursor.ID != "6ad4785d7a18a02fb6cbdeb2" {
+		t.Fatalf("got %+v", cursor)
+	}
+}
+
+func TestDecodePageCursorRejectsGarbage(t *testing.T) {
+	for name, token := range map[string]string{
+		"not base64":  "%%%",
+		"not JSON":    base64.RawURLEncoding.EncodeToString([]byte("title")),
+		"without _id": base64.RawURLEncoding.EncodeToString([]byte(`{"s":"title","v":"Alpha"}`)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if cursor, err := DecodePageCursor(token); err == nil {
+				t.Fatalf("got %+v, want an error", cursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+)
+
+// Revoker is the access-token denylist: the revocations-store, cached in memory for AuthMiddleware.
+// Other instances' revocations are picked up on the next sync (REVOCATION_SYNC_SECONDS, default 30).
+type Revoker struct {
+	repo      repository.RevocationRepository
+	syncEvery time.Duration
+
+	syncMu   sync.Mutex
+	mu       sync.RWMutex
+	tokens   map[string]time.Time // jti -> expires_at
+	users    map[string]time.Time // user_id -> tokens issued before this are revoked
+	sessions map[string]time.Time // session_id -> expires_at
+	lastSync time.Time
+}
+
+func NewRevoker(repo repository.RevocationRepository) *Revoker {
+	syncEvery := 30 * time.Second
+	if val, err := strconv.Atoi(os.Getenv("REVOCATION_SYNC_SECONDS")); err == nil && val > 0 {
+		syncEvery = time.Duration(val) * time.Second
+	}
+
+	return &Revoker{
+		repo:      repo,
+		syncEvery: syncEvery,
+		tokens:    map[string]time.Time{},
+		users:     map[string]time.Time{},
+		sessions:  map[string]time.Time{},
+	}
+}
+
+// Sync reloads the cache from the store
+func (r *Revoker) Sync(ctx context.Context) error {
+	now := time.Now()
+	revocations, err := r.repo.ListActive(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	tokens := map[string]time.Time{}
+	users := map[string]time.Time{}
+	sessions := map[string]time.Time{}
+	for _, revocation := range revocations {
+		switch revocation.Kind {
+		case models.RevocationToken:
+			tokens[revocation.TokenID] = revocation.ExpiresAt
+		case models.RevocationUser:
+			if revocation.IssuedBefore.After(users[revocation.UserID]) {
+				users[revocation.UserID] = revocation.IssuedBefore
+			}
+		case models.RevocationSession:
+			sessions[revocation.SessionID] = revocation.ExpiresAt
+		}
+	}
+
+	r.mu.Lock()
+	r.tokens, r.users, r.sessions, r.lastSync = tokens, users, sessions, now
+	r.mu.Unlock()
+	return nil
+}
+
+// One request syncs a stale cache, the others keep using it meanwhile. On errors the stale cache is kept
+func (r *Revoker) syncIfStale(ctx context.Context) {
+	r.mu.RLock()
+	stale := time.Since(r.lastSync) > r.syncEvery
+	r.mu.RUnlock()
+	if !stale || !r.syncMu.TryLock() {
+		return
+	}
+	defer r.syncMu.Unlock()
+
+	if err := r.Sync(ctx); err != nil {
+		log.Println("⚠️ ERROR syncing token-revocations ---", err)
+	}
+}
+
+// RevokeToken denylists a single access-token until it expires
+func (r *Revoker) RevokeToken(ctx context.Context, claims *SignedDetails, reason string) error {
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+
+	revocation := &models.Revocation{
+		Kind:      models.RevocationToken,
+		TokenID:   claims.ID,
+		UserID:    claims.UserId,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+		ExpiresAt: claims.ExpiresAt.Time,
+	}
+	if err := r.repo.Insert(ctx, revocation); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.tokens[revocation.TokenID] = revocation.ExpiresAt
+	r.mu.Unlock()
+	return nil
+}
+
+// RevokeUser revokes every access-token of the user issued up to now. The cutoff has the iat's (ms) precision,
+// so a token issued right after it (e.g. the new one of a password change) isn't caught
+func (r *Revoker) RevokeUser(ctx context.Context, userID, reason string) error {
+	now := time.Now().Truncate(time.Millisecond)
+	revocation := &models.Revocation{
+		Kind:         models.RevocationUser,
+		UserID:       userID,
+		IssuedBefore: now,
+		Reason:       reason,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(AccessTokenLifetime),
+	}
+	if err := r.repo.Insert(ctx, revocation); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	if now.After(r.users[userID]) {
+		r.users[userID] = now
+	}
+	r.mu.Unlock()
+	return nil
+}
+
+// RevokeSession revokes every access-token of a session (device), until the last one it issued has expired
+func (r *Revoker) RevokeSession(ctx context.Context, sessionID, userID, reason string) error {
+	if sessionID == "" {
+		return nil
+	}
+
+	now := time.Now()
+	revocation := &models.Revocation{
+		Kind:      models.RevocationSession,
+		SessionID: sessionID,
+		UserID:    userID,
+		Reason:    reason,
+		CreatedAt: now,
+		ExpiresAt: now.Add(AccessTokenLifetime),
+	}
+	if err := r.repo.Insert(ctx, revocation); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.sessions[sessionID] = revocation.ExpiresAt
+	r.mu.Unlock()
+	return nil
+}
+
+// IsRevoked reports whether a (valid) access-token was revoked
+func (r *Revoker) IsRevoked(ctx context.Context, claims *SignedDetails) bool {
+	r.syncIfStale(ctx)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.tokens[claims.ID]; ok && claims.ID != "" {
+		return true
+	}
+	if _, ok := r.sessions[claims.SessionId]; ok && claims.SessionId != "" {
+		return true
+	}
+	cutoff, ok := r.users[claims.UserId]
+	if !ok {
+		return false
+	}
+	if claims.IssuedAt == nil {
+		return true
+	}
+	// iat is parsed back through a float64 and can come out 1ms early (x.656 -> x.6559999 -> x.655)
+	return claims.IssuedAt.Time.Add(time.Millisecond).Before(cutoff)
+}
@@ -1,17 +1,18 @@
 package utils
 
 import (
-	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	jwt "github.com/golang-jwt/jwt/v5"
-	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/database"
-	"go.mongodb.org/mongo-driver/v2/bson"
-	"go.mongodb.org/mongo-driver/v2/mongo"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/keystore"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
 )
 
 type SignedDetails struct {
@@ -20,20 +21,117 @@ type SignedDetails struct {
 	LastName  string
 	Role      string
 	UserId    string
+	SessionId string // session (refresh-token family), the jti of a refresh-token is its RegisteredClaims.ID
+	TokenUse  string // access/refresh, both are signed with the same keys
 	jwt.RegisteredClaims
 }
 
-var JWT_SECRET_KEY string
-var JWT_REFRESH_SECRET_KEY string
+const (
+	TokenUseAccess  = "access"
+	TokenUseRefresh = "refresh"
+)
+
+// Token lifetimes (a refresh-token's is also how long an idle session lives)
+const (
+	AccessTokenLifetime  = 24*time.Hour
+	RefreshTokenLifetime = 24*7*time.Hour
+)
+
+// Signing & verification keys (RS256/EdDSA, picked by kid), set up in main()
+var keys *keystore.Store
+
+func SetKeystore(store *keystore.Store){
+	keys = store
+}
 
-// FIX: load env before using keys (in init)
 func init() {
-	JWT_SECRET_KEY = os.Getenv("JWT_SECRET_KEY")
-	JWT_REFRESH_SECRET_KEY = os.Getenv("JWT_REFRESH_SECRET_KEY")
+	// iat/exp in ms, so a sign-out cutoff doesn't also catch the tokens issued right after it (same second)
+	jwt.TimePrecision = time.Millisecond
 }
 
 
-func GenerateAllTokens(email,firstName, lastName, role, userId string)(string,string,error){
+// Random ID for sessions & token jti's
+func NewTokenID()string{
+	b:=make([]byte,16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Signs with the current key of the keystore, its kid goes into the header
+func signToken(claims jwt.Claims)(string,error){
+	if keys==nil{
+		return "",errors.New("keystore not set up")
+	}
+	key,err:=keys.SigningKey()
+	if err!=nil{
+		return "",err
+	}
+
+	token:=jwt.NewWithClaims(key.Method(),claims)
+	token.Header["kid"]=key.ID
+	return token.SignedString(key.Private)
+}
+
+// Picks the verification key by kid. Tokens without one are HS256 tokens from before the keystore,
+// only accepted with JWT_ACCEPT_LEGACY_HS256=true (while they run out), checked with legacySecretEnv
+func parseToken(tokenStr,tokenUse,legacySecretEnv string)(*SignedDetails,error){
+	claims:=&SignedDetails{}
+	legacy:=false
+
+	token,err:=jwt.ParseWithClaims(tokenStr,claims,func(token *jwt.Token)(interface{}, error){
+		kid,_:=token.Header["kid"].(string)
+		if kid==""{
+			secret:=os.Getenv(legacySecretEnv)
+			if os.Getenv("JWT_ACCEPT_LEGACY_HS256")!="true" || secret==""{
+				return nil,errors.New("token has no kid")
+			}
+			if _,ok:=token.Method.(*jwt.SigningMethodHMAC); !ok{
+				return nil,errors.New("unexpected signing method")
+			}
+			legacy = true
+			return []byte(secret),nil
+		}
+		return verificationKey(token)
+	},jwt.WithValidMethods([]string{keystore.AlgRS256,keystore.AlgEdDSA,jwt.SigningMethodHS256.Alg()}))
+
+	if err != nil { // FIX: check err first
+		return nil, err
+	}
+
+	if !token.Valid { // FIX
+		return nil, errors.New("invalid token")
+	}
+
+	// An access-token must not pass as a refresh-token & vice versa (legacy ones had separate secrets instead)
+	if !legacy && claims.TokenUse!=tokenUse{
+		return nil, errors.New("wrong token type")
+	}
+
+	if claims.ExpiresAt==nil || claims.ExpiresAt.Time.Before(time.Now()) {
+		return nil, errors.New("⚠️ Token has expired!")
+	}
+
+	return claims,nil
+}
+
+// Keystore key for a token with a kid
+func verificationKey(token *jwt.Token)(interface{}, error){
+	kid,_:=token.Header["kid"].(string)
+	if keys==nil{
+		return nil,errors.New("keystore not set up")
+	}
+	key,err:=keys.VerificationKey(kid)
+	if err!=nil{
+		return nil,err
+	}
+	// FIX: the alg has to be the key's, never what the token claims
+	if token.Method.Alg()!=key.Alg{
+		return nil,errors.New("unexpected signing method")
+	}
+	return key.Public(),nil
+}
+
+func GenerateAllTokens(email,firstName, lastName, role, userId, sessionId, refreshTokenId string)(string,string,error){
 
 	// First, access-token
 	claims:=&SignedDetails{
@@ -42,16 +140,17 @@ func GenerateAllTokens(email,firstName, lastName, role, userId string)(string,st
 		LastName: lastName,
 		Role:role,
 		UserId: userId,
+		SessionId: sessionId,
+		TokenUse: TokenUseAccess,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID: NewTokenID(), // for the revocation-denylist
 			Issuer:"MagikStream",
 			IssuedAt: jwt.NewNumericDate(time.Now()),
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24*time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenLifetime)),
 		},
 	}
 
-	token:=jwt.NewWithClaims(jwt.SigningMethodHS256,claims)
-	signedToken,err:=token.SignedString([]byte(JWT_SECRET_KEY))
-
+	signedToken,err:=signToken(claims)
 
 	if err!=nil{
 		log.Println("⚠️ERROR:",err.Error())
@@ -65,15 +164,17 @@ func GenerateAllTokens(email,firstName, lastName, role, userId string)(string,st
 		LastName: lastName,
 		Role:role,
 		UserId: userId,
+		SessionId: sessionId,
+		TokenUse: TokenUseRefresh,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID: refreshTokenId,
 			Issuer:"MagikStream",
 			IssuedAt: jwt.NewNumericDate(time.Now()),
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24*7*time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(RefreshTokenLifetime)),
 		},
 	}
 
-	refreshToken:= jwt.NewWithClaims(jwt.SigningMethodHS256,refreshClaims)
-	signedRefreshToken,err:=refreshToken.SignedString([]byte(JWT_REFRESH_SECRET_KEY))
+	signedRefreshToken,err:=signToken(refreshClaims)
 
 	if err!=nil{
 		log.Println("⚠️ERROR:",err.Error())
@@ -83,82 +184,44 @@ func GenerateAllTokens(email,firstName, lastName, role, userId string)(string,st
 	return signedToken, signedRefreshToken, nil
 }
 
-func UpdateAllTokens(userId,token,refreshToken string,client *mongo.Client)(err error){
-	var ctx,cancel= context.WithTimeout(context.Background(),100*time.Second)
-	defer cancel()
-
-	updatedAt,_:=time.Parse(time.RFC3339,time.Now().Format(time.RFC3339))
+// Credential types AuthMiddleware accepts (recorded in the ctx as "authMethod")
+const (
+	AuthMethodCookie = "cookie"
+	AuthMethodBearer = "bearer"
+	AuthMethodAPIKey = "api_key"
+)
 
-	updatedData:=bson.M{
-		"$set": bson.M{
-			"token":token,
-			"refresh_token":refreshToken,
-			"updated_at":updatedAt,
-		},
+// Get/extract the credential of a request (for auth-mw): Authorization-header (JWT or API-key), X-API-Key header, or the "token" cookie
+func GetCredential(ctx *gin.Context)(string,string,error){
+	if authHeader:=ctx.Request.Header.Get("Authorization"); authHeader!=""{
+		// FIX: prevent slice out-of-range panic
+		if !strings.HasPrefix(authHeader,"Bearer "){
+			return "","",errors.New("invalid authorization format")
+		}
+		tokenStr:=strings.TrimSpace(authHeader[len("Bearer "):]) // exclude "Bearer "
+		if tokenStr==""{
+			return "","",errors.New("⚠️ Bearer token is required!")
+		}
+		if strings.HasPrefix(tokenStr,models.APIKeyPrefix){
+			return AuthMethodAPIKey,tokenStr,nil
+		}
+		return AuthMethodBearer,tokenStr,nil
 	}
 
-	// Open collection
-   var userCollection *mongo.Collection = database.OpenCollection("users",client)
-	_,err=userCollection.UpdateOne(ctx, bson.M{"user_id":userId},updatedData)
-	if err!=nil{
-		log.Println("⚠️ERROR:",err.Error())
-		return err
+	if apiKey:=ctx.Request.Header.Get("X-API-Key"); apiKey!=""{
+		return AuthMethodAPIKey,apiKey,nil
 	}
-	return nil
-}
-
-// Get/extract access-token from the req-header. (for auth-mw)
-func GetAccessToken(ctx *gin.Context)(string, error){
-	// authHeader:=ctx.Request.Header.Get("Authorization")
-	
-	// if authHeader==""{
-	// 	return "",errors.New("⚠️ Authorization-header is required!")
-	// }
-
-	// // FIX: prevent slice out-of-range panic
-	// if !strings.HasPrefix(authHeader, "Bearer ") {
-	// return "", errors.New("invalid authorization format")
-	// }
-
-	// tokenStr:= authHeader[len("Bearer "):] // exclude "Bearer "
-
-	// if tokenStr==""{
-	// 	return "",errors.New("⚠️ Bearer token is required!")
-	// }
 
 	tokenStr,err:=ctx.Cookie("token")
 	if err!=nil{
-		return  "",err
+		return "","",err
 	}
-
-	return tokenStr,nil
+	return AuthMethodCookie,tokenStr,nil
 }
 
 // Validate the token (for auth-mw)
 func ValidateToken(tokenStr string)(*SignedDetails,error){
-claims:=&SignedDetails{}
-
-token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token)(interface{}, error){
-	return []byte(JWT_SECRET_KEY), nil
-})
-
-if err != nil { // FIX: check err first
-	return nil, err
-}
-
-if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok { // FIX
-	return nil, errors.New("unexpected signing method")
-}
-
-if !token.Valid { // FIX
-	return nil, errors.New("invalid token")
-}
-
-if claims.ExpiresAt.Time.Before(time.Now()) {
-	return nil, errors.New("⚠️ Token has expired!")
-}
-
-return claims,nil
+	return parseToken(tokenStr,TokenUseAccess,"JWT_SECRET_KEY")
 }
 
 // for GetRecommendedMoviesHandler()
@@ -177,6 +240,30 @@ func GetUserIdFromCtx(ctx *gin.Context)(string,error){
 	return id,nil
 }
 
+// for the session-handlers (empty for tokens issued before sessions existed)
+func GetSessionIdFromCtx(ctx *gin.Context)string{
+	sessionId,_:=ctx.Get("sessionId")
+	id,_:=sessionId.(string)
+	return id
+}
+
+// Which credential authenticated the request (AuthMethod*)
+func GetAuthMethodFromCtx(ctx *gin.Context)string{
+	method,_:=ctx.Get("authMethod")
+	m,_:=method.(string)
+	return m
+}
+
+// Scopes of the API-key that authenticated the request, ok=false for JWTs (no scoping)
+func GetScopesFromCtx(ctx *gin.Context)([]string,bool){
+	scopes,exists:=ctx.Get("scopes")
+	if !exists{
+		return nil,false
+	}
+	s,ok:=scopes.([]string)
+	return s,ok
+}
+
 // for AdminReviewUpdateHandler()
 func GetRoleFromCtx(ctx *gin.Context)(string,error){
 	role, exists:= ctx.Get("role")
@@ -194,23 +281,5 @@ func GetRoleFromCtx(ctx *gin.Context)(string,error){
 }
 
 func ValidateRefreshToken(tokenString string) (*SignedDetails, error) {
-	claims := &SignedDetails{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-
-		return []byte(JWT_REFRESH_SECRET_KEY), nil
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-		return nil, err
-	}
-
-	if claims.ExpiresAt.Time.Before(time.Now()) {
-		return nil, errors.New("refresh token has expired")
-	}
-
-	return claims, nil
-}
\ No newline at end of file
+	return parseToken(tokenString, TokenUseRefresh, "JWT_REFRESH_SECRET_KEY")
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"context"
+	"log"
+
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/models"
+	"github.com/iamskyy666/MagikStreamMovies/server/magikStreamMoviesServer/repository"
+)
+
+// countedRating is what a review counts for in the movie's user_rating (hidden ones don't count)
+func countedRating(review *models.UserReview) int {
+	if review == nil || review.Hidden {
+		return 0
+	}
+	return review.Rating
+}
+
+// countedSentiment is the ranking_value a review counts for in the movie's audience_sentiment (0 = not counted)
+func countedSentiment(review *models.UserReview) int {
+	if review == nil || review.Hidden || review.Sentiment == nil {
+		return 0
+	}
+	return review.Sentiment.RankingValue
+}
+
+// UpdateReviewAggregates keeps the movie's user_rating & audience_sentiment in step with a review going from before
+// to after (nil = no review). The review is already written, so a failure is only logged
+func UpdateReviewAggregates(ctx context.Context, movies repository.MovieRepository, imdbID string, before, after *models.UserReview) {
+	rating := repository.RatingChange{Added: countedRating(after), Removed: countedRating(before)}
+	if rating.Added != rating.Removed {
+		if err := movies.UpdateUserRating(ctx, imdbID, rating); err != nil {
+			log.Printf("⚠️ ERROR updating the user-rating of %s (%+v) --- %v", imdbID, rating, err)
+		}
+	}
+
+	sentiment := repository.RatingChange{Added: countedSentiment(after), Removed: countedSentiment(before)}
+	if sentiment.Added != sentiment.Removed {
+		if err := movies.UpdateAudienceSentiment(ctx, imdbID, sentiment); err != nil {
+			log.Printf("⚠️ ERROR updating the audience-sentiment of %s (%+v) --- %v", imdbID, sentiment, err)
+		}
+	}
+}